@@ -1,15 +1,14 @@
 package ai
 
 import (
-	"bufio"
-	"bytes"
 	"context"
 	"encoding/base64"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"unicode/utf8"
 )
 
 const (
@@ -30,19 +29,42 @@ type ClientConfig struct {
 	HTTPClient     *http.Client
 	VisionAPIKey   string
 	VisionBaseURL  string
+	Provider       string // 翻译接口协议适配器，留空为 OpenAI 兼容协议
+	VisionProvider string // 视觉接口协议适配器，留空时沿用 Provider
+	Retry          RetryPolicy
+	OnUsage        UsageObserver
+	Timeouts       Timeouts
+	// DetectRefusals 为 true 时按常见拒答句式识别文本翻译的较短输出，并作为错误返回；视觉请求与原文本身含拒答句式时不做识别
+	DetectRefusals bool
+	// FallbackOnBlocked 为 true 时，主接口的输出被过滤或拒答后在首个备用接口上重试一次
+	FallbackOnBlocked bool
+	// TranslateFallbacks 与 VisionFallbacks 为按顺序尝试的备用接口
+	TranslateFallbacks []EndpointConfig
+	VisionFallbacks    []EndpointConfig
 }
 
 type endpoint struct {
-	apiKey string
-	base   string
-	model  string
+	name     string
+	apiKey   string
+	base     string
+	model    string
+	provider Provider
 }
 
-// Client 表示一个兼容 OpenAI Chat Completions 的客户端
+// Client 表示一个通用的对话模型客户端，具体协议由 Provider 适配
 type Client struct {
 	translate  endpoint
 	vision     endpoint
 	httpClient *http.Client
+	retry      RetryPolicy
+	onUsage    UsageObserver
+	timeouts   Timeouts
+
+	detectRefusals    bool
+	fallbackOnBlocked bool
+
+	translateFallbacks []endpoint
+	visionFallbacks    []endpoint
 }
 
 // NormalizeBaseURL 处理用户输入的 BaseURL，保证格式统一
@@ -73,9 +95,13 @@ func normalizeBaseURLOrFallback(value string, fallback string) string {
 // NewClient 创建一个新的通用 AI 客户端
 func NewClient(cfg ClientConfig) *Client {
 	baseURL := NormalizeBaseURL(cfg.BaseURL)
+	timeouts := cfg.Timeouts
 	httpClient := cfg.HTTPClient
 	if httpClient == nil {
-		httpClient = &http.Client{}
+		httpClient = newHTTPClient(timeouts.Connect)
+	} else {
+		// 自定义 HTTPClient 自行负责连接超时
+		timeouts.Connect = 0
 	}
 
 	translateAPIKey := strings.TrimSpace(cfg.APIKey)
@@ -86,18 +112,37 @@ func NewClient(cfg ClientConfig) *Client {
 
 	visionBase := normalizeBaseURLOrFallback(cfg.VisionBaseURL, baseURL)
 
+	visionProvider := cfg.VisionProvider
+	if strings.TrimSpace(visionProvider) == "" {
+		visionProvider = cfg.Provider
+	}
+
+	translate := endpoint{
+		name:     EndpointTranslate,
+		apiKey:   translateAPIKey,
+		base:     baseURL,
+		model:    normalizeModel(cfg.TranslateModel, DefaultTranslateModel),
+		provider: NewProvider(cfg.Provider),
+	}
+	vision := endpoint{
+		name:     EndpointVision,
+		apiKey:   visionAPIKey,
+		base:     visionBase,
+		model:    normalizeModel(cfg.VisionModel, DefaultVisionModel),
+		provider: NewProvider(visionProvider),
+	}
+
 	return &Client{
-		translate: endpoint{
-			apiKey: translateAPIKey,
-			base:   baseURL,
-			model:  normalizeModel(cfg.TranslateModel, DefaultTranslateModel),
-		},
-		vision: endpoint{
-			apiKey: visionAPIKey,
-			base:   visionBase,
-			model:  normalizeModel(cfg.VisionModel, DefaultVisionModel),
-		},
-		httpClient: httpClient,
+		translate:          translate,
+		vision:             vision,
+		httpClient:         httpClient,
+		retry:              normalizeRetryPolicy(cfg.Retry),
+		onUsage:            cfg.OnUsage,
+		timeouts:           timeouts,
+		detectRefusals:     cfg.DetectRefusals,
+		fallbackOnBlocked:  cfg.FallbackOnBlocked,
+		translateFallbacks: newFallbackEndpoints(translate, cfg.TranslateFallbacks),
+		visionFallbacks:    newFallbackEndpoints(vision, cfg.VisionFallbacks),
 	}
 }
 
@@ -126,21 +171,34 @@ func (c *Client) VisionBaseURL() string {
 	return c.vision.base
 }
 
-func (c *Client) chatCompletionsURL(base string) string {
-	return base + "/chat/completions"
+// TranslateProvider 返回翻译接口使用的协议适配器名称
+func (c *Client) TranslateProvider() string {
+	return c.translate.provider.Name()
+}
+
+// VisionProvider 返回视觉接口使用的协议适配器名称
+func (c *Client) VisionProvider() string {
+	return c.vision.provider.Name()
 }
 
 // ZhipuAIRequest 表示发送给兼容接口的请求结构
 // 保留原命名以避免大规模重构
 // TODO: 未来可考虑重命名为 ChatCompletionRequest
 type ZhipuAIRequest struct {
-	Model       string                 `json:"model"`
-	Messages    []Message              `json:"messages"`
-	Temperature float64                `json:"temperature,omitempty"`
-	MaxTokens   int                    `json:"max_tokens,omitempty"`
-	Stream      bool                   `json:"stream,omitempty"`
-	TopP        float64                `json:"top_p,omitempty"`
-	OtherParams map[string]interface{} `json:"other_params,omitempty"`
+	Model            string    `json:"model"`
+	Messages         []Message `json:"messages"`
+	Temperature      *float64  `json:"temperature,omitempty"`
+	MaxTokens        int       `json:"max_tokens,omitempty"`
+	Stream           bool      `json:"stream,omitempty"`
+	TopP             *float64  `json:"top_p,omitempty"`
+	PresencePenalty  *float64  `json:"presence_penalty,omitempty"`
+	FrequencyPenalty *float64  `json:"frequency_penalty,omitempty"`
+	Seed             *int64    `json:"seed,omitempty"`
+	Stop             []string  `json:"stop,omitempty"`
+	// StreamOptions 由 OpenAI 兼容协议在流式请求时填充，要求末尾分块返回用量
+	StreamOptions *StreamOptions `json:"stream_options,omitempty"`
+	// ExtraBody 由各协议适配器合并到请求体顶层，不直接序列化
+	ExtraBody map[string]interface{} `json:"-"`
 }
 
 // ContentItem 表示消息内容项，可以是文本或图像
@@ -156,6 +214,10 @@ type ContentItem struct {
 type Message struct {
 	Role    string      `json:"role"`
 	Content interface{} `json:"content"` // 可以是string（文本）或[]ContentItem（多模态）
+	// ReasoningContent 为推理模型输出的思考过程，响应中已从 Content 分离
+	ReasoningContent string `json:"reasoning_content,omitempty"`
+	// Refusal 为服务商单独返回的拒答说明（OpenAI 的 refusal 字段）
+	Refusal string `json:"refusal,omitempty"`
 }
 
 // ZhipuAIResponse 表示兼容接口的响应结构
@@ -166,6 +228,10 @@ type ZhipuAIResponse struct {
 	Choices []Choice  `json:"choices"`
 	Usage   Usage     `json:"usage"`
 	Error   *APIError `json:"error,omitempty"`
+	// ServedBy 记录实际应答的接口（主接口或备用接口），不参与序列化
+	ServedBy ServedBy `json:"-"`
+
+	continuation *continuation
 }
 
 // Choice 表示响应中的选择项
@@ -175,6 +241,11 @@ type Choice struct {
 	FinishReason string  `json:"finish_reason"`
 }
 
+// StreamOptions 表示 OpenAI 兼容协议的流式选项
+type StreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
 // Usage 表示token使用情况
 type Usage struct {
 	PromptTokens     int `json:"prompt_tokens"`
@@ -182,237 +253,223 @@ type Usage struct {
 	TotalTokens      int `json:"total_tokens"`
 }
 
-// APIError 表示API错误
+// APIError 表示响应体中携带的API错误
 type APIError struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 	Type    string `json:"type"`
 }
 
-type streamChunk struct {
-	ID      string         `json:"id"`
-	Object  string         `json:"object"`
-	Created int64          `json:"created"`
-	Model   string         `json:"model"`
-	Choices []streamChoice `json:"choices"`
-	Usage   *Usage         `json:"usage,omitempty"`
-	Error   *APIError      `json:"error,omitempty"`
-}
-
-type streamChoice struct {
-	Index        int         `json:"index"`
-	Delta        streamDelta `json:"delta"`
-	FinishReason string      `json:"finish_reason"`
-}
-
-type streamDelta struct {
-	Role    string      `json:"role"`
-	Content interface{} `json:"content"`
-}
-
-func (c *Client) post(ctx context.Context, request ZhipuAIRequest, target endpoint) (*ZhipuAIResponse, error) {
-	url := c.chatCompletionsURL(target.base)
-
-	jsonData, err := json.Marshal(request)
+func (c *Client) send(ctx context.Context, request ZhipuAIRequest, target endpoint) (*http.Response, error) {
+	req, err := target.provider.NewRequest(ctx, target.base, target.apiKey, request)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %v", err)
+		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+		return nil, newTransportError(target.provider, c.connectError(ctx, err))
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	if target.apiKey != "" {
-		req.Header.Set("Authorization", "Bearer "+target.apiKey)
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, newStatusError(target.provider, resp, body)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	return resp, nil
+}
+
+func (c *Client) post(ctx context.Context, stage string, request ZhipuAIRequest, r route) (*ZhipuAIResponse, error) {
+	request.Stream = false
+
+	response, err := c.withFallback(ctx, r, request, func(target endpoint, request ZhipuAIRequest) (*ZhipuAIResponse, bool, error) {
+		var response *ZhipuAIResponse
+		err := c.retry.run(ctx, func() error {
+			var err error
+			response, err = c.postOnce(ctx, request, target)
+			return err
+		}, nil)
+		if err != nil {
+			return nil, false, err
+		}
+		c.reportUsage(target, stage, response)
+		if err := c.blockedError(target.provider, stage, request, response); err != nil {
+			return nil, false, err
+		}
+		return response, false, nil
+	})
+	setTimeoutStage(err, stage)
+	rememberContinuation(response, stage, r.primary, request)
+	return response, err
+}
+
+func (c *Client) postOnce(ctx context.Context, request ZhipuAIRequest, target endpoint) (*ZhipuAIResponse, error) {
+	resp, err := c.send(ctx, request, target)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %v", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
-	var response ZhipuAIResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+		return nil, newTransportError(target.provider, fmt.Errorf("failed to read response: %w", err))
 	}
 
-	if response.Error != nil {
-		return nil, fmt.Errorf("API error: %s - %s", response.Error.Code, response.Error.Message)
+	response, err := target.provider.DecodeResponse(body)
+	if err != nil {
+		return nil, err
 	}
-
-	return &response, nil
+	separateReasoning(response)
+	return response, nil
 }
 
-func (c *Client) stream(ctx context.Context, request ZhipuAIRequest, target endpoint, onDelta func(string)) (*ZhipuAIResponse, error) {
-	url := c.chatCompletionsURL(target.base)
+func (c *Client) stream(ctx context.Context, stage string, request ZhipuAIRequest, r route, onEvent StreamHandler) (*ZhipuAIResponse, error) {
 	request.Stream = true
 
-	jsonData, err := json.Marshal(request)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %v", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
-	}
+	// discarded 表示之前的接口已推送过被拦截的内容，切换接口后的首个事件需标记 Reset
+	discarded := false
+	response, err := c.withFallback(ctx, r, request, func(target endpoint, request ZhipuAIRequest) (*ZhipuAIResponse, bool, error) {
+		// 一旦有正文或推理增量推送给调用方就不再重试，避免界面出现重复内容
+		delivered := false
+		var response *ZhipuAIResponse
+		err := c.retry.run(ctx, func() error {
+			var err error
+			response, err = c.streamOnce(ctx, request, target, func(event StreamEvent) {
+				if event.Delta != "" || event.Reasoning != "" {
+					delivered = true
+				}
+				if discarded {
+					event.Reset = true
+					discarded = false
+				}
+				if onEvent != nil {
+					onEvent(event)
+				}
+			})
+			return err
+		}, func() bool {
+			return !delivered
+		})
+		if err != nil {
+			return nil, delivered, err
+		}
+		c.reportUsage(target, stage, response)
+		if err := c.blockedError(target.provider, stage, request, response); err != nil {
+			discarded = discarded || delivered
+			return nil, delivered, err
+		}
+		return response, delivered, nil
+	})
+	setTimeoutStage(err, stage)
+	rememberContinuation(response, stage, r.primary, request)
+	return response, err
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	if target.apiKey != "" {
-		req.Header.Set("Authorization", "Bearer "+target.apiKey)
-	}
+func (c *Client) streamOnce(ctx context.Context, request ZhipuAIRequest, target endpoint, onEvent StreamHandler) (*ZhipuAIResponse, error) {
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	watchdog := startStreamWatchdog(c.timeouts, cancel)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(watchCtx, request, target)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
+		if timeoutErr := watchdog.stop(); timeoutErr != nil && ctx.Err() == nil {
+			return nil, newTransportError(target.provider, timeoutErr)
+		}
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
-	scanner := bufio.NewScanner(resp.Body)
-	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
-
 	var (
 		builder      strings.Builder
+		reasoning    strings.Builder
+		refusal      strings.Builder
+		think        thinkParser
+		length       int
 		final        ZhipuAIResponse
 		finishReason string
 		sawChunk     bool
-		dataBuffer   strings.Builder
 	)
 
-	processData := func(data string) error {
-		if err := ctx.Err(); err != nil {
-			return err
-		}
-		if strings.TrimSpace(data) == "" {
-			return nil
-		}
-		if data == "[DONE]" {
-			return io.EOF
+	deliver := func(answer, reasoningDelta, reason string, usage *Usage) {
+		builder.WriteString(answer)
+		reasoning.WriteString(reasoningDelta)
+		length += utf8.RuneCountInString(answer)
+		if answer == "" && reasoningDelta == "" && reason == "" && usage == nil {
+			return
 		}
+		onEvent(StreamEvent{
+			Delta:        answer,
+			Reasoning:    reasoningDelta,
+			Length:       length,
+			FinishReason: reason,
+			Usage:        usage,
+		})
+	}
 
-		var chunk streamChunk
-		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
-			return fmt.Errorf("failed to unmarshal stream chunk: %v (raw: %s)", err, data)
+	err = target.provider.DecodeStream(resp.Body, func(part StreamPart) error {
+		if err := watchCtx.Err(); err != nil {
+			return err
 		}
-		if chunk.Error != nil {
-			return fmt.Errorf("API error: %s - %s", chunk.Error.Code, chunk.Error.Message)
+		if part.ID != "" && final.ID == "" {
+			final.ID = part.ID
 		}
-		if chunk.ID != "" && final.ID == "" {
-			final.ID = chunk.ID
+		if part.Object != "" && final.Object == "" {
+			final.Object = part.Object
 		}
-		if chunk.Object != "" && final.Object == "" {
-			final.Object = chunk.Object
+		if part.Created != 0 && final.Created == 0 {
+			final.Created = part.Created
 		}
-		if chunk.Created != 0 && final.Created == 0 {
-			final.Created = chunk.Created
+		if part.Usage != nil {
+			final.Usage = *part.Usage
 		}
-		if chunk.Usage != nil {
-			final.Usage = *chunk.Usage
+		if part.FinishReason != "" {
+			finishReason = part.FinishReason
 		}
 
-		for _, choice := range chunk.Choices {
-			text := streamContentToString(choice.Delta.Content)
-			if text != "" {
-				builder.WriteString(text)
-				if onDelta != nil && ctx.Err() == nil {
-					onDelta(builder.String())
-				}
-			}
-			if choice.FinishReason != "" {
-				finishReason = choice.FinishReason
-			}
-		}
+		refusal.WriteString(part.Refusal)
 
 		sawChunk = true
-		return nil
-	}
-
-	for {
-		if err := ctx.Err(); err != nil {
-			return nil, err
-		}
-		if !scanner.Scan() {
-			break
-		}
-		line := scanner.Text()
-		line = strings.TrimRight(line, "\r")
-
-		if strings.HasPrefix(line, ":") {
-			continue
+		watchdog.observe(part.Content != "" || part.Reasoning != "" || part.Refusal != "")
+		answer, thought := think.feed(part.Content)
+		if part.FinishReason != "" {
+			restAnswer, restThought := think.flush()
+			answer += restAnswer
+			thought += restThought
 		}
-
-		if strings.HasPrefix(line, "data:") {
-			segment := strings.TrimSpace(line[len("data:"):])
-			if segment == "[DONE]" {
-				if err := processData(segment); err == io.EOF {
-					break
-				}
-				break
-			}
-			if dataBuffer.Len() > 0 {
-				dataBuffer.WriteByte('\n')
-			}
-			dataBuffer.WriteString(segment)
-			continue
-		}
-
-		if strings.TrimSpace(line) == "" {
-			if dataBuffer.Len() == 0 {
-				continue
-			}
-			payload := dataBuffer.String()
-			dataBuffer.Reset()
-			if err := processData(payload); err != nil {
-				if err == io.EOF {
-					break
-				}
-				return nil, err
-			}
-			continue
-		}
-	}
-
-	if err := scanner.Err(); err != nil && err != io.EOF {
-		return nil, fmt.Errorf("failed to read stream: %v", err)
+		deliver(answer, part.Reasoning+thought, part.FinishReason, part.Usage)
+		return nil
+	})
+	timeoutErr := watchdog.stop()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
 	}
-
-	if err := ctx.Err(); err != nil {
-		return nil, err
+	if timeoutErr != nil && err != nil {
+		timeoutErr.Partial = builder.String()
+		return nil, newTransportError(target.provider, timeoutErr)
 	}
-
-	if dataBuffer.Len() > 0 {
-		if err := processData(dataBuffer.String()); err != nil && err != io.EOF {
-			return nil, err
+	if err != nil {
+		var requestErr *RequestError
+		if !errors.As(err, &requestErr) && (errors.Is(err, io.ErrUnexpectedEOF) || isNetError(err)) {
+			err = newTransportError(target.provider, err)
 		}
+		return nil, err
 	}
 
 	if !sawChunk {
 		return nil, fmt.Errorf("stream response empty")
 	}
+	restAnswer, restThought := think.flush()
+	deliver(restAnswer, restThought, "", nil)
 
 	final.Choices = []Choice{
 		{
 			Index: 0,
 			Message: Message{
-				Role:    "assistant",
-				Content: builder.String(),
+				Role:             "assistant",
+				Content:          builder.String(),
+				ReasoningContent: reasoning.String(),
+				Refusal:          refusal.String(),
 			},
 			FinishReason: finishReason,
 		},
@@ -427,7 +484,7 @@ func (c *Client) Translate(userMessage string, systemPrompt string) (*ZhipuAIRes
 }
 
 // TranslateWithContext 发送文本消息至聊天接口（可取消）
-func (c *Client) TranslateWithContext(ctx context.Context, userMessage string, systemPrompt string) (*ZhipuAIResponse, error) {
+func (c *Client) TranslateWithContext(ctx context.Context, userMessage string, systemPrompt string, opts ...RequestOption) (*ZhipuAIResponse, error) {
 	messages := []Message{}
 
 	if systemPrompt != "" {
@@ -445,20 +502,21 @@ func (c *Client) TranslateWithContext(ctx context.Context, userMessage string, s
 	request := ZhipuAIRequest{
 		Model:       c.translate.model,
 		Messages:    messages,
-		Temperature: 1,
-		TopP:        0.9,
+		Temperature: Float64(1),
+		TopP:        Float64(0.9),
 	}
+	applyOptions(&request, opts)
 
-	return c.post(ctx, request, c.translate)
+	return c.post(ctx, StageTranslate, request, routeFrom(c.translate))
 }
 
 // TranslateStream 以流式方式发送文本消息并回调增量内容
-func (c *Client) TranslateStream(userMessage string, systemPrompt string, onDelta func(string)) (*ZhipuAIResponse, error) {
-	return c.TranslateStreamWithContext(context.Background(), userMessage, systemPrompt, onDelta)
+func (c *Client) TranslateStream(userMessage string, systemPrompt string, onEvent StreamHandler) (*ZhipuAIResponse, error) {
+	return c.TranslateStreamWithContext(context.Background(), userMessage, systemPrompt, onEvent)
 }
 
 // TranslateStreamWithContext 以流式方式发送文本消息并回调增量内容（可取消）
-func (c *Client) TranslateStreamWithContext(ctx context.Context, userMessage string, systemPrompt string, onDelta func(string)) (*ZhipuAIResponse, error) {
+func (c *Client) TranslateStreamWithContext(ctx context.Context, userMessage string, systemPrompt string, onEvent StreamHandler, opts ...RequestOption) (*ZhipuAIResponse, error) {
 	messages := []Message{}
 
 	if systemPrompt != "" {
@@ -476,11 +534,12 @@ func (c *Client) TranslateStreamWithContext(ctx context.Context, userMessage str
 	request := ZhipuAIRequest{
 		Model:       c.translate.model,
 		Messages:    messages,
-		Temperature: 1,
-		TopP:        0.9,
+		Temperature: Float64(1),
+		TopP:        Float64(0.9),
 	}
+	applyOptions(&request, opts)
 
-	return c.stream(ctx, request, c.translate, onDelta)
+	return c.stream(ctx, StageTranslate, request, routeFrom(c.translate), onEvent)
 }
 
 // ImageToWords 直接从图像字节数据提取文字
@@ -489,15 +548,29 @@ func (c *Client) ImageToWords(userMessage string, imageData []byte, mimeType str
 }
 
 // ImageToWordsWithContext 直接从图像字节数据提取文字（可取消）
-func (c *Client) ImageToWordsWithContext(ctx context.Context, userMessage string, imageData []byte, mimeType string, systemPrompt string) (*ZhipuAIResponse, error) {
+func (c *Client) ImageToWordsWithContext(ctx context.Context, userMessage string, imageData []byte, mimeType string, systemPrompt string, opts ...RequestOption) (*ZhipuAIResponse, error) {
 	request := ZhipuAIRequest{
 		Model:       c.vision.model,
 		Messages:    c.buildVisionMessages(userMessage, imageData, mimeType, systemPrompt),
-		Temperature: 0.7,
-		TopP:        0.9,
+		Temperature: Float64(0.7),
+		TopP:        Float64(0.9),
 	}
+	applyOptions(&request, opts)
 
-	return c.post(ctx, request, c.vision)
+	return c.post(ctx, StageOCR, request, routeFrom(c.vision))
+}
+
+// ImageToWordsStreamWithContext 直接从图像字节数据流式提取文字（可取消）
+func (c *Client) ImageToWordsStreamWithContext(ctx context.Context, userMessage string, imageData []byte, mimeType string, systemPrompt string, onEvent StreamHandler, opts ...RequestOption) (*ZhipuAIResponse, error) {
+	request := ZhipuAIRequest{
+		Model:       c.vision.model,
+		Messages:    c.buildVisionMessages(userMessage, imageData, mimeType, systemPrompt),
+		Temperature: Float64(0.7),
+		TopP:        Float64(0.9),
+	}
+	applyOptions(&request, opts)
+
+	return c.stream(ctx, StageOCR, request, routeFrom(c.vision), onEvent)
 }
 
 // ImageToTranslation 使用视觉模型直接生成翻译结果
@@ -506,32 +579,34 @@ func (c *Client) ImageToTranslation(userMessage string, imageData []byte, mimeTy
 }
 
 // ImageToTranslationWithContext 使用视觉模型直接生成翻译结果（可取消）
-func (c *Client) ImageToTranslationWithContext(ctx context.Context, userMessage string, imageData []byte, mimeType string, systemPrompt string) (*ZhipuAIResponse, error) {
+func (c *Client) ImageToTranslationWithContext(ctx context.Context, userMessage string, imageData []byte, mimeType string, systemPrompt string, opts ...RequestOption) (*ZhipuAIResponse, error) {
 	request := ZhipuAIRequest{
 		Model:       c.vision.model,
 		Messages:    c.buildVisionMessages(userMessage, imageData, mimeType, systemPrompt),
-		Temperature: 0.7,
-		TopP:        0.9,
+		Temperature: Float64(0.7),
+		TopP:        Float64(0.9),
 	}
+	applyOptions(&request, opts)
 
-	return c.post(ctx, request, c.vision)
+	return c.post(ctx, StageDirect, request, routeFrom(c.vision))
 }
 
 // ImageToTranslationStream 使用视觉模型流式输出翻译结果
-func (c *Client) ImageToTranslationStream(userMessage string, imageData []byte, mimeType string, systemPrompt string, onDelta func(string)) (*ZhipuAIResponse, error) {
-	return c.ImageToTranslationStreamWithContext(context.Background(), userMessage, imageData, mimeType, systemPrompt, onDelta)
+func (c *Client) ImageToTranslationStream(userMessage string, imageData []byte, mimeType string, systemPrompt string, onEvent StreamHandler) (*ZhipuAIResponse, error) {
+	return c.ImageToTranslationStreamWithContext(context.Background(), userMessage, imageData, mimeType, systemPrompt, onEvent)
 }
 
 // ImageToTranslationStreamWithContext 使用视觉模型流式输出翻译结果（可取消）
-func (c *Client) ImageToTranslationStreamWithContext(ctx context.Context, userMessage string, imageData []byte, mimeType string, systemPrompt string, onDelta func(string)) (*ZhipuAIResponse, error) {
+func (c *Client) ImageToTranslationStreamWithContext(ctx context.Context, userMessage string, imageData []byte, mimeType string, systemPrompt string, onEvent StreamHandler, opts ...RequestOption) (*ZhipuAIResponse, error) {
 	request := ZhipuAIRequest{
 		Model:       c.vision.model,
 		Messages:    c.buildVisionMessages(userMessage, imageData, mimeType, systemPrompt),
-		Temperature: 0.7,
-		TopP:        0.9,
+		Temperature: Float64(0.7),
+		TopP:        Float64(0.9),
 	}
+	applyOptions(&request, opts)
 
-	return c.stream(ctx, request, c.vision, onDelta)
+	return c.stream(ctx, StageDirect, request, routeFrom(c.vision), onEvent)
 }
 
 func (c *Client) buildVisionMessages(userMessage string, imageData []byte, mimeType string, systemPrompt string) []Message {
@@ -571,25 +646,3 @@ func (c *Client) buildVisionMessages(userMessage string, imageData []byte, mimeT
 
 	return messages
 }
-
-func streamContentToString(content interface{}) string {
-	switch value := content.(type) {
-	case string:
-		return value
-	case []interface{}:
-		var builder strings.Builder
-		for _, item := range value {
-			switch piece := item.(type) {
-			case map[string]interface{}:
-				if pieceType, ok := piece["type"].(string); ok && pieceType == "text" {
-					if text, ok := piece["text"].(string); ok {
-						builder.WriteString(text)
-					}
-				}
-			}
-		}
-		return builder.String()
-	default:
-		return ""
-	}
-}
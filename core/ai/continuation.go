@@ -0,0 +1,92 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+)
+
+// FinishReasonLength 表示输出因达到 max_tokens 被截断
+const FinishReasonLength = "length"
+
+// DefaultContinuePrompt 为续写请求中追加的用户消息
+const DefaultContinuePrompt = "你的上一条回复因长度限制被截断。请从中断处继续输出，不要重复已输出的内容，也不要添加任何说明。"
+
+// continuation 保存续写所需的上下文：原始请求、实际应答的接口与截至目前的完整输出
+type continuation struct {
+	stage   string
+	primary endpoint
+	// served 为实际应答的接口在 chain(primary) 中的序号，0 表示主接口
+	served  int
+	request ZhipuAIRequest
+	text    string
+}
+
+// Truncated 报告首个选择项是否因 max_tokens 被截断
+func (r *ZhipuAIResponse) Truncated() bool {
+	return r != nil && len(r.Choices) > 0 && r.Choices[0].FinishReason == FinishReasonLength
+}
+
+// CanContinue 报告该响应是否记录了续写所需的上下文
+func (r *ZhipuAIResponse) CanContinue() bool {
+	return r != nil && r.continuation != nil
+}
+
+// ContinueWithContext 携带原始对话与已输出内容发送续写请求，沿用原请求的采样参数，
+// 并固定在上次实际应答的接口（ServedBy）上续写，失败时不切换到其他接口。
+// 返回的响应只包含新增的部分，且同样可以继续续写。onEvent 不为 nil 时使用流式请求。
+func (c *Client) ContinueWithContext(ctx context.Context, previous *ZhipuAIResponse, onEvent StreamHandler) (*ZhipuAIResponse, error) {
+	if !previous.CanContinue() {
+		return nil, fmt.Errorf("response cannot be continued")
+	}
+
+	base := previous.continuation
+	request := base.request
+	request.Messages = append(append([]Message(nil), base.request.Messages...),
+		Message{Role: "assistant", Content: base.text},
+		Message{Role: "user", Content: DefaultContinuePrompt},
+	)
+
+	var (
+		response *ZhipuAIResponse
+		err      error
+	)
+	if onEvent != nil {
+		response, err = c.stream(ctx, base.stage, request, pinnedRoute(base.primary, base.served), onEvent)
+	} else {
+		response, err = c.post(ctx, base.stage, request, pinnedRoute(base.primary, base.served))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var text string
+	if len(response.Choices) > 0 {
+		text = messageText(response.Choices[0].Message.Content)
+	}
+	response.continuation = &continuation{
+		stage:   base.stage,
+		primary: base.primary,
+		served:  base.served,
+		request: base.request,
+		text:    base.text + text,
+	}
+	return response, nil
+}
+
+// rememberContinuation 在响应中记录续写上下文
+func rememberContinuation(response *ZhipuAIResponse, stage string, primary endpoint, request ZhipuAIRequest) {
+	if response == nil {
+		return
+	}
+	var text string
+	if len(response.Choices) > 0 {
+		text = messageText(response.Choices[0].Message.Content)
+	}
+	response.continuation = &continuation{
+		stage:   stage,
+		primary: primary,
+		served:  response.ServedBy.Fallback,
+		request: request,
+		text:    text,
+	}
+}
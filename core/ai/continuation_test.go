@@ -0,0 +1,102 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// chatServer 返回固定正文与结束原因的 OpenAI 兼容接口，并统计收到的请求数
+func chatServer(t *testing.T, status int, content, finishReason string, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		var request ZhipuAIRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if status != http.StatusOK {
+			http.Error(w, `{"error":{"message":"unavailable"}}`, status)
+			return
+		}
+		fmt.Fprintf(w, `{"model":%q,"choices":[{"message":{"role":"assistant","content":%q},"finish_reason":%q}],"usage":{"total_tokens":1}}`,
+			request.Model, content, finishReason)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestContinueUsesServingEndpoint(t *testing.T) {
+	var primaryHits, fallbackHits atomic.Int32
+	primary := chatServer(t, http.StatusBadGateway, "", "", &primaryHits)
+	fallback := chatServer(t, http.StatusOK, "part", FinishReasonLength, &fallbackHits)
+
+	client := NewClient(ClientConfig{
+		APIKey:             "key",
+		BaseURL:            primary.URL,
+		TranslateModel:     "primary-model",
+		Retry:              RetryPolicy{MaxAttempts: 1},
+		TranslateFallbacks: []EndpointConfig{{BaseURL: fallback.URL, Model: "fallback-model"}},
+	})
+
+	response, err := client.TranslateWithContext(context.Background(), "hello", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !response.ServedBy.IsFallback() || response.ServedBy.Model != "fallback-model" {
+		t.Fatalf("ServedBy = %+v, want fallback", response.ServedBy)
+	}
+	if primaryHits.Load() != 1 || fallbackHits.Load() != 1 {
+		t.Fatalf("hits = %d/%d, want 1/1", primaryHits.Load(), fallbackHits.Load())
+	}
+
+	next, err := client.ContinueWithContext(context.Background(), response, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if primaryHits.Load() != 1 {
+		t.Fatalf("continuation went to the primary endpoint (%d hits)", primaryHits.Load())
+	}
+	if fallbackHits.Load() != 2 || next.ServedBy != response.ServedBy {
+		t.Fatalf("continuation ServedBy = %+v, hits = %d", next.ServedBy, fallbackHits.Load())
+	}
+
+	// 续写链上的每一次都固定在同一接口
+	if _, err := client.ContinueWithContext(context.Background(), next, nil); err != nil {
+		t.Fatal(err)
+	}
+	if primaryHits.Load() != 1 || fallbackHits.Load() != 3 {
+		t.Fatalf("hits = %d/%d, want 1/3", primaryHits.Load(), fallbackHits.Load())
+	}
+}
+
+func TestContinueDoesNotSwitchEndpoint(t *testing.T) {
+	var primaryHits, fallbackHits atomic.Int32
+	primary := chatServer(t, http.StatusOK, "part", FinishReasonLength, &primaryHits)
+	fallback := chatServer(t, http.StatusOK, "other", "stop", &fallbackHits)
+
+	client := NewClient(ClientConfig{
+		APIKey:             "key",
+		BaseURL:            primary.URL,
+		TranslateModel:     "primary-model",
+		Retry:              RetryPolicy{MaxAttempts: 1},
+		TranslateFallbacks: []EndpointConfig{{BaseURL: fallback.URL, Model: "fallback-model"}},
+	})
+
+	response, err := client.TranslateWithContext(context.Background(), "hello", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	primary.Close()
+
+	if _, err := client.ContinueWithContext(context.Background(), response, nil); err == nil {
+		t.Fatal("continuation should fail when the serving endpoint is down")
+	}
+	if fallbackHits.Load() != 0 {
+		t.Fatalf("continuation switched to the fallback endpoint (%d hits)", fallbackHits.Load())
+	}
+}
@@ -0,0 +1,167 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"time"
+)
+
+// AuthStatus 表示连接测试中的鉴权结果
+type AuthStatus string
+
+const (
+	// AuthUnknown 表示请求未到达服务端（例如网络错误），无法判断鉴权是否有效
+	AuthUnknown AuthStatus = "unknown"
+	// AuthOK 表示服务端接受了凭证
+	AuthOK AuthStatus = "ok"
+	// AuthFailed 表示 API Key 无效或无权限
+	AuthFailed AuthStatus = "failed"
+)
+
+const (
+	probeTextPrompt  = "Reply with the single word OK."
+	probeImagePrompt = "What is the main color of this image? Answer with one word."
+	probeMaxTokens   = 64
+)
+
+// ProbeResult 描述对单个接口的连接测试结果，只测试主接口，不重试也不切换备用接口
+type ProbeResult struct {
+	Endpoint string
+	Provider string
+	BaseURL  string
+	Model    string
+	Auth     AuthStatus
+	// Latency 为非流式请求的往返耗时
+	Latency time.Duration
+	// Reply 为模型的回复，便于确认模型确实可用
+	Reply string
+	Err   error
+	// Streaming 表示流式请求是否正常完成，FirstTokenLatency 为收到首个增量（含推理内容）的耗时
+	Streaming         bool
+	FirstTokenLatency time.Duration
+	StreamErr         error
+	// Images 表示模型是否接受图像输入，nil 表示未测试或无法判断
+	Images *bool
+}
+
+// ProbeTranslate 向翻译接口发送一次极短的文本对话，并测试流式输出
+func (c *Client) ProbeTranslate(ctx context.Context) ProbeResult {
+	messages := []Message{{Role: "user", Content: probeTextPrompt}}
+	return c.probe(ctx, c.translate, messages, nil)
+}
+
+// ProbeVision 使用生成的测试 PNG 向视觉接口发送一次极短的图像对话，并测试流式输出
+func (c *Client) ProbeVision(ctx context.Context) ProbeResult {
+	imageData, err := probeImage()
+	if err != nil {
+		result := newProbeResult(c.vision)
+		result.Err = err
+		return result
+	}
+	messages := c.buildVisionMessages(probeImagePrompt, imageData, "image/png", "")
+	return c.probe(ctx, c.vision, messages, func(result *ProbeResult) {
+		accepted := result.Err == nil
+		var requestErr *RequestError
+		if !accepted && !(errors.As(result.Err, &requestErr) && requestErr.Category == ErrorCategoryInvalidRequest) {
+			// 鉴权、网络等错误无法说明模型是否支持图像
+			return
+		}
+		result.Images = &accepted
+	})
+}
+
+func newProbeResult(target endpoint) ProbeResult {
+	return ProbeResult{
+		Endpoint: target.name,
+		Provider: target.provider.Name(),
+		BaseURL:  target.base,
+		Model:    target.model,
+		Auth:     AuthUnknown,
+	}
+}
+
+func (c *Client) probe(ctx context.Context, target endpoint, messages []Message, inspect func(result *ProbeResult)) ProbeResult {
+	result := newProbeResult(target)
+	request := ZhipuAIRequest{
+		Model:       target.model,
+		Messages:    messages,
+		Temperature: Float64(0),
+		MaxTokens:   probeMaxTokens,
+	}
+
+	started := time.Now()
+	response, err := c.postOnce(ctx, request, target)
+	result.Latency = time.Since(started)
+	result.Auth = authStatus(err)
+	result.Err = err
+	if err == nil && len(response.Choices) > 0 {
+		result.Reply = strings.TrimSpace(messageText(response.Choices[0].Message.Content))
+	}
+	if inspect != nil {
+		inspect(&result)
+	}
+	if result.Auth == AuthFailed || ctx.Err() != nil {
+		return result
+	}
+
+	request.Stream = true
+	started = time.Now()
+	_, err = c.streamOnce(ctx, request, target, func(event StreamEvent) {
+		if (event.Delta != "" || event.Reasoning != "") && result.FirstTokenLatency == 0 {
+			result.FirstTokenLatency = time.Since(started)
+		}
+	})
+	result.Streaming = err == nil
+	result.StreamErr = err
+	if result.Auth == AuthUnknown {
+		result.Auth = authStatus(err)
+	}
+	return result
+}
+
+// authStatus 根据请求结果推断鉴权状态：服务端返回了非鉴权类错误时说明凭证已被接受
+func authStatus(err error) AuthStatus {
+	if err == nil {
+		return AuthOK
+	}
+	var requestErr *RequestError
+	if !errors.As(err, &requestErr) {
+		return AuthUnknown
+	}
+	switch {
+	case requestErr.Category == ErrorCategoryAuth:
+		return AuthFailed
+	case requestErr.Category == ErrorCategoryNetwork, requestErr.StatusCode >= 500:
+		return AuthUnknown
+	default:
+		return AuthOK
+	}
+}
+
+func messageText(content interface{}) string {
+	if text, ok := content.(string); ok {
+		return text
+	}
+	return streamContentToString(content)
+}
+
+// probeImage 生成一张纯红色的测试图片
+func probeImage() ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	red := color.RGBA{R: 220, A: 255}
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			img.Set(x, y, red)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,205 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrorCategory 对接口错误进行归类，便于调用方给出可读的提示
+type ErrorCategory string
+
+const (
+	// ErrorCategoryAuth 表示 API Key 无效或无权限
+	ErrorCategoryAuth ErrorCategory = "auth"
+	// ErrorCategoryQuota 表示限流或账户额度不足
+	ErrorCategoryQuota ErrorCategory = "quota"
+	// ErrorCategoryContextLength 表示输入超出模型上下文长度
+	ErrorCategoryContextLength ErrorCategory = "context_length"
+	// ErrorCategoryContentFilter 表示内容被服务商安全策略拦截
+	ErrorCategoryContentFilter ErrorCategory = "content_filter"
+	// ErrorCategoryRefusal 表示模型以拒答代替了正常输出
+	ErrorCategoryRefusal ErrorCategory = "refusal"
+	// ErrorCategoryServer 表示服务端故障（5xx）
+	ErrorCategoryServer ErrorCategory = "server"
+	// ErrorCategoryNetwork 表示连接失败、超时等网络问题
+	ErrorCategoryNetwork ErrorCategory = "network"
+	// ErrorCategoryInvalidRequest 表示其他请求参数错误
+	ErrorCategoryInvalidRequest ErrorCategory = "invalid_request"
+)
+
+// RequestError 描述一次模型接口调用失败的详细信息，可通过 errors.As 获取
+type RequestError struct {
+	Provider   string
+	StatusCode int
+	Code       string
+	Message    string
+	RequestID  string
+	Category   ErrorCategory
+	RetryAfter time.Duration
+	// Body 保存原始响应体，便于排查
+	Body string
+	// Err 为底层错误（例如网络错误）
+	Err error
+}
+
+func (e *RequestError) Error() string {
+	switch {
+	case e.Err != nil && e.StatusCode == 0:
+		return fmt.Sprintf("request failed: %v", e.Err)
+	case e.StatusCode != 0 && e.Code != "":
+		return fmt.Sprintf("API request failed with status %d: %s - %s", e.StatusCode, e.Code, e.Message)
+	case e.StatusCode != 0:
+		return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Message)
+	default:
+		return fmt.Sprintf("API error: %s - %s", e.Code, e.Message)
+	}
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// UserMessage 返回适合直接展示给用户的错误说明
+func (e *RequestError) UserMessage() string {
+	var timeoutErr *TimeoutError
+	if errors.As(e.Err, &timeoutErr) {
+		return timeoutErr.UserMessage()
+	}
+	switch e.Category {
+	case ErrorCategoryAuth:
+		return "API Key 无效或无权访问该模型，请检查设置中的 API Key"
+	case ErrorCategoryQuota:
+		return "请求过于频繁或账户额度不足，请稍后重试或检查账户余额"
+	case ErrorCategoryContextLength:
+		return "输入内容超出模型上下文长度，请缩小截图区域或更换模型"
+	case ErrorCategoryContentFilter:
+		return "内容被服务商安全策略拦截"
+	case ErrorCategoryRefusal:
+		return "模型拒绝处理该内容，可更换模型后重试"
+	case ErrorCategoryServer:
+		return "模型服务暂时不可用，请稍后重试"
+	case ErrorCategoryNetwork:
+		return "无法连接模型服务，请检查网络或 Base URL"
+	}
+	if e.Message != "" {
+		return e.Message
+	}
+	return e.Error()
+}
+
+// newStatusError 根据非 200 响应构造错误
+func newStatusError(provider Provider, resp *http.Response, body []byte) *RequestError {
+	apiErr := provider.DecodeError(body)
+	message := strings.TrimSpace(apiErr.Message)
+	if message == "" {
+		message = strings.TrimSpace(string(body))
+	}
+	if message == "" {
+		message = http.StatusText(resp.StatusCode)
+	}
+
+	return &RequestError{
+		Provider:   provider.Name(),
+		StatusCode: resp.StatusCode,
+		Code:       apiErr.Code,
+		Message:    message,
+		RequestID:  requestIDFromHeader(resp.Header),
+		Category:   classifyError(resp.StatusCode, apiErr),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		Body:       string(body),
+	}
+}
+
+// newTransportError 包装网络层错误
+func newTransportError(provider Provider, err error) *RequestError {
+	return &RequestError{
+		Provider: provider.Name(),
+		Message:  err.Error(),
+		Category: ErrorCategoryNetwork,
+		Err:      err,
+	}
+}
+
+// newBodyError 包装响应体或流式数据中携带的错误（HTTP 状态为 200）
+func newBodyError(provider string, apiErr APIError) *RequestError {
+	return &RequestError{
+		Provider: provider,
+		Code:     apiErr.Code,
+		Message:  apiErr.Message,
+		Category: classifyError(0, apiErr),
+	}
+}
+
+func requestIDFromHeader(header http.Header) string {
+	for _, key := range []string{"X-Request-Id", "Request-Id", "X-Log-Id", "X-Goog-Request-Id"} {
+		if value := strings.TrimSpace(header.Get(key)); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// zhipuErrorCodes 智谱开放平台的业务错误码
+var zhipuErrorCodes = map[string]ErrorCategory{
+	"1000": ErrorCategoryAuth,
+	"1001": ErrorCategoryAuth,
+	"1002": ErrorCategoryAuth,
+	"1003": ErrorCategoryAuth,
+	"1004": ErrorCategoryAuth,
+	"1113": ErrorCategoryQuota,
+	"1261": ErrorCategoryContextLength,
+	"1301": ErrorCategoryContentFilter,
+	"1302": ErrorCategoryQuota,
+	"1303": ErrorCategoryQuota,
+	"1305": ErrorCategoryQuota,
+}
+
+// classifyError 结合状态码与错误码/文本推断错误类别
+func classifyError(status int, apiErr APIError) ErrorCategory {
+	if category, ok := zhipuErrorCodes[strings.TrimSpace(apiErr.Code)]; ok {
+		return category
+	}
+
+	text := strings.ToLower(apiErr.Code + " " + apiErr.Type + " " + apiErr.Message)
+
+	switch {
+	case containsAny(text, "content_filter", "sensitive", "safety", "prohibited", "敏感"):
+		return ErrorCategoryContentFilter
+	case containsAny(text, "context_length", "context length", "maximum context", "too many tokens", "prompt is too long", "超过最大长度"):
+		return ErrorCategoryContextLength
+	}
+
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrorCategoryAuth
+	case status == http.StatusTooManyRequests || status == http.StatusPaymentRequired:
+		return ErrorCategoryQuota
+	case status == http.StatusRequestEntityTooLarge:
+		return ErrorCategoryContextLength
+	case status >= 500:
+		return ErrorCategoryServer
+	}
+
+	switch {
+	case containsAny(text, "invalid_api_key", "authentication", "unauthorized", "permission", "api key"):
+		return ErrorCategoryAuth
+	case containsAny(text, "rate_limit", "rate limit", "quota", "insufficient", "balance", "overloaded", "余额", "频率"):
+		return ErrorCategoryQuota
+	case status == 0 && containsAny(text, "server_error", "internal", "unavailable"):
+		return ErrorCategoryServer
+	}
+
+	return ErrorCategoryInvalidRequest
+}
+
+func containsAny(text string, needles ...string) bool {
+	for _, needle := range needles {
+		if strings.Contains(text, needle) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// EndpointConfig 描述一个备用接口，字段留空时沿用主接口的对应配置
+type EndpointConfig struct {
+	BaseURL  string `json:"baseUrl"`
+	APIKey   string `json:"apiKey"`
+	Model    string `json:"model"`
+	Provider string `json:"provider"`
+}
+
+// ServedBy 记录实际应答请求的接口
+type ServedBy struct {
+	Endpoint string `json:"endpoint"`
+	Provider string `json:"provider"`
+	BaseURL  string `json:"baseUrl"`
+	Model    string `json:"model"`
+	// Fallback 为备用接口序号，0 表示主接口
+	Fallback int `json:"fallback"`
+}
+
+// IsFallback 报告应答是否来自备用接口
+func (s ServedBy) IsFallback() bool {
+	return s.Fallback > 0
+}
+
+// newFallbackEndpoints 基于主接口构建备用接口列表，跳过与主接口完全相同的配置
+func newFallbackEndpoints(primary endpoint, configs []EndpointConfig) []endpoint {
+	var endpoints []endpoint
+	for _, cfg := range configs {
+		target := endpoint{
+			name:     primary.name,
+			apiKey:   strings.TrimSpace(cfg.APIKey),
+			base:     normalizeBaseURLOrFallback(cfg.BaseURL, primary.base),
+			model:    normalizeModel(cfg.Model, primary.model),
+			provider: primary.provider,
+		}
+		if target.apiKey == "" {
+			target.apiKey = primary.apiKey
+		}
+		if strings.TrimSpace(cfg.Provider) != "" {
+			target.provider = NewProvider(cfg.Provider)
+		}
+		if target.base == primary.base && target.model == primary.model && target.apiKey == primary.apiKey && target.provider.Name() == primary.provider.Name() {
+			continue
+		}
+		endpoints = append(endpoints, target)
+	}
+	return endpoints
+}
+
+func (e endpoint) servedBy(index int) ServedBy {
+	return ServedBy{
+		Endpoint: e.name,
+		Provider: e.provider.Name(),
+		BaseURL:  e.base,
+		Model:    e.model,
+		Fallback: index,
+	}
+}
+
+// chain 返回主接口及其备用接口，按尝试顺序排列
+func (c *Client) chain(primary endpoint) []endpoint {
+	fallbacks := c.translateFallbacks
+	if primary.name == EndpointVision {
+		fallbacks = c.visionFallbacks
+	}
+	return append([]endpoint{primary}, fallbacks...)
+}
+
+// route 描述一次调用可尝试的接口：primary 为所属阶段的主接口，
+// 只尝试 chain(primary) 中序号位于 [first, last] 的接口，last 小于 0 表示直到末尾
+type route struct {
+	primary endpoint
+	first   int
+	last    int
+}
+
+// routeFrom 返回从主接口开始、按需切换到备用接口的常规路线
+func routeFrom(primary endpoint) route {
+	return route{primary: primary, last: -1}
+}
+
+// pinnedRoute 返回只使用第 index 个接口的路线，续写时沿用上次实际应答的接口，避免混合不同模型的输出
+func pinnedRoute(primary endpoint, index int) route {
+	return route{primary: primary, first: index, last: index}
+}
+
+// withFallback 依次尝试主接口与备用接口，遇到网络、5xx 或额度错误时切换到下一个；
+// 开启 FallbackOnBlocked 时，主接口的输出被过滤或拒答也会切换到首个备用接口（即使已推送过增量）
+func (c *Client) withFallback(ctx context.Context, r route, request ZhipuAIRequest, call func(target endpoint, request ZhipuAIRequest) (*ZhipuAIResponse, bool, error)) (*ZhipuAIResponse, error) {
+	var lastErr error
+	for index, target := range c.chain(r.primary) {
+		if index < r.first || (r.last >= 0 && index > r.last) {
+			continue
+		}
+		request.Model = target.model
+		response, delivered, err := call(target, request)
+		if err == nil {
+			response.ServedBy = target.servedBy(index)
+			return response, nil
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		if c.fallbackOnBlocked && index == 0 && IsBlocked(err) {
+			continue
+		}
+		// 已向调用方推送过增量内容时不再切换接口，避免混合两个模型的输出
+		if delivered || !shouldFallback(err) {
+			return nil, err
+		}
+	}
+	if lastErr == nil {
+		// 续写时记录的接口已不在当前客户端的接口列表中
+		lastErr = errors.New("no endpoint available for request")
+	}
+	return nil, lastErr
+}
+
+// shouldFallback 判断错误是否值得切换到备用接口
+func shouldFallback(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var requestErr *RequestError
+	if errors.As(err, &requestErr) {
+		switch requestErr.Category {
+		case ErrorCategoryNetwork, ErrorCategoryServer, ErrorCategoryQuota:
+			return true
+		}
+		return requestErr.StatusCode >= 500
+	}
+	return isNetError(err)
+}
@@ -0,0 +1,123 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+)
+
+// ErrModelListUnsupported 表示服务商未实现模型列表接口
+var ErrModelListUnsupported = errors.New("ai: provider does not support listing models")
+
+// ModelLister 由支持列出可用模型的协议适配器实现
+type ModelLister interface {
+	// NewListModelsRequest 构造获取模型列表的 HTTP 请求
+	NewListModelsRequest(ctx context.Context, baseURL, apiKey string) (*http.Request, error)
+	// DecodeModels 解析模型列表响应体
+	DecodeModels(body []byte) ([]ModelInfo, error)
+}
+
+// ModelInfo 描述接口返回的一个模型
+type ModelInfo struct {
+	ID      string `json:"id"`
+	Name    string `json:"name,omitempty"`
+	OwnedBy string `json:"ownedBy,omitempty"`
+}
+
+// ModelList 是单个接口的模型列表
+type ModelList struct {
+	Endpoint string
+	Provider string
+	BaseURL  string
+	Models   []ModelInfo
+	// Err 为获取失败的原因，服务商未实现列表接口时可用 errors.Is(err, ErrModelListUnsupported) 判断
+	Err error
+}
+
+// ModelCatalog 汇总翻译接口与视觉接口的模型列表
+type ModelCatalog struct {
+	Translate ModelList
+	Vision    ModelList
+}
+
+// ListModels 分别获取翻译接口与视觉接口的可用模型。
+// 单个接口失败不影响另一个接口，失败原因记录在对应 ModelList.Err 中；
+// 仅在 ctx 被取消时返回错误。
+func (c *Client) ListModels(ctx context.Context) (*ModelCatalog, error) {
+	catalog := &ModelCatalog{
+		Translate: c.listModels(ctx, c.translate),
+	}
+	if sameModelSource(c.translate, c.vision) {
+		catalog.Vision = catalog.Translate
+		catalog.Vision.Endpoint = c.vision.name
+	} else {
+		catalog.Vision = c.listModels(ctx, c.vision)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return catalog, nil
+}
+
+func sameModelSource(a, b endpoint) bool {
+	return a.base == b.base && a.apiKey == b.apiKey && a.provider.Name() == b.provider.Name()
+}
+
+func (c *Client) listModels(ctx context.Context, target endpoint) ModelList {
+	list := ModelList{
+		Endpoint: target.name,
+		Provider: target.provider.Name(),
+		BaseURL:  target.base,
+	}
+	models, err := c.fetchModels(ctx, target)
+	if err != nil {
+		list.Err = err
+		return list
+	}
+	sort.Slice(models, func(i, j int) bool {
+		return models[i].ID < models[j].ID
+	})
+	list.Models = models
+	return list
+}
+
+func (c *Client) fetchModels(ctx context.Context, target endpoint) ([]ModelInfo, error) {
+	lister, ok := target.provider.(ModelLister)
+	if !ok {
+		return nil, ErrModelListUnsupported
+	}
+	req, err := lister.NewListModelsRequest(ctx, target.base, target.apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, newTransportError(target.provider, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, newTransportError(target.provider, fmt.Errorf("failed to read response: %w", err))
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		// 部分兼容服务只实现了对话接口
+		return nil, fmt.Errorf("%w: %w", ErrModelListUnsupported, newStatusError(target.provider, resp, body))
+	default:
+		return nil, newStatusError(target.provider, resp, body)
+	}
+
+	models, err := lister.DecodeModels(body)
+	if err != nil {
+		// 响应不是预期的列表格式时同样视为不支持
+		return nil, fmt.Errorf("%w: %v", ErrModelListUnsupported, err)
+	}
+	return models, nil
+}
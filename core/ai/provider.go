@@ -0,0 +1,229 @@
+package ai
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	// ProviderOpenAI 表示 OpenAI Chat Completions 兼容协议（默认）
+	ProviderOpenAI = "openai"
+	// ProviderAnthropic 表示 Anthropic Messages 协议
+	ProviderAnthropic = "anthropic"
+	// ProviderGemini 表示 Google Gemini generateContent 协议
+	ProviderGemini = "gemini"
+	// ProviderOllama 表示 Ollama 原生 /api/chat 协议
+	ProviderOllama = "ollama"
+)
+
+// Provider 将通用的对话请求转换为具体服务商的接口协议。
+// Client 负责发送请求与聚合结果，适配器只处理编解码。
+type Provider interface {
+	// Name 返回适配器标识，例如 "openai"
+	Name() string
+	// NewRequest 构造 HTTP 请求；request.Stream 为 true 时需请求流式响应
+	NewRequest(ctx context.Context, baseURL, apiKey string, request ZhipuAIRequest) (*http.Request, error)
+	// DecodeResponse 解析非流式响应体
+	DecodeResponse(body []byte) (*ZhipuAIResponse, error)
+	// DecodeStream 逐段解析流式响应体，每得到一段数据调用一次 emit
+	DecodeStream(body io.Reader, emit func(StreamPart) error) error
+	// DecodeError 从错误响应体中提取错误码与说明，无法解析时返回零值
+	DecodeError(body []byte) APIError
+}
+
+// StreamPart 表示适配器从流式响应中解析出的一段数据
+type StreamPart struct {
+	ID      string
+	Object  string
+	Created int64
+	Content string
+	// Reasoning 为服务商单独返回的推理内容
+	Reasoning string
+	// Refusal 为服务商单独返回的拒答说明
+	Refusal      string
+	FinishReason string
+	Usage        *Usage
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]func() Provider{
+		ProviderOpenAI:    func() Provider { return openAIProvider{} },
+		ProviderAnthropic: func() Provider { return anthropicProvider{} },
+		ProviderGemini:    func() Provider { return geminiProvider{} },
+		ProviderOllama:    func() Provider { return ollamaProvider{} },
+	}
+)
+
+// RegisterProvider 注册自定义协议适配器，同名时覆盖已有实现
+func RegisterProvider(name string, factory func() Provider) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if key == "" || factory == nil {
+		return
+	}
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[key] = factory
+}
+
+// NormalizeProvider 归一化协议名称，未知或留空时回退到 OpenAI 兼容协议
+func NormalizeProvider(value string) string {
+	key := strings.ToLower(strings.TrimSpace(value))
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	if _, ok := providers[key]; ok {
+		return key
+	}
+	return ProviderOpenAI
+}
+
+// NewProvider 按名称创建协议适配器
+func NewProvider(name string) Provider {
+	key := NormalizeProvider(name)
+	providersMu.RLock()
+	factory := providers[key]
+	providersMu.RUnlock()
+	return factory()
+}
+
+// inlineImage 表示从 data URL 中解析出的内联图像
+type inlineImage struct {
+	mimeType string
+	data     string
+}
+
+// splitMessageContent 将通用消息内容拆分为文本与内联图像，供非 OpenAI 协议重新编码
+func splitMessageContent(content interface{}) (string, []inlineImage) {
+	switch value := content.(type) {
+	case string:
+		return value, nil
+	case []ContentItem:
+		var (
+			texts  []string
+			images []inlineImage
+		)
+		for _, item := range value {
+			switch item.Type {
+			case "text":
+				if item.Text != "" {
+					texts = append(texts, item.Text)
+				}
+			case "image_url":
+				if image, ok := parseDataURL(item.ImageURL.URL); ok {
+					images = append(images, image)
+				}
+			}
+		}
+		return strings.Join(texts, "\n"), images
+	default:
+		return streamContentToString(content), nil
+	}
+}
+
+// parseDataURL 解析 data:<mime>;base64,<data> 形式的图像地址
+func parseDataURL(value string) (inlineImage, bool) {
+	if !strings.HasPrefix(value, "data:") {
+		return inlineImage{}, false
+	}
+	header, data, found := strings.Cut(value[len("data:"):], ",")
+	if !found {
+		return inlineImage{}, false
+	}
+	mimeType, _, _ := strings.Cut(header, ";")
+	if mimeType == "" {
+		mimeType = "image/png"
+	}
+	return inlineImage{mimeType: mimeType, data: data}, true
+}
+
+// readSSE 读取 Server-Sent Events，按事件回调 event 名称与 data 内容。
+// handle 返回 io.EOF 时提前结束读取且不视为错误。
+func readSSE(body io.Reader, handle func(event, data string) error) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	var (
+		eventName  string
+		dataBuffer strings.Builder
+	)
+
+	flush := func() error {
+		if dataBuffer.Len() == 0 {
+			eventName = ""
+			return nil
+		}
+		payload := dataBuffer.String()
+		name := eventName
+		dataBuffer.Reset()
+		eventName = ""
+		return handle(name, payload)
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		switch {
+		case strings.HasPrefix(line, ":"):
+			continue
+		case strings.HasPrefix(line, "event:"):
+			eventName = strings.TrimSpace(line[len("event:"):])
+		case strings.HasPrefix(line, "data:"):
+			segment := strings.TrimSpace(line[len("data:"):])
+			if segment == "[DONE]" {
+				if err := flush(); err != nil && err != io.EOF {
+					return err
+				}
+				return nil
+			}
+			if dataBuffer.Len() > 0 {
+				dataBuffer.WriteByte('\n')
+			}
+			dataBuffer.WriteString(segment)
+		case strings.TrimSpace(line) == "":
+			if err := flush(); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	if err := flush(); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+// readNDJSON 逐行读取以换行分隔的 JSON 流
+func readNDJSON(body io.Reader, handle func(line string) error) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if err := handle(line); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stream: %w", err)
+	}
+	return nil
+}
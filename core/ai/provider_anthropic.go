@@ -0,0 +1,298 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	anthropicVersion          = "2023-06-01"
+	anthropicDefaultMaxTokens = 4096
+)
+
+// anthropicProvider 实现 Anthropic Messages 协议
+type anthropicProvider struct{}
+
+type anthropicRequest struct {
+	Model         string             `json:"model"`
+	System        string             `json:"system,omitempty"`
+	Messages      []anthropicMessage `json:"messages"`
+	MaxTokens     int                `json:"max_tokens"`
+	Temperature   *float64           `json:"temperature,omitempty"`
+	TopP          *float64           `json:"top_p,omitempty"`
+	StopSequences []string           `json:"stop_sequences,omitempty"`
+	Stream        bool               `json:"stream,omitempty"`
+}
+
+type anthropicMessage struct {
+	Role    string             `json:"role"`
+	Content []anthropicContent `json:"content"`
+}
+
+type anthropicContent struct {
+	Type     string                `json:"type"`
+	Text     string                `json:"text,omitempty"`
+	Thinking string                `json:"thinking,omitempty"`
+	Source   *anthropicImageSource `json:"source,omitempty"`
+}
+
+type anthropicImageSource struct {
+	Type      string `json:"type"`
+	MediaType string `json:"media_type"`
+	Data      string `json:"data"`
+}
+
+type anthropicResponse struct {
+	ID         string             `json:"id"`
+	Type       string             `json:"type"`
+	Content    []anthropicContent `json:"content"`
+	StopReason string             `json:"stop_reason"`
+	Usage      anthropicUsage     `json:"usage"`
+	Error      *anthropicError    `json:"error,omitempty"`
+}
+
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+type anthropicError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+type anthropicStreamEvent struct {
+	Type    string             `json:"type"`
+	Message *anthropicResponse `json:"message,omitempty"`
+	Delta   struct {
+		Type       string `json:"type"`
+		Text       string `json:"text"`
+		Thinking   string `json:"thinking"`
+		StopReason string `json:"stop_reason"`
+	} `json:"delta"`
+	Usage *anthropicUsage `json:"usage,omitempty"`
+	Error *anthropicError `json:"error,omitempty"`
+}
+
+func (anthropicProvider) Name() string {
+	return ProviderAnthropic
+}
+
+func (anthropicProvider) NewRequest(ctx context.Context, baseURL, apiKey string, request ZhipuAIRequest) (*http.Request, error) {
+	payload := anthropicRequest{
+		Model:         request.Model,
+		MaxTokens:     request.MaxTokens,
+		Temperature:   request.Temperature,
+		TopP:          request.TopP,
+		StopSequences: request.Stop,
+		Stream:        request.Stream,
+	}
+	if payload.MaxTokens <= 0 {
+		payload.MaxTokens = anthropicDefaultMaxTokens
+	}
+	// Anthropic 不允许同时指定 temperature 与 top_p，以 temperature 为准
+	if payload.Temperature != nil {
+		payload.TopP = nil
+	}
+
+	var system []string
+	for _, message := range request.Messages {
+		text, images := splitMessageContent(message.Content)
+		if message.Role == "system" {
+			if text != "" {
+				system = append(system, text)
+			}
+			continue
+		}
+
+		content := make([]anthropicContent, 0, len(images)+1)
+		for _, image := range images {
+			content = append(content, anthropicContent{
+				Type: "image",
+				Source: &anthropicImageSource{
+					Type:      "base64",
+					MediaType: image.mimeType,
+					Data:      image.data,
+				},
+			})
+		}
+		if text != "" {
+			content = append(content, anthropicContent{Type: "text", Text: text})
+		}
+		payload.Messages = append(payload.Messages, anthropicMessage{Role: message.Role, Content: content})
+	}
+	payload.System = strings.Join(system, "\n\n")
+
+	jsonData, err := marshalWithExtra(payload, request.ExtraBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/messages", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("anthropic-version", anthropicVersion)
+	if apiKey != "" {
+		req.Header.Set("x-api-key", apiKey)
+	}
+	return req, nil
+}
+
+func (anthropicProvider) DecodeResponse(body []byte) (*ZhipuAIResponse, error) {
+	var response anthropicResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+	if response.Error != nil {
+		return nil, newBodyError(ProviderAnthropic, response.Error.toAPIError())
+	}
+
+	var text, thinking strings.Builder
+	for _, item := range response.Content {
+		switch item.Type {
+		case "text":
+			text.WriteString(item.Text)
+		case "thinking":
+			thinking.WriteString(item.Thinking)
+		}
+	}
+
+	return &ZhipuAIResponse{
+		ID:     response.ID,
+		Object: response.Type,
+		Choices: []Choice{
+			{
+				Message: Message{
+					Role:             "assistant",
+					Content:          text.String(),
+					ReasoningContent: thinking.String(),
+				},
+				FinishReason: anthropicFinishReason(response.StopReason),
+			},
+		},
+		Usage: Usage{
+			PromptTokens:     response.Usage.InputTokens,
+			CompletionTokens: response.Usage.OutputTokens,
+			TotalTokens:      response.Usage.InputTokens + response.Usage.OutputTokens,
+		},
+	}, nil
+}
+
+func (anthropicProvider) DecodeStream(body io.Reader, emit func(StreamPart) error) error {
+	var usage Usage
+
+	return readSSE(body, func(_ string, data string) error {
+		var event anthropicStreamEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return fmt.Errorf("failed to unmarshal stream chunk: %v (raw: %s)", err, data)
+		}
+
+		switch event.Type {
+		case "error":
+			if event.Error != nil {
+				return newBodyError(ProviderAnthropic, event.Error.toAPIError())
+			}
+			return newBodyError(ProviderAnthropic, APIError{Message: data})
+		case "message_start":
+			if event.Message == nil {
+				return nil
+			}
+			usage.PromptTokens = event.Message.Usage.InputTokens
+			return emit(StreamPart{ID: event.Message.ID, Object: event.Message.Type})
+		case "content_block_delta":
+			switch event.Delta.Type {
+			case "text_delta":
+				return emit(StreamPart{Content: event.Delta.Text})
+			case "thinking_delta":
+				return emit(StreamPart{Reasoning: event.Delta.Thinking})
+			default:
+				return nil
+			}
+		case "message_delta":
+			part := StreamPart{FinishReason: anthropicFinishReason(event.Delta.StopReason)}
+			if event.Usage != nil {
+				usage.CompletionTokens = event.Usage.OutputTokens
+				usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+				final := usage
+				part.Usage = &final
+			}
+			return emit(part)
+		case "message_stop":
+			return io.EOF
+		default:
+			return nil
+		}
+	})
+}
+
+func (anthropicProvider) DecodeError(body []byte) APIError {
+	var payload struct {
+		Error *anthropicError `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Error == nil {
+		return APIError{}
+	}
+	return payload.Error.toAPIError()
+}
+
+func (anthropicProvider) NewListModelsRequest(ctx context.Context, baseURL, apiKey string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"/models?limit=1000", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("anthropic-version", anthropicVersion)
+	if apiKey != "" {
+		req.Header.Set("x-api-key", apiKey)
+	}
+	return req, nil
+}
+
+func (anthropicProvider) DecodeModels(body []byte) ([]ModelInfo, error) {
+	var payload struct {
+		Data *[]struct {
+			ID          string `json:"id"`
+			DisplayName string `json:"display_name"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal models: %v", err)
+	}
+	if payload.Data == nil {
+		return nil, fmt.Errorf("models response has no data field")
+	}
+	models := make([]ModelInfo, 0, len(*payload.Data))
+	for _, item := range *payload.Data {
+		if item.ID != "" {
+			models = append(models, ModelInfo{ID: item.ID, Name: item.DisplayName, OwnedBy: "anthropic"})
+		}
+	}
+	return models, nil
+}
+
+func (e anthropicError) toAPIError() APIError {
+	return APIError{Code: e.Type, Message: e.Message, Type: e.Type}
+}
+
+// anthropicFinishReason 将 stop_reason 映射为 Chat Completions 的 finish_reason
+func anthropicFinishReason(reason string) string {
+	switch reason {
+	case "":
+		return ""
+	case "end_turn", "stop_sequence":
+		return "stop"
+	case "max_tokens":
+		return "length"
+	case "refusal":
+		return "content_filter"
+	default:
+		return reason
+	}
+}
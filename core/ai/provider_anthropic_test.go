@@ -0,0 +1,51 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+)
+
+func TestAnthropicRequestSampling(t *testing.T) {
+	cases := []struct {
+		name            string
+		temperature     *float64
+		topP            *float64
+		wantTemperature bool
+		wantTopP        bool
+	}{
+		{"temperature wins over top_p", Float64(0.7), Float64(0.9), true, false},
+		{"temperature only", Float64(1), nil, true, false},
+		{"top_p only", nil, Float64(0.9), false, true},
+		{"neither", nil, nil, false, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := ZhipuAIRequest{
+				Model:       "claude-sonnet",
+				Messages:    []Message{{Role: "user", Content: "hello"}},
+				Temperature: tc.temperature,
+				TopP:        tc.topP,
+			}
+			req, err := anthropicProvider{}.NewRequest(context.Background(), "https://example.com/v1", "key", request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var payload map[string]interface{}
+			if err := json.Unmarshal(body, &payload); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := payload["temperature"]; ok != tc.wantTemperature {
+				t.Fatalf("temperature present = %v, want %v (%s)", ok, tc.wantTemperature, body)
+			}
+			if _, ok := payload["top_p"]; ok != tc.wantTopP {
+				t.Fatalf("top_p present = %v, want %v (%s)", ok, tc.wantTopP, body)
+			}
+		})
+	}
+}
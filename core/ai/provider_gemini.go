@@ -0,0 +1,319 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// geminiProvider 实现 Google Gemini generateContent 协议
+type geminiProvider struct{}
+
+type geminiRequest struct {
+	Contents          []geminiContent         `json:"contents"`
+	SystemInstruction *geminiContent          `json:"systemInstruction,omitempty"`
+	GenerationConfig  *geminiGenerationConfig `json:"generationConfig,omitempty"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiPart struct {
+	Text       string            `json:"text,omitempty"`
+	InlineData *geminiInlineData `json:"inlineData,omitempty"`
+	// Thought 标记该段为思考摘要
+	Thought bool `json:"thought,omitempty"`
+}
+
+type geminiInlineData struct {
+	MimeType string `json:"mimeType"`
+	Data     string `json:"data"`
+}
+
+type geminiGenerationConfig struct {
+	Temperature      *float64 `json:"temperature,omitempty"`
+	TopP             *float64 `json:"topP,omitempty"`
+	MaxOutputTokens  int      `json:"maxOutputTokens,omitempty"`
+	StopSequences    []string `json:"stopSequences,omitempty"`
+	PresencePenalty  *float64 `json:"presencePenalty,omitempty"`
+	FrequencyPenalty *float64 `json:"frequencyPenalty,omitempty"`
+	Seed             *int64   `json:"seed,omitempty"`
+}
+
+type geminiResponse struct {
+	ResponseID    string            `json:"responseId"`
+	Candidates    []geminiCandidate `json:"candidates"`
+	UsageMetadata *geminiUsage      `json:"usageMetadata,omitempty"`
+	Error         *geminiError      `json:"error,omitempty"`
+	// PromptFeedback 在输入被安全策略拦截时给出原因，此时不返回任何候选
+	PromptFeedback *struct {
+		BlockReason string `json:"blockReason"`
+	} `json:"promptFeedback,omitempty"`
+}
+
+type geminiCandidate struct {
+	Content      geminiContent `json:"content"`
+	FinishReason string        `json:"finishReason"`
+}
+
+type geminiUsage struct {
+	PromptTokenCount     int `json:"promptTokenCount"`
+	CandidatesTokenCount int `json:"candidatesTokenCount"`
+	TotalTokenCount      int `json:"totalTokenCount"`
+}
+
+type geminiError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Status  string `json:"status"`
+}
+
+func (geminiProvider) Name() string {
+	return ProviderGemini
+}
+
+func (geminiProvider) NewRequest(ctx context.Context, baseURL, apiKey string, request ZhipuAIRequest) (*http.Request, error) {
+	payload := geminiRequest{}
+	config := geminiGenerationConfig{
+		Temperature:      request.Temperature,
+		TopP:             request.TopP,
+		MaxOutputTokens:  request.MaxTokens,
+		StopSequences:    request.Stop,
+		PresencePenalty:  request.PresencePenalty,
+		FrequencyPenalty: request.FrequencyPenalty,
+		Seed:             request.Seed,
+	}
+	if config.Temperature != nil || config.TopP != nil || config.MaxOutputTokens != 0 || len(config.StopSequences) > 0 ||
+		config.PresencePenalty != nil || config.FrequencyPenalty != nil || config.Seed != nil {
+		payload.GenerationConfig = &config
+	}
+
+	for _, message := range request.Messages {
+		text, images := splitMessageContent(message.Content)
+		if message.Role == "system" {
+			if text == "" {
+				continue
+			}
+			if payload.SystemInstruction == nil {
+				payload.SystemInstruction = &geminiContent{}
+			}
+			payload.SystemInstruction.Parts = append(payload.SystemInstruction.Parts, geminiPart{Text: text})
+			continue
+		}
+
+		role := "user"
+		if message.Role == "assistant" {
+			role = "model"
+		}
+		content := geminiContent{Role: role}
+		if text != "" {
+			content.Parts = append(content.Parts, geminiPart{Text: text})
+		}
+		for _, image := range images {
+			content.Parts = append(content.Parts, geminiPart{
+				InlineData: &geminiInlineData{MimeType: image.mimeType, Data: image.data},
+			})
+		}
+		payload.Contents = append(payload.Contents, content)
+	}
+
+	jsonData, err := marshalWithExtra(payload, request.ExtraBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	target := baseURL + "/models/" + url.PathEscape(request.Model) + ":generateContent"
+	if request.Stream {
+		target = baseURL + "/models/" + url.PathEscape(request.Model) + ":streamGenerateContent?alt=sse"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", target, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("x-goog-api-key", apiKey)
+	}
+	return req, nil
+}
+
+func (geminiProvider) DecodeResponse(body []byte) (*ZhipuAIResponse, error) {
+	var response geminiResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+	if response.Error != nil {
+		return nil, newBodyError(ProviderGemini, response.Error.toAPIError())
+	}
+	if apiErr, blocked := response.promptBlocked(); blocked {
+		return nil, newBodyError(ProviderGemini, apiErr)
+	}
+
+	result := &ZhipuAIResponse{ID: response.ResponseID}
+	for index, candidate := range response.Candidates {
+		result.Choices = append(result.Choices, Choice{
+			Index: index,
+			Message: Message{
+				Role:             "assistant",
+				Content:          geminiCandidateText(candidate),
+				ReasoningContent: geminiCandidateThoughts(candidate),
+			},
+			FinishReason: geminiFinishReason(candidate.FinishReason),
+		})
+	}
+	if response.UsageMetadata != nil {
+		result.Usage = response.UsageMetadata.toUsage()
+	}
+	return result, nil
+}
+
+func (geminiProvider) DecodeStream(body io.Reader, emit func(StreamPart) error) error {
+	return readSSE(body, func(_ string, data string) error {
+		if strings.TrimSpace(data) == "" {
+			return nil
+		}
+
+		var chunk geminiResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("failed to unmarshal stream chunk: %v (raw: %s)", err, data)
+		}
+		if chunk.Error != nil {
+			return newBodyError(ProviderGemini, chunk.Error.toAPIError())
+		}
+		if apiErr, blocked := chunk.promptBlocked(); blocked {
+			return newBodyError(ProviderGemini, apiErr)
+		}
+
+		part := StreamPart{ID: chunk.ResponseID}
+		if len(chunk.Candidates) > 0 {
+			part.Content = geminiCandidateText(chunk.Candidates[0])
+			part.Reasoning = geminiCandidateThoughts(chunk.Candidates[0])
+			part.FinishReason = geminiFinishReason(chunk.Candidates[0].FinishReason)
+		}
+		if chunk.UsageMetadata != nil {
+			usage := chunk.UsageMetadata.toUsage()
+			part.Usage = &usage
+		}
+		return emit(part)
+	})
+}
+
+func (geminiProvider) DecodeError(body []byte) APIError {
+	var payload struct {
+		Error *geminiError `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Error == nil {
+		return APIError{}
+	}
+	return payload.Error.toAPIError()
+}
+
+func (geminiProvider) NewListModelsRequest(ctx context.Context, baseURL, apiKey string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"/models?pageSize=1000", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	if apiKey != "" {
+		req.Header.Set("x-goog-api-key", apiKey)
+	}
+	return req, nil
+}
+
+// DecodeModels 仅保留支持 generateContent 的模型，并去掉名称中的 "models/" 前缀
+func (geminiProvider) DecodeModels(body []byte) ([]ModelInfo, error) {
+	var payload struct {
+		Models *[]struct {
+			Name                       string   `json:"name"`
+			DisplayName                string   `json:"displayName"`
+			SupportedGenerationMethods []string `json:"supportedGenerationMethods"`
+		} `json:"models"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal models: %v", err)
+	}
+	if payload.Models == nil {
+		return nil, fmt.Errorf("models response has no models field")
+	}
+	models := make([]ModelInfo, 0, len(*payload.Models))
+	for _, item := range *payload.Models {
+		id := strings.TrimPrefix(item.Name, "models/")
+		if id == "" {
+			continue
+		}
+		if len(item.SupportedGenerationMethods) > 0 && !slices.Contains(item.SupportedGenerationMethods, "generateContent") {
+			continue
+		}
+		models = append(models, ModelInfo{ID: id, Name: item.DisplayName, OwnedBy: "google"})
+	}
+	return models, nil
+}
+
+func (e geminiError) toAPIError() APIError {
+	return APIError{Code: e.Status, Message: e.Message, Type: e.Status}
+}
+
+func (u geminiUsage) toUsage() Usage {
+	return Usage{
+		PromptTokens:     u.PromptTokenCount,
+		CompletionTokens: u.CandidatesTokenCount,
+		TotalTokens:      u.TotalTokenCount,
+	}
+}
+
+func geminiCandidateText(candidate geminiCandidate) string {
+	var text strings.Builder
+	for _, part := range candidate.Content.Parts {
+		if !part.Thought {
+			text.WriteString(part.Text)
+		}
+	}
+	return text.String()
+}
+
+func geminiCandidateThoughts(candidate geminiCandidate) string {
+	var text strings.Builder
+	for _, part := range candidate.Content.Parts {
+		if part.Thought {
+			text.WriteString(part.Text)
+		}
+	}
+	return text.String()
+}
+
+// promptBlocked 报告输入是否被安全策略拦截
+func (r geminiResponse) promptBlocked() (APIError, bool) {
+	if r.PromptFeedback == nil || r.PromptFeedback.BlockReason == "" || len(r.Candidates) > 0 {
+		return APIError{}, false
+	}
+	return APIError{
+		Code:    r.PromptFeedback.BlockReason,
+		Type:    FinishReasonContentFilter,
+		Message: "prompt blocked: " + r.PromptFeedback.BlockReason,
+	}, true
+}
+
+// geminiFinishReason 将 finishReason 映射为 Chat Completions 的 finish_reason
+func geminiFinishReason(reason string) string {
+	switch reason {
+	case "", "FINISH_REASON_UNSPECIFIED":
+		return ""
+	case "STOP":
+		return "stop"
+	case "MAX_TOKENS":
+		return "length"
+	case "SAFETY", "RECITATION", "BLOCKLIST", "PROHIBITED_CONTENT", "SPII", "IMAGE_SAFETY":
+		return "content_filter"
+	default:
+		return strings.ToLower(reason)
+	}
+}
@@ -0,0 +1,232 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ollamaProvider 实现 Ollama 原生 /api/chat 协议
+type ollamaProvider struct{}
+
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+	Options  *ollamaOptions  `json:"options,omitempty"`
+}
+
+type ollamaMessage struct {
+	Role    string   `json:"role"`
+	Content string   `json:"content"`
+	Images  []string `json:"images,omitempty"`
+	// Thinking 为开启思考模式的模型输出的推理内容
+	Thinking string `json:"thinking,omitempty"`
+}
+
+type ollamaOptions struct {
+	Temperature      *float64 `json:"temperature,omitempty"`
+	TopP             *float64 `json:"top_p,omitempty"`
+	NumPredict       int      `json:"num_predict,omitempty"`
+	PresencePenalty  *float64 `json:"presence_penalty,omitempty"`
+	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty"`
+	Seed             *int64   `json:"seed,omitempty"`
+	Stop             []string `json:"stop,omitempty"`
+}
+
+type ollamaResponse struct {
+	Model           string        `json:"model"`
+	CreatedAt       string        `json:"created_at"`
+	Message         ollamaMessage `json:"message"`
+	Done            bool          `json:"done"`
+	DoneReason      string        `json:"done_reason"`
+	PromptEvalCount int           `json:"prompt_eval_count"`
+	EvalCount       int           `json:"eval_count"`
+	Error           string        `json:"error,omitempty"`
+}
+
+func (ollamaProvider) Name() string {
+	return ProviderOllama
+}
+
+func (ollamaProvider) NewRequest(ctx context.Context, baseURL, apiKey string, request ZhipuAIRequest) (*http.Request, error) {
+	payload := ollamaRequest{
+		Model:  request.Model,
+		Stream: request.Stream,
+	}
+	options := ollamaOptions{
+		Temperature:      request.Temperature,
+		TopP:             request.TopP,
+		NumPredict:       request.MaxTokens,
+		PresencePenalty:  request.PresencePenalty,
+		FrequencyPenalty: request.FrequencyPenalty,
+		Seed:             request.Seed,
+		Stop:             request.Stop,
+	}
+	if options.Temperature != nil || options.TopP != nil || options.NumPredict != 0 || len(options.Stop) > 0 ||
+		options.PresencePenalty != nil || options.FrequencyPenalty != nil || options.Seed != nil {
+		payload.Options = &options
+	}
+
+	for _, message := range request.Messages {
+		text, images := splitMessageContent(message.Content)
+		converted := ollamaMessage{Role: message.Role, Content: text}
+		for _, image := range images {
+			converted.Images = append(converted.Images, image.data)
+		}
+		payload.Messages = append(payload.Messages, converted)
+	}
+
+	jsonData, err := marshalWithExtra(payload, request.ExtraBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/api/chat", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	return req, nil
+}
+
+func (ollamaProvider) DecodeResponse(body []byte) (*ZhipuAIResponse, error) {
+	var response ollamaResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+	if response.Error != "" {
+		return nil, newBodyError(ProviderOllama, APIError{Message: response.Error})
+	}
+
+	return &ZhipuAIResponse{
+		Object:  "chat.completion",
+		Created: ollamaCreated(response.CreatedAt),
+		Choices: []Choice{
+			{
+				Message: Message{
+					Role:             "assistant",
+					Content:          response.Message.Content,
+					ReasoningContent: response.Message.Thinking,
+				},
+				FinishReason: ollamaFinishReason(response),
+			},
+		},
+		Usage: response.usage(),
+	}, nil
+}
+
+func (ollamaProvider) DecodeStream(body io.Reader, emit func(StreamPart) error) error {
+	return readNDJSON(body, func(line string) error {
+		var chunk ollamaResponse
+		if err := json.Unmarshal([]byte(line), &chunk); err != nil {
+			return fmt.Errorf("failed to unmarshal stream chunk: %v (raw: %s)", err, line)
+		}
+		if chunk.Error != "" {
+			return newBodyError(ProviderOllama, APIError{Message: chunk.Error})
+		}
+
+		part := StreamPart{
+			Created:   ollamaCreated(chunk.CreatedAt),
+			Content:   chunk.Message.Content,
+			Reasoning: chunk.Message.Thinking,
+		}
+		if chunk.Done {
+			part.FinishReason = ollamaFinishReason(chunk)
+			usage := chunk.usage()
+			part.Usage = &usage
+		}
+		if err := emit(part); err != nil {
+			return err
+		}
+		if chunk.Done {
+			return io.EOF
+		}
+		return nil
+	})
+}
+
+func (ollamaProvider) DecodeError(body []byte) APIError {
+	var payload struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Error == "" {
+		return APIError{}
+	}
+	return APIError{Message: payload.Error}
+}
+
+func (ollamaProvider) NewListModelsRequest(ctx context.Context, baseURL, apiKey string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"/api/tags", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	return req, nil
+}
+
+// DecodeModels 解析 /api/tags 返回的本地模型列表
+func (ollamaProvider) DecodeModels(body []byte) ([]ModelInfo, error) {
+	var payload struct {
+		Models *[]struct {
+			Name  string `json:"name"`
+			Model string `json:"model"`
+		} `json:"models"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal models: %v", err)
+	}
+	if payload.Models == nil {
+		return nil, fmt.Errorf("models response has no models field")
+	}
+	models := make([]ModelInfo, 0, len(*payload.Models))
+	for _, item := range *payload.Models {
+		id := item.Model
+		if id == "" {
+			id = item.Name
+		}
+		if id != "" {
+			models = append(models, ModelInfo{ID: id, Name: item.Name})
+		}
+	}
+	return models, nil
+}
+
+func (r ollamaResponse) usage() Usage {
+	return Usage{
+		PromptTokens:     r.PromptEvalCount,
+		CompletionTokens: r.EvalCount,
+		TotalTokens:      r.PromptEvalCount + r.EvalCount,
+	}
+}
+
+func ollamaFinishReason(response ollamaResponse) string {
+	if !response.Done {
+		return ""
+	}
+	if response.DoneReason == "" {
+		return "stop"
+	}
+	return response.DoneReason
+}
+
+func ollamaCreated(value string) int64 {
+	if value == "" {
+		return 0
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return 0
+	}
+	return parsed.Unix()
+}
@@ -0,0 +1,180 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// openAIProvider 实现 OpenAI Chat Completions 兼容协议（智谱、DeepSeek 等均适用）
+type openAIProvider struct{}
+
+type streamChunk struct {
+	ID      string         `json:"id"`
+	Object  string         `json:"object"`
+	Created int64          `json:"created"`
+	Model   string         `json:"model"`
+	Choices []streamChoice `json:"choices"`
+	Usage   *Usage         `json:"usage,omitempty"`
+	Error   *APIError      `json:"error,omitempty"`
+}
+
+type streamChoice struct {
+	Index        int         `json:"index"`
+	Delta        streamDelta `json:"delta"`
+	FinishReason string      `json:"finish_reason"`
+}
+
+type streamDelta struct {
+	Role             string      `json:"role"`
+	Content          interface{} `json:"content"`
+	ReasoningContent string      `json:"reasoning_content"`
+	// Reasoning 为部分聚合服务（如 OpenRouter）使用的字段名
+	Reasoning string `json:"reasoning"`
+	Refusal   string `json:"refusal"`
+}
+
+func (openAIProvider) Name() string {
+	return ProviderOpenAI
+}
+
+func (openAIProvider) NewRequest(ctx context.Context, baseURL, apiKey string, request ZhipuAIRequest) (*http.Request, error) {
+	// 流式响应默认不带用量，需显式要求服务商在最后一个分块中返回
+	if request.Stream && request.StreamOptions == nil {
+		request.StreamOptions = &StreamOptions{IncludeUsage: true}
+	}
+	jsonData, err := marshalWithExtra(request, request.ExtraBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	return req, nil
+}
+
+func (openAIProvider) DecodeResponse(body []byte) (*ZhipuAIResponse, error) {
+	var response ZhipuAIResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+
+	if response.Error != nil {
+		return nil, newBodyError(ProviderOpenAI, *response.Error)
+	}
+
+	return &response, nil
+}
+
+func (openAIProvider) DecodeStream(body io.Reader, emit func(StreamPart) error) error {
+	return readSSE(body, func(_ string, data string) error {
+		if strings.TrimSpace(data) == "" {
+			return nil
+		}
+
+		var chunk streamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("failed to unmarshal stream chunk: %v (raw: %s)", err, data)
+		}
+		if chunk.Error != nil {
+			return newBodyError(ProviderOpenAI, *chunk.Error)
+		}
+
+		part := StreamPart{
+			ID:      chunk.ID,
+			Object:  chunk.Object,
+			Created: chunk.Created,
+			Usage:   chunk.Usage,
+		}
+		var text, reasoning, refusal strings.Builder
+		for _, choice := range chunk.Choices {
+			text.WriteString(streamContentToString(choice.Delta.Content))
+			reasoning.WriteString(choice.Delta.ReasoningContent)
+			reasoning.WriteString(choice.Delta.Reasoning)
+			refusal.WriteString(choice.Delta.Refusal)
+			if choice.FinishReason != "" {
+				part.FinishReason = choice.FinishReason
+			}
+		}
+		part.Content = text.String()
+		part.Reasoning = reasoning.String()
+		part.Refusal = refusal.String()
+		return emit(part)
+	})
+}
+
+func (openAIProvider) DecodeError(body []byte) APIError {
+	var payload struct {
+		Error *APIError `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Error == nil {
+		return APIError{}
+	}
+	return *payload.Error
+}
+
+func (openAIProvider) NewListModelsRequest(ctx context.Context, baseURL, apiKey string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"/models", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	return req, nil
+}
+
+func (openAIProvider) DecodeModels(body []byte) ([]ModelInfo, error) {
+	var payload struct {
+		Data *[]struct {
+			ID      string `json:"id"`
+			OwnedBy string `json:"owned_by"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal models: %v", err)
+	}
+	if payload.Data == nil {
+		return nil, fmt.Errorf("models response has no data field")
+	}
+	models := make([]ModelInfo, 0, len(*payload.Data))
+	for _, item := range *payload.Data {
+		if item.ID != "" {
+			models = append(models, ModelInfo{ID: item.ID, OwnedBy: item.OwnedBy})
+		}
+	}
+	return models, nil
+}
+
+func streamContentToString(content interface{}) string {
+	switch value := content.(type) {
+	case string:
+		return value
+	case []interface{}:
+		var builder strings.Builder
+		for _, item := range value {
+			switch piece := item.(type) {
+			case map[string]interface{}:
+				if pieceType, ok := piece["type"].(string); ok && pieceType == "text" {
+					if text, ok := piece["text"].(string); ok {
+						builder.WriteString(text)
+					}
+				}
+			}
+		}
+		return builder.String()
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,46 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+)
+
+func TestOpenAIRequestStreamOptions(t *testing.T) {
+	cases := []struct {
+		name   string
+		stream bool
+		want   string
+	}{
+		{name: "streaming requests usage", stream: true, want: `{"include_usage":true}`},
+		{name: "non-streaming omits stream_options", stream: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := ZhipuAIRequest{Model: "gpt-4o-mini", Stream: tc.stream}
+			req, err := openAIProvider{}.NewRequest(context.Background(), "https://example.com/v1", "key", request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var payload map[string]interface{}
+			if err := json.Unmarshal(body, &payload); err != nil {
+				t.Fatal(err)
+			}
+			got, ok := payload["stream_options"]
+			if tc.want == "" {
+				if ok {
+					t.Fatalf("stream_options = %v, want absent", got)
+				}
+				return
+			}
+			if encoded, _ := json.Marshal(got); string(encoded) != tc.want {
+				t.Fatalf("stream_options = %s, want %s", encoded, tc.want)
+			}
+		})
+	}
+}
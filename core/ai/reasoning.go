@@ -0,0 +1,149 @@
+package ai
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	thinkOpenTag  = "<think>"
+	thinkCloseTag = "</think>"
+)
+
+// thinkState 表示 thinkParser 所处的阶段
+type thinkState int
+
+const (
+	// thinkStart 表示尚未出现非空白内容
+	thinkStart thinkState = iota
+	// thinkInside 表示位于开头的推理块中
+	thinkInside
+	// thinkDone 表示推理块已结束或输出并非以推理块开头，之后的内容全部作为正文
+	thinkDone
+)
+
+// thinkParser 分离输出开头由 <think>…</think> 包裹的推理内容，标签可以跨越多个流式分片。
+// 只有位于输出最前面（允许前导空白）的推理块会被剥离，正文中出现的 <think> 标签原样保留
+type thinkParser struct {
+	state thinkState
+	// pending 保存尚无法判断归属的文本（开头的空白或标签前缀），等待下一个分片确认
+	pending string
+	// trimLeading 为 true 时丢弃推理块之后紧跟的空白
+	trimLeading bool
+}
+
+// feed 处理一个分片，返回其中的正文与推理内容
+func (p *thinkParser) feed(chunk string) (answer, reasoning string) {
+	buffer := p.pending + chunk
+	p.pending = ""
+
+	switch p.state {
+	case thinkStart:
+		trimmed := strings.TrimLeftFunc(buffer, unicode.IsSpace)
+		switch {
+		case strings.HasPrefix(trimmed, thinkOpenTag):
+			p.state = thinkInside
+			return p.feed(trimmed[len(thinkOpenTag):])
+		case strings.HasPrefix(thinkOpenTag, trimmed):
+			// 只有空白或标签前缀，等待更多内容
+			p.pending = buffer
+			return "", ""
+		}
+		p.state = thinkDone
+		return buffer, ""
+	case thinkInside:
+		index := strings.Index(buffer, thinkCloseTag)
+		if index < 0 {
+			keep := partialTagSuffix(buffer, thinkCloseTag)
+			p.pending = buffer[len(buffer)-keep:]
+			return "", buffer[:len(buffer)-keep]
+		}
+		p.state = thinkDone
+		p.trimLeading = true
+		return p.answer(buffer[index+len(thinkCloseTag):]), buffer[:index]
+	default:
+		return p.answer(buffer), ""
+	}
+}
+
+// flush 在输出结束时返回仍在等待确认的文本
+func (p *thinkParser) flush() (answer, reasoning string) {
+	pending := p.pending
+	p.pending = ""
+	if p.state == thinkInside {
+		return "", pending
+	}
+	return p.answer(pending), ""
+}
+
+// answer 返回正文，推理块之后的前导空白会被丢弃
+func (p *thinkParser) answer(text string) string {
+	if p.trimLeading {
+		text = strings.TrimLeftFunc(text, unicode.IsSpace)
+		if text == "" {
+			return ""
+		}
+		p.trimLeading = false
+	}
+	return text
+}
+
+// partialTagSuffix 返回 text 末尾与 tag 前缀重合的长度
+func partialTagSuffix(text, tag string) int {
+	limit := len(tag) - 1
+	if limit > len(text) {
+		limit = len(text)
+	}
+	for size := limit; size > 0; size-- {
+		if strings.HasSuffix(text, tag[:size]) {
+			return size
+		}
+	}
+	return 0
+}
+
+// splitThinkTags 将完整文本拆分为正文与开头的 <think> 推理内容
+func splitThinkTags(text string) (answer, reasoning string) {
+	if !strings.Contains(text, thinkOpenTag) {
+		return text, ""
+	}
+	var parser thinkParser
+	answer, reasoning = parser.feed(text)
+	restAnswer, restReasoning := parser.flush()
+	return answer + restAnswer, reasoning + restReasoning
+}
+
+// separateReasoning 将非流式响应中的推理内容统一放入 Message.ReasoningContent，
+// 并把多段内容归一化为字符串，保证 Message.Content 只包含正文
+func separateReasoning(response *ZhipuAIResponse) {
+	if response == nil {
+		return
+	}
+	for i := range response.Choices {
+		message := &response.Choices[i].Message
+		text, ok := message.Content.(string)
+		if !ok {
+			text = streamContentToString(message.Content)
+		}
+		answer, reasoning := splitThinkTags(text)
+		message.Content = answer
+		if reasoning != "" {
+			message.ReasoningContent = joinReasoning(message.ReasoningContent, reasoning)
+		}
+	}
+}
+
+func joinReasoning(existing, extra string) string {
+	if strings.TrimSpace(existing) == "" {
+		return extra
+	}
+	return existing + "\n" + extra
+}
+
+// Reasoning 返回首个选择项中的推理内容
+func (r *ZhipuAIResponse) Reasoning() string {
+	if r == nil || len(r.Choices) == 0 {
+		return ""
+	}
+	return r.Choices[0].Message.ReasoningContent
+}
@@ -0,0 +1,101 @@
+package ai
+
+import "testing"
+
+// feedAll 依次送入各分片并在结束时 flush，返回拼接后的正文与推理内容
+func feedAll(chunks ...string) (answer, reasoning string) {
+	var parser thinkParser
+	for _, chunk := range chunks {
+		a, r := parser.feed(chunk)
+		answer += a
+		reasoning += r
+	}
+	a, r := parser.flush()
+	return answer + a, reasoning + r
+}
+
+func TestThinkParser(t *testing.T) {
+	cases := []struct {
+		name          string
+		chunks        []string
+		wantAnswer    string
+		wantReasoning string
+	}{
+		{
+			name:       "no think block",
+			chunks:     []string{"Hello ", "world"},
+			wantAnswer: "Hello world",
+		},
+		{
+			name:          "leading block in one chunk",
+			chunks:        []string{"<think>plan</think>\n\n你好"},
+			wantAnswer:    "你好",
+			wantReasoning: "plan",
+		},
+		{
+			name:          "tags split across chunks",
+			chunks:        []string{"<th", "ink>pl", "an</thi", "nk>", "\n", "你好"},
+			wantAnswer:    "你好",
+			wantReasoning: "plan",
+		},
+		{
+			name:          "leading whitespace before block",
+			chunks:        []string{"\n ", "<think>plan</think>答案"},
+			wantAnswer:    "答案",
+			wantReasoning: "plan",
+		},
+		{
+			name:       "think tag inside the answer is kept",
+			chunks:     []string{"Use the <think> tag, then </think>."},
+			wantAnswer: "Use the <think> tag, then </think>.",
+		},
+		{
+			name:          "second block after answer is kept",
+			chunks:        []string{"<think>a</think>x <think>b</think> y"},
+			wantAnswer:    "x <think>b</think> y",
+			wantReasoning: "a",
+		},
+		{
+			name:       "answer starting with a tag prefix",
+			chunks:     []string{"<th", "is is text"},
+			wantAnswer: "<this is text",
+		},
+		{
+			name:       "unfinished tag prefix flushed as answer",
+			chunks:     []string{"<thi"},
+			wantAnswer: "<thi",
+		},
+		{
+			name:          "unterminated block stays reasoning",
+			chunks:        []string{"<think>still thinking</th"},
+			wantReasoning: "still thinking</th",
+		},
+		{
+			name:       "whitespace-only output",
+			chunks:     []string{"  ", "\n"},
+			wantAnswer: "  \n",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			answer, reasoning := feedAll(tc.chunks...)
+			if answer != tc.wantAnswer || reasoning != tc.wantReasoning {
+				t.Fatalf("got answer %q reasoning %q, want %q / %q", answer, reasoning, tc.wantAnswer, tc.wantReasoning)
+			}
+		})
+	}
+}
+
+func TestSeparateReasoningKeepsInlineTags(t *testing.T) {
+	response := &ZhipuAIResponse{Choices: []Choice{{Message: Message{
+		Content:          "<think>b</think>正文中的 <think> 标签",
+		ReasoningContent: "a",
+	}}}}
+	separateReasoning(response)
+	if got := response.Choices[0].Message.Content; got != "正文中的 <think> 标签" {
+		t.Fatalf("Content = %q", got)
+	}
+	if got := response.Reasoning(); got != "a\nb" {
+		t.Fatalf("Reasoning = %q", got)
+	}
+}
@@ -0,0 +1,151 @@
+package ai
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// FinishReasonContentFilter 表示输出被服务商内容过滤拦截（OpenAI、Azure 及映射后的 Anthropic、Gemini）
+	FinishReasonContentFilter = "content_filter"
+	// FinishReasonSensitive 为智谱在内容审核未通过时返回的结束原因
+	FinishReasonSensitive = "sensitive"
+)
+
+// maxRefusalRunes 为判定拒答时正文的最大长度，较长的输出视为正常译文
+const maxRefusalRunes = 200
+
+// refusalPrefixes 为常见的拒答开头，统一按小写比较
+var refusalPrefixes = []string{
+	"i'm sorry, but i can't",
+	"i'm sorry, but i cannot",
+	"i’m sorry, but i can’t",
+	"i am sorry, but i cannot",
+	"sorry, i can't help with",
+	"sorry, but i can't help with",
+	"i can't assist with",
+	"i cannot assist with",
+	"i can't help with that",
+	"i cannot help with that",
+	"i'm unable to help with",
+	"i am unable to comply",
+	"抱歉，我无法",
+	"抱歉，我不能",
+	"很抱歉，我无法",
+	"很抱歉，我不能",
+	"对不起，我无法",
+	"对不起，我不能",
+	"我无法协助",
+	"我不能协助",
+	"我无法提供",
+}
+
+// Blocked 报告响应是否因服务商内容过滤而终止
+func (r *ZhipuAIResponse) Blocked() bool {
+	if r == nil || len(r.Choices) == 0 {
+		return false
+	}
+	switch r.Choices[0].FinishReason {
+	case FinishReasonContentFilter, FinishReasonSensitive:
+		return true
+	}
+	return false
+}
+
+// Refusal 返回模型的拒答内容：优先使用服务商单独返回的 refusal 字段，
+// detect 为 true 时再按常见拒答句式识别较短的正文；未拒答时返回空字符串
+func (r *ZhipuAIResponse) Refusal(detect bool) string {
+	if r == nil || len(r.Choices) == 0 {
+		return ""
+	}
+	message := r.Choices[0].Message
+	if refusal := strings.TrimSpace(message.Refusal); refusal != "" {
+		return refusal
+	}
+	if !detect {
+		return ""
+	}
+	text := strings.TrimSpace(messageText(message.Content))
+	if text == "" || utf8.RuneCountInString(text) > maxRefusalRunes {
+		return ""
+	}
+	lower := strings.ToLower(text)
+	for _, prefix := range refusalPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return text
+		}
+	}
+	return ""
+}
+
+// containsRefusalPhrase 报告文本中是否出现常见拒答句式
+func containsRefusalPhrase(text string) bool {
+	lower := strings.ToLower(text)
+	for _, prefix := range refusalPrefixes {
+		if strings.Contains(lower, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sourceHasRefusalPhrase 报告请求的用户消息本身是否包含拒答句式。
+// 原文就是“抱歉，我无法…”这类道歉时，正确的译文同样形似拒答，不能按句式判定
+func sourceHasRefusalPhrase(request ZhipuAIRequest) bool {
+	for _, message := range request.Messages {
+		if message.Role != "user" {
+			continue
+		}
+		switch content := message.Content.(type) {
+		case string:
+			if containsRefusalPhrase(content) {
+				return true
+			}
+		case []ContentItem:
+			for _, item := range content {
+				if item.Type == "text" && containsRefusalPhrase(item.Text) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// blockedError 将被过滤或拒答的响应转换为 *RequestError，正常响应返回 nil。
+// 按句式识别拒答仅在开启 detectRefusals、且为原文不含拒答句式的文本翻译请求时进行：
+// 视觉请求的原文在图片中无从比对，画面上的道歉文字会被误判为拒答
+func (c *Client) blockedError(provider Provider, stage string, request ZhipuAIRequest, response *ZhipuAIResponse) error {
+	if response.Blocked() {
+		message := strings.TrimSpace(messageText(response.Choices[0].Message.Content))
+		if message == "" {
+			message = "response blocked by content filter"
+		}
+		return &RequestError{
+			Provider: provider.Name(),
+			Code:     response.Choices[0].FinishReason,
+			Message:  message,
+			Category: ErrorCategoryContentFilter,
+		}
+	}
+	detect := c.detectRefusals && stage == StageTranslate && !sourceHasRefusalPhrase(request)
+	if refusal := response.Refusal(detect); refusal != "" {
+		return &RequestError{
+			Provider: provider.Name(),
+			Code:     string(ErrorCategoryRefusal),
+			Message:  refusal,
+			Category: ErrorCategoryRefusal,
+		}
+	}
+	return nil
+}
+
+// IsBlocked 报告错误是否由内容过滤或模型拒答引起
+func IsBlocked(err error) bool {
+	var requestErr *RequestError
+	if !errors.As(err, &requestErr) {
+		return false
+	}
+	return requestErr.Category == ErrorCategoryContentFilter || requestErr.Category == ErrorCategoryRefusal
+}
@@ -0,0 +1,126 @@
+package ai
+
+import (
+	"errors"
+	"testing"
+)
+
+func textResponse(content, finishReason string) *ZhipuAIResponse {
+	return &ZhipuAIResponse{Choices: []Choice{{
+		Message:      Message{Role: "assistant", Content: content},
+		FinishReason: finishReason,
+	}}}
+}
+
+func translateRequest(source string) ZhipuAIRequest {
+	return ZhipuAIRequest{Messages: []Message{
+		{Role: "system", Content: "你是翻译助手"},
+		{Role: "user", Content: "请翻译：\n" + source},
+	}}
+}
+
+func TestBlockedError(t *testing.T) {
+	provider := NewProvider(ProviderOpenAI)
+	cases := []struct {
+		name     string
+		detect   bool
+		stage    string
+		request  ZhipuAIRequest
+		response *ZhipuAIResponse
+		want     ErrorCategory
+	}{
+		{
+			name:     "normal translation",
+			detect:   true,
+			request:  translateRequest("Hello"),
+			response: textResponse("你好", "stop"),
+		},
+		{
+			name:     "content filter finish reason",
+			request:  translateRequest("Hello"),
+			response: textResponse("", FinishReasonContentFilter),
+			want:     ErrorCategoryContentFilter,
+		},
+		{
+			name:    "provider refusal field always honoured",
+			request: translateRequest("Hello"),
+			response: &ZhipuAIResponse{Choices: []Choice{{
+				Message: Message{Role: "assistant", Refusal: "I can't help with that."},
+			}}},
+			want: ErrorCategoryRefusal,
+		},
+		{
+			name:     "apology text ignored when detection is off",
+			request:  translateRequest("Hello"),
+			response: textResponse("抱歉，我无法协助处理该请求。", "stop"),
+		},
+		{
+			name:     "apology text detected when detection is on",
+			detect:   true,
+			request:  translateRequest("Hello"),
+			response: textResponse("抱歉，我无法协助处理该请求。", "stop"),
+			want:     ErrorCategoryRefusal,
+		},
+		{
+			name:     "translated apology is not a refusal",
+			detect:   true,
+			request:  translateRequest("I'm sorry, but I can't make it tonight."),
+			response: textResponse("抱歉，我不能今晚赴约。", "stop"),
+		},
+		{
+			name:   "apology in multimodal source text",
+			detect: true,
+			request: ZhipuAIRequest{Messages: []Message{{Role: "user", Content: []ContentItem{
+				{Type: "text", Text: "抱歉，我无法参加明天的会议。"},
+			}}}},
+			response: textResponse("I'm sorry, but I can't attend tomorrow's meeting.", "stop"),
+		},
+		{
+			name:   "on-screen apology in vision direct mode",
+			detect: true,
+			stage:  StageDirect,
+			request: ZhipuAIRequest{Messages: []Message{{Role: "user", Content: []ContentItem{
+				{Type: "text", Text: "将图片中的文字翻译为中文"},
+				{Type: "image_url"},
+			}}}},
+			response: textResponse("抱歉，我无法连接到服务器。", "stop"),
+		},
+		{
+			name:     "on-screen apology in vision extract",
+			detect:   true,
+			stage:    StageOCR,
+			request:  translateRequest("提取图片中的文字"),
+			response: textResponse("I'm sorry, but I can't find that page.", "stop"),
+		},
+		{
+			name:     "content filter still applies to vision stages",
+			stage:    StageDirect,
+			request:  translateRequest("Hello"),
+			response: textResponse("", FinishReasonSensitive),
+			want:     ErrorCategoryContentFilter,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			stage := tc.stage
+			if stage == "" {
+				stage = StageTranslate
+			}
+			client := &Client{detectRefusals: tc.detect}
+			err := client.blockedError(provider, stage, tc.request, tc.response)
+			if tc.want == "" {
+				if err != nil {
+					t.Fatalf("blockedError() = %v, want nil", err)
+				}
+				return
+			}
+			var requestErr *RequestError
+			if !errors.As(err, &requestErr) || requestErr.Category != tc.want {
+				t.Fatalf("blockedError() = %v, want category %s", err, tc.want)
+			}
+			if !IsBlocked(err) {
+				t.Fatalf("IsBlocked(%v) = false", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy 控制请求失败后的重试行为，零值字段使用 DefaultRetryPolicy 中的取值
+type RetryPolicy struct {
+	// MaxAttempts 为总尝试次数（含首次），设为 1 表示不重试
+	MaxAttempts int
+	// InitialBackoff 为首次重试前的等待时间
+	InitialBackoff time.Duration
+	// MaxBackoff 为指数退避的等待上限
+	MaxBackoff time.Duration
+	// Multiplier 为每次重试等待时间的增长倍数
+	Multiplier float64
+	// Jitter 为随机抖动比例（0-1），用于打散并发请求的重试时刻
+	Jitter float64
+	// MaxRetryAfter 为可接受的 Retry-After 上限，超过时直接返回错误
+	MaxRetryAfter time.Duration
+	// RetryableStatuses 为需要重试的 HTTP 状态码
+	RetryableStatuses []int
+}
+
+// DefaultRetryPolicy 返回默认重试策略，覆盖免费模型常见的 429 与 5xx 错误
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     8 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		MaxRetryAfter:  30 * time.Second,
+		RetryableStatuses: []int{
+			http.StatusRequestTimeout,
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+func normalizeRetryPolicy(policy RetryPolicy) RetryPolicy {
+	defaults := DefaultRetryPolicy()
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = defaults.MaxAttempts
+	}
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = defaults.InitialBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = defaults.MaxBackoff
+	}
+	if policy.Multiplier < 1 {
+		policy.Multiplier = defaults.Multiplier
+	}
+	if policy.Jitter < 0 || policy.Jitter > 1 {
+		policy.Jitter = defaults.Jitter
+	}
+	if policy.MaxRetryAfter <= 0 {
+		policy.MaxRetryAfter = defaults.MaxRetryAfter
+	}
+	if len(policy.RetryableStatuses) == 0 {
+		policy.RetryableStatuses = defaults.RetryableStatuses
+	}
+	return policy
+}
+
+// run 执行 call，失败时按策略等待后重试。
+// canRetry 返回 false 时（例如流式内容已推送给调用方）不再重试。
+func (p RetryPolicy) run(ctx context.Context, call func() error, canRetry func() bool) error {
+	for attempt := 1; ; attempt++ {
+		err := call()
+		if err == nil {
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if attempt >= p.MaxAttempts || !p.retryable(err) {
+			return err
+		}
+		if canRetry != nil && !canRetry() {
+			return err
+		}
+
+		wait, ok := p.backoff(attempt, err)
+		if !ok {
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	var requestErr *RequestError
+	if errors.As(err, &requestErr) {
+		if requestErr.Category == ErrorCategoryNetwork {
+			return true
+		}
+		return slices.Contains(p.RetryableStatuses, requestErr.StatusCode)
+	}
+	return isNetError(err) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+func isNetError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// backoff 计算第 attempt 次失败后的等待时间；Retry-After 超出上限时返回 false
+func (p RetryPolicy) backoff(attempt int, err error) (time.Duration, bool) {
+	var requestErr *RequestError
+	if errors.As(err, &requestErr) && requestErr.RetryAfter > 0 {
+		if requestErr.RetryAfter > p.MaxRetryAfter {
+			return 0, false
+		}
+		return requestErr.RetryAfter, true
+	}
+
+	wait := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(attempt-1))
+	if wait > float64(p.MaxBackoff) {
+		wait = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		wait *= 1 - p.Jitter + rand.Float64()*2*p.Jitter
+	}
+	return time.Duration(wait), true
+}
+
+// parseRetryAfter 解析 Retry-After 头，支持秒数与 HTTP 日期两种格式
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		if wait := when.Sub(now); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
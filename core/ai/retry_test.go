@@ -0,0 +1,202 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const okChatResponse = `{"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],"usage":{"total_tokens":1}}`
+
+// flakyServer 前 failures 次请求返回 status（附带 retryAfter 头），之后返回成功响应
+func flakyServer(t *testing.T, failures int32, status int, retryAfter string, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			http.Error(w, `{"error":{"message":"try again"}}`, status)
+			return
+		}
+		w.Write([]byte(okChatResponse))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func retryClient(baseURL string, policy RetryPolicy) *Client {
+	return NewClient(ClientConfig{APIKey: "key", BaseURL: baseURL, Retry: policy})
+}
+
+func TestRetryRateLimitHonoursRetryAfter(t *testing.T) {
+	var hits atomic.Int32
+	server := flakyServer(t, 1, http.StatusTooManyRequests, "1", &hits)
+	client := retryClient(server.URL, RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+
+	started := time.Now()
+	if _, err := client.TranslateWithContext(context.Background(), "hello", ""); err != nil {
+		t.Fatal(err)
+	}
+	if hits.Load() != 2 {
+		t.Fatalf("hits = %d, want 2", hits.Load())
+	}
+	if elapsed := time.Since(started); elapsed < 900*time.Millisecond {
+		t.Fatalf("retried after %v, want Retry-After of 1s", elapsed)
+	}
+}
+
+func TestRetryAfterAboveLimitIsNotRetried(t *testing.T) {
+	var hits atomic.Int32
+	server := flakyServer(t, 1, http.StatusTooManyRequests, "120", &hits)
+	client := retryClient(server.URL, RetryPolicy{MaxAttempts: 3, MaxRetryAfter: time.Second})
+
+	_, err := client.TranslateWithContext(context.Background(), "hello", "")
+	var requestErr *RequestError
+	if !errors.As(err, &requestErr) || requestErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("err = %v, want 429 RequestError", err)
+	}
+	if requestErr.RetryAfter != 120*time.Second {
+		t.Fatalf("RetryAfter = %v", requestErr.RetryAfter)
+	}
+	if hits.Load() != 1 {
+		t.Fatalf("hits = %d, want 1", hits.Load())
+	}
+}
+
+func TestRetryServerErrors(t *testing.T) {
+	cases := []struct {
+		name        string
+		failures    int32
+		status      int
+		maxAttempts int
+		wantHits    int32
+		wantErr     bool
+	}{
+		{"503 recovers", 2, http.StatusServiceUnavailable, 3, 3, false},
+		{"502 exhausts attempts", 5, http.StatusBadGateway, 3, 3, true},
+		{"single attempt disables retry", 1, http.StatusInternalServerError, 1, 1, true},
+		{"400 is not retried", 1, http.StatusBadRequest, 3, 1, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var hits atomic.Int32
+			server := flakyServer(t, tc.failures, tc.status, "", &hits)
+			client := retryClient(server.URL, RetryPolicy{MaxAttempts: tc.maxAttempts, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond})
+
+			_, err := client.TranslateWithContext(context.Background(), "hello", "")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tc.wantErr)
+			}
+			if hits.Load() != tc.wantHits {
+				t.Fatalf("hits = %d, want %d", hits.Load(), tc.wantHits)
+			}
+		})
+	}
+}
+
+func TestRetryCancelledDuringWait(t *testing.T) {
+	var hits atomic.Int32
+	server := flakyServer(t, 10, http.StatusTooManyRequests, "10", &hits)
+	client := retryClient(server.URL, RetryPolicy{MaxAttempts: 3})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	started := time.Now()
+	_, err := client.TranslateWithContext(ctx, "hello", "")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Fatalf("cancellation took %v, wait was not interrupted", elapsed)
+	}
+	if hits.Load() != 1 {
+		t.Fatalf("hits = %d, want 1", hits.Load())
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond, Multiplier: 2, MaxRetryAfter: time.Minute}
+	for attempt, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond} {
+		if got, ok := policy.backoff(attempt+1, errors.New("network")); !ok || got != want {
+			t.Errorf("backoff(%d) = %v, %v, want %v", attempt+1, got, ok, want)
+		}
+	}
+	if got, ok := policy.backoff(1, &RequestError{RetryAfter: 5 * time.Second}); !ok || got != 5*time.Second {
+		t.Errorf("backoff with Retry-After = %v, %v", got, ok)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"3", 3 * time.Second},
+		{"-1", 0},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"soon", 0},
+	}
+	for _, tc := range cases {
+		if got := parseRetryAfter(tc.value, now); got != tc.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tc.value, got, tc.want)
+		}
+	}
+}
+
+// brokenStreamServer 首次请求先推送 chunks 再中断连接，之后返回完整的流式响应
+func brokenStreamServer(t *testing.T, chunks []string, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+		if hits.Add(1) == 1 {
+			for _, chunk := range chunks {
+				fmt.Fprintf(w, "data: %s\n\n", chunk)
+			}
+			flusher.Flush()
+			panic(http.ErrAbortHandler)
+		}
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"ok\"},\"finish_reason\":\"stop\"}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRetryStreamOnlyBeforeFirstEvent(t *testing.T) {
+	cases := []struct {
+		name     string
+		chunks   []string
+		wantHits int32
+		wantErr  bool
+	}{
+		{"fails before any event", nil, 2, false},
+		{"fails after delta", []string{`{"choices":[{"delta":{"content":"He"}}]}`}, 1, true},
+		{"fails after reasoning", []string{`{"choices":[{"delta":{"reasoning_content":"plan"}}]}`}, 1, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var hits atomic.Int32
+			server := brokenStreamServer(t, tc.chunks, &hits)
+			client := retryClient(server.URL, RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond})
+
+			_, err := client.TranslateStreamWithContext(context.Background(), "hello", "", func(StreamEvent) {})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tc.wantErr)
+			}
+			if hits.Load() != tc.wantHits {
+				t.Fatalf("hits = %d, want %d", hits.Load(), tc.wantHits)
+			}
+		})
+	}
+}
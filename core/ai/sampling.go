@@ -0,0 +1,101 @@
+package ai
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Sampling 描述一次请求的采样参数，未设置的字段沿用客户端默认值或由服务端决定
+type Sampling struct {
+	Temperature      *float64 `json:"temperature,omitempty"`
+	TopP             *float64 `json:"topP,omitempty"`
+	MaxTokens        int      `json:"maxTokens,omitempty"`
+	PresencePenalty  *float64 `json:"presencePenalty,omitempty"`
+	FrequencyPenalty *float64 `json:"frequencyPenalty,omitempty"`
+	Seed             *int64   `json:"seed,omitempty"`
+	Stop             []string `json:"stop,omitempty"`
+	// ExtraBody 会合并到请求体顶层，用于传递服务商特有的参数
+	ExtraBody map[string]interface{} `json:"extraBody,omitempty"`
+}
+
+// RequestOption 在发送前调整请求
+type RequestOption func(request *ZhipuAIRequest)
+
+// WithSampling 使用 sampling 中已设置的字段覆盖请求的采样参数
+func WithSampling(sampling Sampling) RequestOption {
+	return func(request *ZhipuAIRequest) {
+		if sampling.Temperature != nil {
+			request.Temperature = sampling.Temperature
+		}
+		if sampling.TopP != nil {
+			request.TopP = sampling.TopP
+		}
+		if sampling.MaxTokens > 0 {
+			request.MaxTokens = sampling.MaxTokens
+		}
+		if sampling.PresencePenalty != nil {
+			request.PresencePenalty = sampling.PresencePenalty
+		}
+		if sampling.FrequencyPenalty != nil {
+			request.FrequencyPenalty = sampling.FrequencyPenalty
+		}
+		if sampling.Seed != nil {
+			request.Seed = sampling.Seed
+		}
+		if stop := cleanStop(sampling.Stop); len(stop) > 0 {
+			request.Stop = stop
+		}
+		if len(sampling.ExtraBody) > 0 {
+			if request.ExtraBody == nil {
+				request.ExtraBody = map[string]interface{}{}
+			}
+			for key, value := range sampling.ExtraBody {
+				request.ExtraBody[key] = value
+			}
+		}
+	}
+}
+
+// Float64 返回 v 的指针，便于构造 Sampling
+func Float64(v float64) *float64 {
+	return &v
+}
+
+func applyOptions(request *ZhipuAIRequest, opts []RequestOption) {
+	for _, opt := range opts {
+		if opt != nil {
+			opt(request)
+		}
+	}
+}
+
+func cleanStop(stop []string) []string {
+	var result []string
+	for _, value := range stop {
+		if value != "" && strings.TrimSpace(value) != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// marshalWithExtra 序列化 payload，并将 extra 中的键合并到 JSON 顶层（extra 优先）
+func marshalWithExtra(payload interface{}, extra map[string]interface{}) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	var merged map[string]json.RawMessage
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return nil, err
+	}
+	for key, value := range extra {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		merged[key] = encoded
+	}
+	return json.Marshal(merged)
+}
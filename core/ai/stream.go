@@ -0,0 +1,21 @@
+package ai
+
+// StreamEvent 描述流式响应中的一次增量。
+// 调用方需自行拼接 Delta；最后一个事件携带 FinishReason，服务商返回用量时附带 Usage。
+type StreamEvent struct {
+	// Delta 为本次新增的文本，可能为空（例如仅携带结束原因或用量的事件）
+	Delta string
+	// Reasoning 为本次新增的推理内容（reasoning_content 或 <think> 标签内的文本），不计入 Length
+	Reasoning string
+	// Length 为截至本次事件累计输出的字符数（按 rune 计），便于调用方校验是否漏收
+	Length int
+	// FinishReason 为结束原因，例如 "stop"、"length"，未结束时为空
+	FinishReason string
+	// Usage 为服务商返回的 token 用量，未返回时为 nil
+	Usage *Usage
+	// Reset 表示此前推送的增量已作废（被拦截后切换到备用接口重新生成），调用方应先清空再追加 Delta
+	Reset bool
+}
+
+// StreamHandler 接收流式响应中的增量事件
+type StreamHandler func(event StreamEvent)
@@ -0,0 +1,202 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// StageCapture 表示截图阶段，仅用于超时错误中标识阶段
+const StageCapture = "capture"
+
+// TimeoutLimit 标识触发超时的时限类型
+type TimeoutLimit string
+
+const (
+	// TimeoutConnect 表示建立连接（含 TLS 握手）超时
+	TimeoutConnect TimeoutLimit = "connect"
+	// TimeoutFirstToken 表示流式请求迟迟没有收到首个增量
+	TimeoutFirstToken TimeoutLimit = "first_token"
+	// TimeoutIdle 表示流式响应两个分片之间的间隔过长
+	TimeoutIdle TimeoutLimit = "idle"
+	// TimeoutTotal 表示整个阶段超出总时长预算
+	TimeoutTotal TimeoutLimit = "total"
+)
+
+// Timeouts 控制单次请求的时限，零值字段表示不限制
+type Timeouts struct {
+	// Connect 为建立连接（含 TLS 握手）的时限，仅在未指定 HTTPClient 时生效
+	Connect time.Duration
+	// FirstToken 为流式请求从发出到收到首个正文或推理增量的时限
+	FirstToken time.Duration
+	// Idle 为流式响应相邻两个分片之间的最大间隔
+	Idle time.Duration
+}
+
+// TimeoutError 描述某个阶段超出的时限，可通过 errors.As 获取
+type TimeoutError struct {
+	// Stage 为超时的阶段（capture、ocr、translate、direct）
+	Stage    string
+	Limit    TimeoutLimit
+	Duration time.Duration
+	// Partial 为超时前已收到的正文，便于调用方保留部分译文
+	Partial string
+}
+
+func (e *TimeoutError) Error() string {
+	stage := e.Stage
+	if stage == "" {
+		stage = "request"
+	}
+	return fmt.Sprintf("%s stage exceeded %s timeout of %s", stage, e.Limit, e.Duration)
+}
+
+// UserMessage 返回适合直接展示给用户的超时说明
+func (e *TimeoutError) UserMessage() string {
+	stage := "请求"
+	switch e.Stage {
+	case StageCapture:
+		stage = "截图"
+	case StageOCR:
+		stage = "文字提取"
+	case StageTranslate:
+		stage = "翻译"
+	case StageDirect:
+		stage = "视觉直出翻译"
+	}
+
+	var limit string
+	switch e.Limit {
+	case TimeoutConnect:
+		limit = "连接超时"
+	case TimeoutFirstToken:
+		limit = "等待首个输出超时"
+	case TimeoutIdle:
+		limit = "输出中断超时"
+	default:
+		limit = "超出总时长"
+	}
+
+	message := fmt.Sprintf("%s阶段%s（%s）", stage, limit, e.Duration)
+	if e.Partial != "" {
+		message += "，已保留部分译文"
+	}
+	return message
+}
+
+// newHTTPClient 创建带连接超时的 HTTP 客户端；响应体读取由流式看门狗与调用方的 context 控制
+func newHTTPClient(connect time.Duration) *http.Client {
+	if connect <= 0 {
+		return &http.Client{}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	dialer := &net.Dialer{Timeout: connect, KeepAlive: 30 * time.Second}
+	transport.DialContext = dialer.DialContext
+	transport.TLSHandshakeTimeout = connect
+	return &http.Client{Transport: transport}
+}
+
+// connectError 将拨号或 TLS 握手超时转换为 *TimeoutError，其他错误原样返回
+func (c *Client) connectError(ctx context.Context, err error) error {
+	if c.timeouts.Connect <= 0 || ctx.Err() != nil {
+		return err
+	}
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		return err
+	}
+	return &TimeoutError{Limit: TimeoutConnect, Duration: c.timeouts.Connect}
+}
+
+// setTimeoutStage 为尚未标注阶段的超时错误补充阶段名称
+func setTimeoutStage(err error, stage string) {
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) && timeoutErr.Stage == "" {
+		timeoutErr.Stage = stage
+	}
+}
+
+// streamWatchdog 监控流式响应：收到首个增量前受 FirstToken 约束，之后每个分片都会重置 Idle 计时。
+// 触发时取消请求的 context，使阻塞中的读取立即返回。
+type streamWatchdog struct {
+	timeouts Timeouts
+	cancel   context.CancelFunc
+
+	mu         sync.Mutex
+	timer      *time.Timer
+	generation int
+	gotToken   bool
+	fired      *TimeoutError
+}
+
+func startStreamWatchdog(timeouts Timeouts, cancel context.CancelFunc) *streamWatchdog {
+	w := &streamWatchdog{timeouts: timeouts, cancel: cancel}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	switch {
+	case timeouts.FirstToken > 0:
+		w.arm(TimeoutFirstToken, timeouts.FirstToken)
+	case timeouts.Idle > 0:
+		w.arm(TimeoutIdle, timeouts.Idle)
+	}
+	return w
+}
+
+// observe 记录收到一个分片，token 表示分片中含有正文或推理内容
+func (w *streamWatchdog) observe(token bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.fired != nil {
+		return
+	}
+	if token {
+		w.gotToken = true
+	}
+	if !w.gotToken && w.timeouts.FirstToken > 0 {
+		return
+	}
+	if w.timeouts.Idle > 0 {
+		w.arm(TimeoutIdle, w.timeouts.Idle)
+	} else {
+		w.disarm()
+	}
+}
+
+// stop 停止计时并返回已触发的超时，未触发时返回 nil
+func (w *streamWatchdog) stop() *TimeoutError {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.disarm()
+	if w.fired == nil {
+		return nil
+	}
+	fired := *w.fired
+	return &fired
+}
+
+func (w *streamWatchdog) arm(limit TimeoutLimit, duration time.Duration) {
+	w.disarm()
+	generation := w.generation
+	w.timer = time.AfterFunc(duration, func() {
+		w.mu.Lock()
+		if w.generation != generation || w.fired != nil {
+			w.mu.Unlock()
+			return
+		}
+		w.fired = &TimeoutError{Limit: limit, Duration: duration}
+		w.mu.Unlock()
+		w.cancel()
+	})
+}
+
+func (w *streamWatchdog) disarm() {
+	w.generation++
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+}
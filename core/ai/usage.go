@@ -0,0 +1,51 @@
+package ai
+
+import "time"
+
+const (
+	// EndpointTranslate 表示文本翻译接口
+	EndpointTranslate = "translate"
+	// EndpointVision 表示视觉接口
+	EndpointVision = "vision"
+)
+
+const (
+	// StageOCR 表示视觉模型提取原文
+	StageOCR = "ocr"
+	// StageTranslate 表示文本模型翻译
+	StageTranslate = "translate"
+	// StageDirect 表示视觉模型直出译文
+	StageDirect = "direct"
+)
+
+// UsageEvent 描述一次成功调用消耗的 token
+type UsageEvent struct {
+	Time     time.Time
+	Endpoint string
+	Provider string
+	Model    string
+	Stage    string
+	Usage    Usage
+	// UsageMissing 为 true 表示服务商未返回用量，Usage 中的 0 不代表实际消耗
+	UsageMissing bool
+}
+
+// UsageObserver 接收每次调用的用量，由 ClientConfig.OnUsage 注入
+type UsageObserver func(event UsageEvent)
+
+func (c *Client) reportUsage(target endpoint, stage string, response *ZhipuAIResponse) {
+	if c.onUsage == nil || response == nil {
+		return
+	}
+	usage := response.Usage
+	c.onUsage(UsageEvent{
+		Time:     time.Now(),
+		Endpoint: target.name,
+		Provider: target.provider.Name(),
+		Model:    target.model,
+		Stage:    stage,
+		Usage:    usage,
+		// 流式响应缺少末尾的用量分块时各项计数均为 0
+		UsageMissing: usage.PromptTokens == 0 && usage.CompletionTokens == 0 && usage.TotalTokens == 0,
+	})
+}
@@ -0,0 +1,321 @@
+package cache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DirName 是结果缓存在配置目录下的子目录名
+const DirName = "cache"
+
+const entryExt = ".json"
+
+// 默认缓存参数
+const (
+	DefaultTTL           = 7 * 24 * time.Hour
+	DefaultMaxDiskBytes  = 64 << 20
+	DefaultMemoryEntries = 128
+)
+
+// Options 控制缓存的有效期与容量
+type Options struct {
+	// TTL 为条目有效期，<=0 时使用 DefaultTTL
+	TTL time.Duration
+	// MaxDiskBytes 为磁盘缓存的总大小上限，超出时淘汰最旧的条目
+	MaxDiskBytes int64
+	// MemoryEntries 为内存 LRU 保留的条目数
+	MemoryEntries int
+}
+
+// Cache 是内存 LRU 与磁盘存储组成的两级内容寻址缓存
+type Cache struct {
+	mu       sync.Mutex
+	dir      string
+	opts     Options
+	order    *list.List
+	items    map[string]*list.Element
+	diskSize int64
+}
+
+type entry struct {
+	Key     string          `json:"key"`
+	Created time.Time       `json:"created"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// New 创建缓存，dir 为空时仅使用内存层
+func New(dir string, opts Options) (*Cache, error) {
+	c := &Cache{
+		dir:   dir,
+		opts:  normalizeOptions(opts),
+		order: list.New(),
+		items: map[string]*list.Element{},
+	}
+	if dir == "" {
+		return c, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return c, err
+	}
+	size, err := c.scanDisk()
+	c.diskSize = size
+	return c, err
+}
+
+// Key 将若干组成部分合并为内容寻址的缓存键
+func Key(parts ...[]byte) string {
+	hash := sha256.New()
+	var length [8]byte
+	for _, part := range parts {
+		// 写入长度前缀，避免不同切分方式得到相同的键
+		binary.BigEndian.PutUint64(length[:], uint64(len(part)))
+		hash.Write(length[:])
+		hash.Write(part)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Configure 更新缓存参数，容量缩小时立即淘汰
+func (c *Cache) Configure(opts Options) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.opts = normalizeOptions(opts)
+	c.trimMemoryLocked()
+	c.trimDiskLocked()
+}
+
+// Get 读取并解码缓存条目，未命中或已过期时返回 false
+func (c *Cache) Get(key string, value interface{}) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, ok := c.lookupLocked(key)
+	if !ok {
+		return false
+	}
+	return json.Unmarshal(item.Value, value) == nil
+}
+
+// Set 写入缓存条目，同时落盘
+func (c *Cache) Set(key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	item := &entry{Key: key, Created: time.Now(), Value: data}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rememberLocked(item)
+	if c.dir == "" {
+		return nil
+	}
+	return c.writeLocked(item)
+}
+
+// Clear 清空内存与磁盘中的全部条目
+func (c *Cache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	c.items = map[string]*list.Element{}
+	c.diskSize = 0
+	if c.dir == "" {
+		return nil
+	}
+	if err := os.RemoveAll(c.dir); err != nil {
+		return err
+	}
+	return os.MkdirAll(c.dir, 0o755)
+}
+
+func normalizeOptions(opts Options) Options {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTTL
+	}
+	if opts.MaxDiskBytes <= 0 {
+		opts.MaxDiskBytes = DefaultMaxDiskBytes
+	}
+	if opts.MemoryEntries <= 0 {
+		opts.MemoryEntries = DefaultMemoryEntries
+	}
+	return opts
+}
+
+func (c *Cache) expired(item *entry) bool {
+	return time.Since(item.Created) > c.opts.TTL
+}
+
+func (c *Cache) lookupLocked(key string) (*entry, bool) {
+	if element, ok := c.items[key]; ok {
+		item := element.Value.(*entry)
+		if c.expired(item) {
+			c.forgetLocked(key)
+			c.removeFileLocked(key)
+			return nil, false
+		}
+		c.order.MoveToFront(element)
+		return item, true
+	}
+
+	if c.dir == "" {
+		return nil, false
+	}
+	data, err := os.ReadFile(c.pathFor(key))
+	if err != nil {
+		return nil, false
+	}
+	var item entry
+	if err := json.Unmarshal(data, &item); err != nil || item.Key != key || c.expired(&item) {
+		c.removeFileLocked(key)
+		return nil, false
+	}
+	c.rememberLocked(&item)
+	return &item, true
+}
+
+func (c *Cache) rememberLocked(item *entry) {
+	if element, ok := c.items[item.Key]; ok {
+		element.Value = item
+		c.order.MoveToFront(element)
+		return
+	}
+	c.items[item.Key] = c.order.PushFront(item)
+	c.trimMemoryLocked()
+}
+
+func (c *Cache) forgetLocked(key string) {
+	if element, ok := c.items[key]; ok {
+		c.order.Remove(element)
+		delete(c.items, key)
+	}
+}
+
+func (c *Cache) trimMemoryLocked() {
+	for c.order.Len() > c.opts.MemoryEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*entry).Key)
+	}
+}
+
+func (c *Cache) pathFor(key string) string {
+	return filepath.Join(c.dir, key[:2], key+entryExt)
+}
+
+func (c *Cache) writeLocked(item *entry) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	path := c.pathFor(item.Key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if info, err := os.Stat(path); err == nil {
+		c.diskSize -= info.Size()
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return err
+	}
+	c.diskSize += int64(len(data))
+	c.trimDiskLocked()
+	return nil
+}
+
+func (c *Cache) removeFileLocked(key string) {
+	if c.dir == "" {
+		return
+	}
+	path := c.pathFor(key)
+	if info, err := os.Stat(path); err == nil {
+		if os.Remove(path) == nil {
+			c.diskSize -= info.Size()
+		}
+	}
+}
+
+type diskFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+func (c *Cache) listDisk() ([]diskFile, error) {
+	var files []diskFile
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), entryExt) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files = append(files, diskFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		return nil
+	})
+	return files, err
+}
+
+// scanDisk 统计磁盘占用并清理过期条目
+func (c *Cache) scanDisk() (int64, error) {
+	files, err := c.listDisk()
+	var total int64
+	for _, file := range files {
+		if time.Since(file.modTime) > c.opts.TTL {
+			os.Remove(file.path)
+			continue
+		}
+		total += file.size
+	}
+	return total, err
+}
+
+// trimDiskLocked 在超出容量时按写入时间淘汰最旧的条目
+func (c *Cache) trimDiskLocked() {
+	if c.dir == "" || c.diskSize <= c.opts.MaxDiskBytes {
+		return
+	}
+	files, err := c.listDisk()
+	if err != nil {
+		return
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+
+	var total int64
+	for _, file := range files {
+		total += file.size
+	}
+	for _, file := range files {
+		if total <= c.opts.MaxDiskBytes {
+			break
+		}
+		if os.Remove(file.path) == nil {
+			total -= file.size
+			c.forgetLocked(strings.TrimSuffix(filepath.Base(file.path), entryExt))
+		}
+	}
+	c.diskSize = total
+}
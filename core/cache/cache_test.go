@@ -0,0 +1,179 @@
+package cache
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func mustSet(t *testing.T, c *Cache, key, value string) {
+	t.Helper()
+	if err := c.Set(key, value); err != nil {
+		t.Fatalf("Set(%s): %v", key, err)
+	}
+}
+
+func has(c *Cache, key string) bool {
+	var value string
+	return c.Get(key, &value)
+}
+
+// age 将条目文件的修改时间改为 d 之前，用于控制磁盘淘汰顺序与过期
+func age(t *testing.T, c *Cache, key string, d time.Duration) {
+	t.Helper()
+	when := time.Now().Add(-d)
+	if err := os.Chtimes(c.pathFor(key), when, when); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKeyIsLengthPrefixed(t *testing.T) {
+	if Key([]byte("ab"), []byte("c")) == Key([]byte("a"), []byte("bc")) {
+		t.Fatal("different splits produced the same key")
+	}
+	if Key([]byte("a")) != Key([]byte("a")) {
+		t.Fatal("key is not deterministic")
+	}
+}
+
+func TestMemoryLRUEviction(t *testing.T) {
+	c, err := New("", Options{MemoryEntries: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b, d := Key([]byte("a")), Key([]byte("b")), Key([]byte("d"))
+	mustSet(t, c, a, "A")
+	mustSet(t, c, b, "B")
+	// 读取 a 使其成为最近使用，随后写入 d 应淘汰 b
+	if !has(c, a) {
+		t.Fatal("a missing before eviction")
+	}
+	mustSet(t, c, d, "D")
+
+	if !has(c, a) || !has(c, d) {
+		t.Fatal("recently used entries were evicted")
+	}
+	if has(c, b) {
+		t.Fatal("least recently used entry was not evicted")
+	}
+}
+
+func TestConfigureShrinksMemory(t *testing.T) {
+	c, err := New("", Options{MemoryEntries: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := []string{Key([]byte("1")), Key([]byte("2")), Key([]byte("3"))}
+	for _, key := range keys {
+		mustSet(t, c, key, key)
+	}
+	c.Configure(Options{MemoryEntries: 1})
+	if has(c, keys[0]) || has(c, keys[1]) || !has(c, keys[2]) {
+		t.Fatal("Configure did not keep only the newest entry")
+	}
+}
+
+func TestMemoryEvictedEntryReadFromDisk(t *testing.T) {
+	c, err := New(t.TempDir(), Options{MemoryEntries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := Key([]byte("a")), Key([]byte("b"))
+	mustSet(t, c, a, "A")
+	mustSet(t, c, b, "B")
+
+	var value string
+	if !c.Get(a, &value) || value != "A" {
+		t.Fatalf("Get(a) = %q, want value reloaded from disk", value)
+	}
+}
+
+func TestDiskEvictsOldestBeyondLimit(t *testing.T) {
+	dir := t.TempDir()
+	c, err := New(dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b, d := Key([]byte("a")), Key([]byte("b")), Key([]byte("d"))
+	mustSet(t, c, a, "A")
+	info, err := os.Stat(c.pathFor(a))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustSet(t, c, b, "B")
+	age(t, c, a, 2*time.Hour)
+	age(t, c, b, time.Hour)
+
+	// 容量只够两条，写入第三条时按修改时间淘汰最旧的 a
+	c.Configure(Options{MaxDiskBytes: info.Size()*2 + info.Size()/2})
+	mustSet(t, c, d, "D")
+
+	if _, err := os.Stat(c.pathFor(a)); !os.IsNotExist(err) {
+		t.Fatalf("oldest entry file still present: %v", err)
+	}
+	if has(c, a) {
+		t.Fatal("evicted entry still served from memory")
+	}
+	if !has(c, b) || !has(c, d) {
+		t.Fatal("newer entries were evicted")
+	}
+	if c.diskSize > info.Size()*2+info.Size()/2 {
+		t.Fatalf("diskSize = %d exceeds limit", c.diskSize)
+	}
+}
+
+func TestExpiredEntries(t *testing.T) {
+	c, err := New("", Options{TTL: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := Key([]byte("k"))
+	mustSet(t, c, key, "V")
+	time.Sleep(5 * time.Millisecond)
+	if has(c, key) {
+		t.Fatal("expired memory entry returned")
+	}
+}
+
+func TestExpiredFilesRemovedOnOpen(t *testing.T) {
+	dir := t.TempDir()
+	c, err := New(dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, fresh := Key([]byte("old")), Key([]byte("fresh"))
+	mustSet(t, c, old, "O")
+	mustSet(t, c, fresh, "F")
+	age(t, c, old, 2*time.Hour)
+
+	reopened, err := New(dir, Options{TTL: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(reopened.pathFor(old)); !os.IsNotExist(err) {
+		t.Fatalf("expired file not removed: %v", err)
+	}
+	if !has(reopened, fresh) {
+		t.Fatal("fresh entry lost after reopening")
+	}
+}
+
+func TestClearRemovesEverything(t *testing.T) {
+	dir := t.TempDir()
+	c, err := New(dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := Key([]byte("k"))
+	mustSet(t, c, key, "V")
+	if err := c.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if has(c, key) {
+		t.Fatal("entry still present after Clear")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("cache dir after Clear: %v, %v", entries, err)
+	}
+}
@@ -7,9 +7,13 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"Translater/core/ai"
+	"Translater/core/cache"
+	"Translater/core/imageproc"
 	"Translater/core/prompts"
+	"Translater/core/usage"
 )
 
 // Settings 保存桌面端可配置项
@@ -20,6 +24,12 @@ type Settings struct {
 	Theme                   string `json:"theme"`
 	ShowToastOnComplete     bool   `json:"showToastOnComplete"`
 	EnableStreamOutput      bool   `json:"enableStreamOutput"`
+	PipelineTranslation     bool   `json:"pipelineTranslation"`
+	ShowReasoning           bool   `json:"showReasoning"`
+	AutoContinue            bool   `json:"autoContinue"`
+	MaxContinuations        int    `json:"maxContinuations"`
+	DetectRefusals          bool   `json:"detectRefusals"`
+	FallbackOnBlocked       bool   `json:"fallbackOnBlocked"`
 	HotkeyCombination       string `json:"hotkeyCombination"`
 	ExtractPrompt           string `json:"extractPrompt"`
 	TranslatePrompt         string `json:"translatePrompt"`
@@ -28,9 +38,121 @@ type Settings struct {
 	VisionModel             string `json:"visionModel"`
 	VisionAPIBaseURL        string `json:"visionApiBaseUrl"`
 	VisionAPIKeyOverride    string `json:"visionApiKeyOverride"`
+	TranslateProvider       string `json:"translateProvider"`
+	VisionProvider          string `json:"visionProvider"`
 	UseVisionForTranslation bool   `json:"useVisionForTranslation"`
+	LayoutBlocks            bool   `json:"layoutBlocks"`
 	SourceLanguage          string `json:"sourceLanguage"`
 	TargetLanguage          string `json:"targetLanguage"`
+	// RepeatHotkeyCombination 为重复截取上一次区域的热键，留空表示不绑定
+	RepeatHotkeyCombination string `json:"repeatHotkeyCombination"`
+	// CaptureRegions 为固定的命名截图区域，可分别绑定热键
+	CaptureRegions []CaptureRegion `json:"captureRegions,omitempty"`
+	// Watch 为区域监视模式的轮询与变化判定设置
+	Watch WatchSettings `json:"watch"`
+	// CaptureBackend 为截图后端："auto" 按平台自动选择，"x11" 直接通过 X11 协议截图
+	CaptureBackend string `json:"captureBackend"`
+	// ModelPrices 为各模型每百万 token 的单价，用于用量账本估算费用
+	ModelPrices map[string]usage.Price `json:"modelPrices,omitempty"`
+	// Budget 为每日请求数、每日 token 与每月费用的软/硬上限，0 表示不限制
+	Budget usage.Budget `json:"budget"`
+	// TranslateFallbacks 与 VisionFallbacks 为主接口失败时按顺序尝试的备用接口
+	TranslateFallbacks []ai.EndpointConfig `json:"translateFallbacks,omitempty"`
+	VisionFallbacks    []ai.EndpointConfig `json:"visionFallbacks,omitempty"`
+	// Cache 控制截图与文本翻译结果的本地缓存
+	Cache CacheSettings `json:"cache"`
+	// Sampling 为提取、翻译与视觉直出三个阶段分别指定采样参数
+	Sampling SamplingSettings `json:"sampling"`
+	// Timeouts 为连接、首字、流式间隔与各阶段总时长的时限
+	Timeouts TimeoutSettings `json:"timeouts"`
+	// Image 为截图上传到视觉模型前的预处理设置
+	Image ImageSettings `json:"image"`
+}
+
+// ImageSettings 控制截图上传前的缩放、编码格式、灰度与大小上限，未填写的数值使用默认值
+type ImageSettings struct {
+	MaxLongEdge int    `json:"maxLongEdge"`
+	Format      string `json:"format"`
+	JPEGQuality int    `json:"jpegQuality"`
+	Grayscale   bool   `json:"grayscale"`
+	MaxKB       int    `json:"maxKb"`
+	// Enhance 为提升小字号、低对比度文字识别率的增强步骤，默认全部关闭
+	Enhance imageproc.Enhancement `json:"enhance"`
+}
+
+// Options 转换为 imageproc.Options
+func (i ImageSettings) Options() imageproc.Options {
+	return imageproc.Options{
+		MaxLongEdge: i.MaxLongEdge,
+		Format:      i.Format,
+		JPEGQuality: i.JPEGQuality,
+		Grayscale:   i.Grayscale,
+		MaxBytes:    i.MaxKB << 10,
+		Enhance:     i.Enhance,
+	}
+}
+
+// TimeoutSettings 以秒为单位保存各项时限，未填写时使用默认值
+type TimeoutSettings struct {
+	ConnectSeconds    int `json:"connectSeconds"`
+	FirstTokenSeconds int `json:"firstTokenSeconds"`
+	IdleSeconds       int `json:"idleSeconds"`
+	CaptureSeconds    int `json:"captureSeconds"`
+	OCRSeconds        int `json:"ocrSeconds"`
+	TranslateSeconds  int `json:"translateSeconds"`
+}
+
+// Client 转换为客户端的请求时限
+func (t TimeoutSettings) Client() ai.Timeouts {
+	return ai.Timeouts{
+		Connect:    seconds(t.ConnectSeconds),
+		FirstToken: seconds(t.FirstTokenSeconds),
+		Idle:       seconds(t.IdleSeconds),
+	}
+}
+
+func seconds(value int) time.Duration {
+	return time.Duration(value) * time.Second
+}
+
+// SamplingSettings 保存各阶段的采样参数，未填写的字段沿用客户端默认值
+type SamplingSettings struct {
+	Extract   ai.Sampling `json:"extract"`
+	Translate ai.Sampling `json:"translate"`
+	Direct    ai.Sampling `json:"direct"`
+}
+
+// CaptureRegion 为一个命名的固定截图区域，坐标为虚拟桌面中的屏幕坐标
+type CaptureRegion struct {
+	Name   string `json:"name"`
+	Left   int    `json:"left"`
+	Top    int    `json:"top"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	// Hotkey 为截取该区域的热键，留空表示仅能从界面触发
+	Hotkey string `json:"hotkey,omitempty"`
+}
+
+// WatchSettings 控制区域监视模式：轮询间隔、感知哈希变化阈值（0-64 位）与变化后的稳定时长
+type WatchSettings struct {
+	IntervalMs int `json:"intervalMs"`
+	Threshold  int `json:"threshold"`
+	DebounceMs int `json:"debounceMs"`
+}
+
+// CacheSettings 控制翻译结果缓存的开关、有效期与磁盘容量
+type CacheSettings struct {
+	Disabled  bool `json:"disabled"`
+	TTLHours  int  `json:"ttlHours"`
+	MaxSizeMB int  `json:"maxSizeMb"`
+}
+
+// Options 转换为 cache.Options
+func (c CacheSettings) Options() cache.Options {
+	return cache.Options{
+		TTL:          time.Duration(c.TTLHours) * time.Hour,
+		MaxDiskBytes: int64(c.MaxSizeMB) << 20,
+	}
 }
 
 // DefaultSettings 返回默认配置
@@ -41,6 +163,11 @@ func DefaultSettings() Settings {
 		Theme:                   "system",
 		ShowToastOnComplete:     true,
 		EnableStreamOutput:      true,
+		AutoContinue:            true,
+		MaxContinuations:        2,
+		DetectRefusals:          false,
+		PipelineTranslation:     true,
+		FallbackOnBlocked:       true,
 		HotkeyCombination:       "Alt+T",
 		ExtractPrompt:           prompts.DefaultExtractPrompt,
 		TranslatePrompt:         prompts.DefaultTranslatePrompt,
@@ -48,9 +175,35 @@ func DefaultSettings() Settings {
 		TranslateModel:          ai.DefaultTranslateModel,
 		VisionModel:             ai.DefaultVisionModel,
 		VisionAPIBaseURL:        ai.DefaultBaseURL,
+		TranslateProvider:       ai.ProviderOpenAI,
+		VisionProvider:          ai.ProviderOpenAI,
 		UseVisionForTranslation: true,
 		SourceLanguage:          "auto",
 		TargetLanguage:          "zh-CN",
+		CaptureBackend:          "auto",
+		Watch: WatchSettings{
+			IntervalMs: 500,
+			Threshold:  4,
+			DebounceMs: 400,
+		},
+		Cache: CacheSettings{
+			TTLHours:  int(cache.DefaultTTL / time.Hour),
+			MaxSizeMB: cache.DefaultMaxDiskBytes >> 20,
+		},
+		Timeouts: TimeoutSettings{
+			ConnectSeconds:    10,
+			FirstTokenSeconds: 60,
+			IdleSeconds:       30,
+			CaptureSeconds:    10,
+			OCRSeconds:        90,
+			TranslateSeconds:  180,
+		},
+		Image: ImageSettings{
+			MaxLongEdge: 2048,
+			Format:      imageproc.FormatPNG,
+			JPEGQuality: imageproc.DefaultJPEGQuality,
+			MaxKB:       1024,
+		},
 	}
 }
 
@@ -74,6 +227,11 @@ func (m *SettingsManager) Path() string {
 	return m.path
 }
 
+// Dir 返回配置所在目录，用量账本等数据文件与配置放在一起
+func (m *SettingsManager) Dir() string {
+	return filepath.Dir(m.path)
+}
+
 // Load 读取配置，不存在时返回默认配置
 func (m *SettingsManager) Load() (Settings, error) {
 	m.mu.RLock()
@@ -134,6 +292,13 @@ func applySettingsDefaults(settings *Settings) {
 	if strings.TrimSpace(settings.HotkeyCombination) == "" {
 		settings.HotkeyCombination = defaults.HotkeyCombination
 	}
+	settings.RepeatHotkeyCombination = strings.TrimSpace(settings.RepeatHotkeyCombination)
+	settings.CaptureRegions = normalizeRegions(settings.CaptureRegions)
+	settings.Watch = normalizeWatch(settings.Watch, defaults.Watch)
+	settings.CaptureBackend = strings.ToLower(strings.TrimSpace(settings.CaptureBackend))
+	if settings.CaptureBackend == "" {
+		settings.CaptureBackend = defaults.CaptureBackend
+	}
 	if strings.TrimSpace(settings.ExtractPrompt) == "" {
 		settings.ExtractPrompt = defaults.ExtractPrompt
 	}
@@ -156,10 +321,163 @@ func applySettingsDefaults(settings *Settings) {
 		settings.VisionAPIBaseURL = ai.NormalizeBaseURL(settings.VisionAPIBaseURL)
 	}
 	settings.VisionAPIKeyOverride = strings.TrimSpace(settings.VisionAPIKeyOverride)
+	settings.TranslateProvider = ai.NormalizeProvider(settings.TranslateProvider)
+	if strings.TrimSpace(settings.VisionProvider) == "" {
+		settings.VisionProvider = settings.TranslateProvider
+	} else {
+		settings.VisionProvider = ai.NormalizeProvider(settings.VisionProvider)
+	}
+	if settings.MaxContinuations <= 0 {
+		settings.MaxContinuations = defaults.MaxContinuations
+	}
 	if strings.TrimSpace(settings.SourceLanguage) == "" {
 		settings.SourceLanguage = defaults.SourceLanguage
 	}
 	if strings.TrimSpace(settings.TargetLanguage) == "" {
 		settings.TargetLanguage = defaults.TargetLanguage
 	}
+	settings.TranslateFallbacks = normalizeFallbacks(settings.TranslateFallbacks)
+	settings.VisionFallbacks = normalizeFallbacks(settings.VisionFallbacks)
+	settings.Budget.Soft = normalizeLimits(settings.Budget.Soft)
+	settings.Budget.Hard = normalizeLimits(settings.Budget.Hard)
+	if settings.Cache.TTLHours <= 0 {
+		settings.Cache.TTLHours = defaults.Cache.TTLHours
+	}
+	if settings.Cache.MaxSizeMB <= 0 {
+		settings.Cache.MaxSizeMB = defaults.Cache.MaxSizeMB
+	}
+	settings.Sampling.Extract = normalizeSampling(settings.Sampling.Extract)
+	settings.Sampling.Translate = normalizeSampling(settings.Sampling.Translate)
+	settings.Sampling.Direct = normalizeSampling(settings.Sampling.Direct)
+	settings.Timeouts = normalizeTimeouts(settings.Timeouts, defaults.Timeouts)
+	settings.Image = normalizeImage(settings.Image, defaults.Image)
+}
+
+// normalizeImage 将未填写或非法的预处理参数替换为默认值
+func normalizeImage(image, defaults ImageSettings) ImageSettings {
+	image.Format = imageproc.NormalizeFormat(image.Format)
+	if image.MaxLongEdge <= 0 {
+		image.MaxLongEdge = defaults.MaxLongEdge
+	}
+	if image.JPEGQuality <= 0 || image.JPEGQuality > 100 {
+		image.JPEGQuality = defaults.JPEGQuality
+	}
+	if image.MaxKB <= 0 {
+		image.MaxKB = defaults.MaxKB
+	}
+	if image.Enhance.UpscaleBelow < 0 {
+		image.Enhance.UpscaleBelow = 0
+	}
+	if image.Enhance.MaxUpscale <= 1 {
+		image.Enhance.MaxUpscale = imageproc.DefaultMaxUpscale
+	}
+	return image
+}
+
+// normalizeTimeouts 将未填写或非法的时限替换为默认值
+func normalizeTimeouts(timeouts, defaults TimeoutSettings) TimeoutSettings {
+	if timeouts.ConnectSeconds <= 0 {
+		timeouts.ConnectSeconds = defaults.ConnectSeconds
+	}
+	if timeouts.FirstTokenSeconds <= 0 {
+		timeouts.FirstTokenSeconds = defaults.FirstTokenSeconds
+	}
+	if timeouts.IdleSeconds <= 0 {
+		timeouts.IdleSeconds = defaults.IdleSeconds
+	}
+	if timeouts.CaptureSeconds <= 0 {
+		timeouts.CaptureSeconds = defaults.CaptureSeconds
+	}
+	if timeouts.OCRSeconds <= 0 {
+		timeouts.OCRSeconds = defaults.OCRSeconds
+	}
+	if timeouts.TranslateSeconds <= 0 {
+		timeouts.TranslateSeconds = defaults.TranslateSeconds
+	}
+	return timeouts
+}
+
+// normalizeSampling 丢弃非法的 max_tokens 与空白停止词
+func normalizeSampling(sampling ai.Sampling) ai.Sampling {
+	if sampling.MaxTokens < 0 {
+		sampling.MaxTokens = 0
+	}
+	var stop []string
+	for _, value := range sampling.Stop {
+		if strings.TrimSpace(value) != "" {
+			stop = append(stop, value)
+		}
+	}
+	sampling.Stop = stop
+	if len(sampling.ExtraBody) == 0 {
+		sampling.ExtraBody = nil
+	}
+	return sampling
+}
+
+// normalizeFallbacks 去除空白并丢弃未填写任何字段的备用接口
+func normalizeFallbacks(fallbacks []ai.EndpointConfig) []ai.EndpointConfig {
+	var result []ai.EndpointConfig
+	for _, fallback := range fallbacks {
+		fallback.BaseURL = strings.TrimSpace(fallback.BaseURL)
+		fallback.APIKey = strings.TrimSpace(fallback.APIKey)
+		fallback.Model = strings.TrimSpace(fallback.Model)
+		fallback.Provider = strings.TrimSpace(fallback.Provider)
+		if fallback == (ai.EndpointConfig{}) {
+			continue
+		}
+		if fallback.BaseURL != "" {
+			fallback.BaseURL = ai.NormalizeBaseURL(fallback.BaseURL)
+		}
+		if fallback.Provider != "" {
+			fallback.Provider = ai.NormalizeProvider(fallback.Provider)
+		}
+		result = append(result, fallback)
+	}
+	return result
+}
+
+// normalizeWatch 将非法的监视参数替换为默认值，轮询间隔不低于 100 毫秒
+func normalizeWatch(watch, defaults WatchSettings) WatchSettings {
+	if watch.IntervalMs <= 0 {
+		watch.IntervalMs = defaults.IntervalMs
+	}
+	watch.IntervalMs = max(watch.IntervalMs, 100)
+	if watch.Threshold < 0 || watch.Threshold > 64 {
+		watch.Threshold = defaults.Threshold
+	}
+	if watch.DebounceMs < 0 {
+		watch.DebounceMs = defaults.DebounceMs
+	}
+	return watch
+}
+
+// normalizeRegions 去除空白，丢弃未命名、重名或尺寸非法的截图区域
+func normalizeRegions(regions []CaptureRegion) []CaptureRegion {
+	var result []CaptureRegion
+	seen := make(map[string]bool, len(regions))
+	for _, region := range regions {
+		region.Name = strings.TrimSpace(region.Name)
+		region.Hotkey = strings.TrimSpace(region.Hotkey)
+		key := strings.ToLower(region.Name)
+		if region.Name == "" || seen[key] || region.Width <= 0 || region.Height <= 0 {
+			continue
+		}
+		seen[key] = true
+		result = append(result, region)
+	}
+	return result
+}
+
+func normalizeLimits(limits usage.Limits) usage.Limits {
+	if limits.RequestsPerDay < 0 {
+		limits.RequestsPerDay = 0
+	}
+	if limits.TokensPerDay < 0 {
+		limits.TokensPerDay = 0
+	}
+	if limits.CostPerMonth < 0 {
+		limits.CostPerMonth = 0
+	}
+	return limits
 }
@@ -0,0 +1,202 @@
+package display
+
+import (
+	"image"
+	"math"
+)
+
+// Monitor 描述一个显示器
+type Monitor struct {
+	// Index 为显示器编号（从 0 开始），与截图模块的显示器编号一致
+	Index int
+	// Bounds 为显示器在虚拟桌面中的物理像素范围，鼠标钩子、截图与浮窗均使用该坐标系
+	Bounds image.Rectangle
+	// Scale 为显示器的缩放比例，例如 150% 缩放为 1.5
+	Scale   float64
+	Primary bool
+}
+
+// Logical 返回显示器的逻辑坐标范围：左上角与物理坐标相同，宽高按缩放比例缩小，
+// 与 Windows 对每个显示器做 DPI 虚拟化时的规则一致
+func (m Monitor) Logical() image.Rectangle {
+	scale := m.scale()
+	return image.Rectangle{
+		Min: m.Bounds.Min,
+		Max: image.Pt(
+			m.Bounds.Min.X+int(math.Round(float64(m.Bounds.Dx())/scale)),
+			m.Bounds.Min.Y+int(math.Round(float64(m.Bounds.Dy())/scale)),
+		),
+	}
+}
+
+func (m Monitor) scale() float64 {
+	if m.Scale <= 0 {
+		return 1
+	}
+	return m.Scale
+}
+
+// ToLogical 将物理坐标转换为该显示器的逻辑坐标，点可以位于显示器之外
+func (m Monitor) ToLogical(p image.Point) image.Point {
+	scale := m.scale()
+	return image.Pt(
+		m.Bounds.Min.X+int(math.Round(float64(p.X-m.Bounds.Min.X)/scale)),
+		m.Bounds.Min.Y+int(math.Round(float64(p.Y-m.Bounds.Min.Y)/scale)),
+	)
+}
+
+// ToPhysical 将该显示器的逻辑坐标转换为物理坐标，是 ToLogical 的逆运算
+func (m Monitor) ToPhysical(p image.Point) image.Point {
+	scale := m.scale()
+	return image.Pt(
+		m.Bounds.Min.X+int(math.Round(float64(p.X-m.Bounds.Min.X)*scale)),
+		m.Bounds.Min.Y+int(math.Round(float64(p.Y-m.Bounds.Min.Y)*scale)),
+	)
+}
+
+// Topology 为当前的显示器布局
+type Topology struct {
+	Monitors []Monitor
+}
+
+// VirtualBounds 返回所有显示器物理范围的并集，副屏位于主屏左侧或上方时左上角为负值
+func (t Topology) VirtualBounds() image.Rectangle {
+	var bounds image.Rectangle
+	for _, monitor := range t.Monitors {
+		bounds = bounds.Union(monitor.Bounds)
+	}
+	return bounds
+}
+
+// Primary 返回主显示器，未标记主显示器时返回第一个
+func (t Topology) Primary() (Monitor, bool) {
+	for _, monitor := range t.Monitors {
+		if monitor.Primary {
+			return monitor, true
+		}
+	}
+	if len(t.Monitors) == 0 {
+		return Monitor{}, false
+	}
+	return t.Monitors[0], true
+}
+
+// MonitorAt 返回包含物理坐标 p 的显示器，p 不在任何显示器内时返回距离最近的显示器
+func (t Topology) MonitorAt(p image.Point) (Monitor, bool) {
+	return t.nearest(p, func(m Monitor) image.Rectangle { return m.Bounds })
+}
+
+// MonitorFor 返回与物理范围 r 重叠面积最大的显示器，没有重叠时按 r 的中心点取最近的显示器
+func (t Topology) MonitorFor(r image.Rectangle) (Monitor, bool) {
+	return t.overlapping(r, func(m Monitor) image.Rectangle { return m.Bounds })
+}
+
+// ToLogical 按 p 所在的显示器将物理坐标转换为逻辑坐标
+func (t Topology) ToLogical(p image.Point) image.Point {
+	monitor, ok := t.MonitorAt(p)
+	if !ok {
+		return p
+	}
+	return monitor.ToLogical(p)
+}
+
+// ToPhysical 按 p 所在显示器的逻辑范围将逻辑坐标转换为物理坐标
+func (t Topology) ToPhysical(p image.Point) image.Point {
+	monitor, ok := t.nearest(p, Monitor.Logical)
+	if !ok {
+		return p
+	}
+	return monitor.ToPhysical(p)
+}
+
+// RectToLogical 将物理范围转换为逻辑范围。跨显示器的范围统一按重叠最多的显示器换算，
+// 以免两个角落按不同比例缩放后变形
+func (t Topology) RectToLogical(r image.Rectangle) image.Rectangle {
+	monitor, ok := t.MonitorFor(r)
+	if !ok {
+		return r.Canon()
+	}
+	r = r.Canon()
+	return image.Rectangle{Min: monitor.ToLogical(r.Min), Max: monitor.ToLogical(r.Max)}
+}
+
+// RectToPhysical 将逻辑范围转换为物理范围，是 RectToLogical 的逆运算
+func (t Topology) RectToPhysical(r image.Rectangle) image.Rectangle {
+	monitor, ok := t.overlapping(r, Monitor.Logical)
+	if !ok {
+		return r.Canon()
+	}
+	r = r.Canon()
+	return image.Rectangle{Min: monitor.ToPhysical(r.Min), Max: monitor.ToPhysical(r.Max)}
+}
+
+// ScaleFor 返回物理范围 r 所在显示器的缩放比例，没有显示器信息时返回 1
+func (t Topology) ScaleFor(r image.Rectangle) float64 {
+	monitor, ok := t.MonitorFor(r)
+	if !ok {
+		return 1
+	}
+	return monitor.scale()
+}
+
+// overlapping 返回 bounds 与 r 重叠面积最大的显示器，都不重叠时返回距离 r 中心点最近的显示器
+func (t Topology) overlapping(r image.Rectangle, bounds func(Monitor) image.Rectangle) (Monitor, bool) {
+	r = r.Canon()
+	best, bestArea := -1, 0
+	for i, monitor := range t.Monitors {
+		overlap := r.Intersect(bounds(monitor))
+		if area := overlap.Dx() * overlap.Dy(); area > bestArea {
+			best, bestArea = i, area
+		}
+	}
+	if best >= 0 {
+		return t.Monitors[best], true
+	}
+	return t.nearest(image.Pt(r.Min.X+r.Dx()/2, r.Min.Y+r.Dy()/2), bounds)
+}
+
+// nearest 返回 bounds 包含 p 的显示器，都不包含时返回距离 p 最近的显示器
+func (t Topology) nearest(p image.Point, bounds func(Monitor) image.Rectangle) (Monitor, bool) {
+	best, bestDistance := -1, 0
+	for i, monitor := range t.Monitors {
+		rect := bounds(monitor)
+		if p.In(rect) {
+			return monitor, true
+		}
+		if distance := distanceSquared(p, rect); best < 0 || distance < bestDistance {
+			best, bestDistance = i, distance
+		}
+	}
+	if best < 0 {
+		return Monitor{}, false
+	}
+	return t.Monitors[best], true
+}
+
+// distanceSquared 返回点 p 到范围 r 的距离平方，r 的右下边界不属于范围内
+func distanceSquared(p image.Point, r image.Rectangle) int {
+	dx := 0
+	switch {
+	case p.X < r.Min.X:
+		dx = r.Min.X - p.X
+	case p.X >= r.Max.X:
+		dx = p.X - r.Max.X + 1
+	}
+	dy := 0
+	switch {
+	case p.Y < r.Min.Y:
+		dy = r.Min.Y - p.Y
+	case p.Y >= r.Max.Y:
+		dy = p.Y - r.Max.Y + 1
+	}
+	return dx*dx + dy*dy
+}
+
+// ScaleFor 返回物理范围 r 所在显示器的缩放比例，无法获取显示器布局时返回 1
+func ScaleFor(r image.Rectangle) float64 {
+	topology, err := Current()
+	if err != nil {
+		return 1
+	}
+	return topology.ScaleFor(r)
+}
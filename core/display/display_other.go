@@ -0,0 +1,30 @@
+//go:build !windows
+
+package display
+
+import (
+	"fmt"
+
+	"github.com/kbinani/screenshot"
+)
+
+// EnableDPIAwareness 仅在 Windows 上需要，其他平台为空操作
+func EnableDPIAwareness() {}
+
+// Current 枚举当前的显示器布局。X11 下坐标本身即为物理像素且没有按显示器的缩放，比例统一为 1
+func Current() (Topology, error) {
+	count := screenshot.NumActiveDisplays()
+	if count == 0 {
+		return Topology{}, fmt.Errorf("未检测到显示器")
+	}
+	monitors := make([]Monitor, 0, count)
+	for index := 0; index < count; index++ {
+		monitors = append(monitors, Monitor{
+			Index:   index,
+			Bounds:  screenshot.GetDisplayBounds(index),
+			Scale:   1,
+			Primary: index == 0,
+		})
+	}
+	return Topology{Monitors: monitors}, nil
+}
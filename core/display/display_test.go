@@ -0,0 +1,208 @@
+package display
+
+import (
+	"image"
+	"testing"
+)
+
+// mixedTopology 为 150% 的主屏，左侧有一块 100% 的副屏（上边缘高于主屏），上方有一块 125% 的副屏
+func mixedTopology() Topology {
+	return Topology{Monitors: []Monitor{
+		{Index: 0, Bounds: image.Rect(0, 0, 2880, 1800), Scale: 1.5, Primary: true},
+		{Index: 1, Bounds: image.Rect(-1920, -300, 0, 780), Scale: 1},
+		{Index: 2, Bounds: image.Rect(0, -1440, 2560, 0), Scale: 1.25},
+	}}
+}
+
+func TestMonitorConversion(t *testing.T) {
+	topology := mixedTopology()
+	cases := []struct {
+		name     string
+		monitor  int
+		physical image.Point
+		logical  image.Point
+	}{
+		{"150% origin", 0, image.Pt(0, 0), image.Pt(0, 0)},
+		{"150% middle", 0, image.Pt(1500, 900), image.Pt(1000, 600)},
+		{"150% bottom right", 0, image.Pt(2880, 1800), image.Pt(1920, 1200)},
+		{"100% left of primary", 1, image.Pt(-100, -200), image.Pt(-100, -200)},
+		{"100% top left corner", 1, image.Pt(-1920, -300), image.Pt(-1920, -300)},
+		{"125% above primary", 2, image.Pt(1000, -1000), image.Pt(800, -1088)},
+		{"125% origin", 2, image.Pt(0, -1440), image.Pt(0, -1440)},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			monitor := topology.Monitors[tc.monitor]
+			if got := monitor.ToLogical(tc.physical); got != tc.logical {
+				t.Errorf("ToLogical(%v) = %v, want %v", tc.physical, got, tc.logical)
+			}
+			if got := monitor.ToPhysical(tc.logical); got != tc.physical {
+				t.Errorf("ToPhysical(%v) = %v, want %v", tc.logical, got, tc.physical)
+			}
+		})
+	}
+}
+
+func TestMonitorLogicalBounds(t *testing.T) {
+	topology := mixedTopology()
+	want := []image.Rectangle{
+		image.Rect(0, 0, 1920, 1200),
+		image.Rect(-1920, -300, 0, 780),
+		image.Rect(0, -1440, 2048, -288),
+	}
+	for i, monitor := range topology.Monitors {
+		if got := monitor.Logical(); got != want[i] {
+			t.Errorf("monitor %d Logical() = %v, want %v", i, got, want[i])
+		}
+	}
+}
+
+func TestTopologyRoundTrip(t *testing.T) {
+	topology := mixedTopology()
+	for _, monitor := range topology.Monitors {
+		logical := monitor.Logical()
+		for y := logical.Min.Y; y < logical.Max.Y; y += 37 {
+			for x := logical.Min.X; x < logical.Max.X; x += 41 {
+				p := image.Pt(x, y)
+				physical := topology.ToPhysical(p)
+				if back := topology.ToLogical(physical); back != p {
+					t.Fatalf("monitor %d: logical %v -> physical %v -> logical %v", monitor.Index, p, physical, back)
+				}
+			}
+		}
+		// 物理坐标往返时误差不超过缩放带来的 1 像素
+		bounds := monitor.Bounds
+		for y := bounds.Min.Y; y < bounds.Max.Y; y += 37 {
+			for x := bounds.Min.X; x < bounds.Max.X; x += 41 {
+				p := image.Pt(x, y)
+				back := topology.ToPhysical(topology.ToLogical(p))
+				if abs(back.X-p.X) > 1 || abs(back.Y-p.Y) > 1 {
+					t.Fatalf("monitor %d: physical %v -> %v", monitor.Index, p, back)
+				}
+			}
+		}
+	}
+}
+
+func TestMonitorAt(t *testing.T) {
+	topology := mixedTopology()
+	cases := []struct {
+		name  string
+		point image.Point
+		want  int
+	}{
+		{"inside primary", image.Pt(10, 10), 0},
+		{"negative x on left monitor", image.Pt(-1, 0), 1},
+		{"negative y on upper monitor", image.Pt(10, -1), 2},
+		{"far left falls back to nearest", image.Pt(-5000, 0), 1},
+		{"gap beside upper monitor goes to nearest", image.Pt(2700, -100), 0},
+		{"right edge is exclusive", image.Pt(2880, 10), 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			monitor, ok := topology.MonitorAt(tc.point)
+			if !ok || monitor.Index != tc.want {
+				t.Fatalf("MonitorAt(%v) = %d, %v, want %d", tc.point, monitor.Index, ok, tc.want)
+			}
+		})
+	}
+}
+
+func TestMonitorFor(t *testing.T) {
+	topology := mixedTopology()
+	cases := []struct {
+		name string
+		rect image.Rectangle
+		want int
+	}{
+		{"mostly on primary", image.Rect(-90, 90, 300, 210), 0},
+		{"mostly on left monitor", image.Rect(-300, 0, 30, 30), 1},
+		{"spanning primary and upper monitor", image.Rect(100, -50, 200, 10), 2},
+		{"reversed corners", image.Rect(300, 210, -90, 90), 0},
+		{"no overlap uses nearest to center", image.Rect(-3000, 0, -2900, 10), 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			monitor, ok := topology.MonitorFor(tc.rect)
+			if !ok || monitor.Index != tc.want {
+				t.Fatalf("MonitorFor(%v) = %d, %v, want %d", tc.rect, monitor.Index, ok, tc.want)
+			}
+		})
+	}
+}
+
+func TestRectConversion(t *testing.T) {
+	topology := mixedTopology()
+	cases := []struct {
+		name     string
+		physical image.Rectangle
+		logical  image.Rectangle
+	}{
+		{"inside 150% primary", image.Rect(300, 150, 900, 450), image.Rect(200, 100, 600, 300)},
+		{"inside 100% left monitor", image.Rect(-1000, -200, -500, 100), image.Rect(-1000, -200, -500, 100)},
+		{"inside 125% upper monitor", image.Rect(500, -1000, 1000, -500), image.Rect(400, -1088, 800, -688)},
+		{"spanning left and primary uses primary scale", image.Rect(-90, 90, 300, 210), image.Rect(-60, 60, 200, 140)},
+		{"spanning left and primary uses left scale", image.Rect(-300, 0, 30, 30), image.Rect(-300, 0, 30, 30)},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := topology.RectToLogical(tc.physical); got != tc.logical {
+				t.Errorf("RectToLogical(%v) = %v, want %v", tc.physical, got, tc.logical)
+			}
+			if got := topology.RectToPhysical(tc.logical); got != tc.physical {
+				t.Errorf("RectToPhysical(%v) = %v, want %v", tc.logical, got, tc.physical)
+			}
+		})
+	}
+}
+
+func TestScaleAndVirtualBounds(t *testing.T) {
+	topology := mixedTopology()
+	if got := topology.ScaleFor(image.Rect(-100, 0, 2000, 100)); got != 1.5 {
+		t.Errorf("ScaleFor spanning rect = %v, want 1.5", got)
+	}
+	if got := topology.ScaleFor(image.Rect(-500, -100, -400, 0)); got != 1 {
+		t.Errorf("ScaleFor left rect = %v, want 1", got)
+	}
+	if got, want := topology.VirtualBounds(), image.Rect(-1920, -1440, 2880, 1800); got != want {
+		t.Errorf("VirtualBounds() = %v, want %v", got, want)
+	}
+	if primary, ok := topology.Primary(); !ok || primary.Index != 0 {
+		t.Errorf("Primary() = %d, %v", primary.Index, ok)
+	}
+}
+
+func TestEmptyTopology(t *testing.T) {
+	var topology Topology
+	p := image.Pt(-10, 20)
+	if got := topology.ToLogical(p); got != p {
+		t.Errorf("ToLogical = %v, want %v", got, p)
+	}
+	if got := topology.ToPhysical(p); got != p {
+		t.Errorf("ToPhysical = %v, want %v", got, p)
+	}
+	if got := topology.ScaleFor(image.Rect(0, 0, 10, 10)); got != 1 {
+		t.Errorf("ScaleFor = %v, want 1", got)
+	}
+	if _, ok := topology.MonitorFor(image.Rect(0, 0, 10, 10)); ok {
+		t.Error("MonitorFor on empty topology should report false")
+	}
+}
+
+func TestZeroScaleTreatedAsOne(t *testing.T) {
+	monitor := Monitor{Bounds: image.Rect(-100, -100, 100, 100)}
+	p := image.Pt(50, -30)
+	if got := monitor.ToLogical(p); got != p {
+		t.Errorf("ToLogical = %v, want %v", got, p)
+	}
+	if got := monitor.Logical(); got != monitor.Bounds {
+		t.Errorf("Logical = %v, want %v", got, monitor.Bounds)
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,106 @@
+//go:build windows
+
+package display
+
+import (
+	"fmt"
+	"image"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"github.com/lxn/win"
+)
+
+const (
+	mdtEffectiveDpi = 0
+	// dpiAwarenessContextPerMonitorAwareV2 为 DPI_AWARENESS_CONTEXT_PER_MONITOR_AWARE_V2
+	dpiAwarenessContextPerMonitorAwareV2 = ^uintptr(3)
+	processPerMonitorDpiAware            = 2
+)
+
+var (
+	user32                            = syscall.NewLazyDLL("user32.dll")
+	shcore                            = syscall.NewLazyDLL("shcore.dll")
+	procEnumDisplayMonitors           = user32.NewProc("EnumDisplayMonitors")
+	procSetProcessDpiAwarenessContext = user32.NewProc("SetProcessDpiAwarenessContext")
+	procSetProcessDPIAware            = user32.NewProc("SetProcessDPIAware")
+	procGetDpiForMonitor              = shcore.NewProc("GetDpiForMonitor")
+	procSetProcessDpiAwareness        = shcore.NewProc("SetProcessDpiAwareness")
+	enumMonitorsCallback              = syscall.NewCallback(enumMonitorsProc)
+
+	// enumMonitors 收集 EnumDisplayMonitors 回调的结果，enumMutex 保证同一时间只有一次枚举
+	enumMutex    sync.Mutex
+	enumMonitors []Monitor
+)
+
+// EnableDPIAwareness 将进程声明为按显示器感知 DPI，使鼠标钩子、截图与浮窗窗口都使用物理像素。
+// 清单文件已声明时调用无副作用；未嵌入清单（如 wails dev）时避免系统对坐标做虚拟化。
+// 需要在创建任何窗口之前调用。
+func EnableDPIAwareness() {
+	if procSetProcessDpiAwarenessContext.Find() == nil {
+		if ret, _, _ := procSetProcessDpiAwarenessContext.Call(dpiAwarenessContextPerMonitorAwareV2); ret != 0 {
+			return
+		}
+	}
+	if procSetProcessDpiAwareness.Find() == nil {
+		// 已通过清单设置时返回 E_ACCESSDENIED，同样视为成功
+		procSetProcessDpiAwareness.Call(processPerMonitorDpiAware)
+		return
+	}
+	if procSetProcessDPIAware.Find() == nil {
+		procSetProcessDPIAware.Call()
+	}
+}
+
+// Current 枚举当前的显示器布局，顺序与截图模块的显示器编号一致
+func Current() (Topology, error) {
+	enumMutex.Lock()
+	enumMonitors = nil
+	ret, _, err := procEnumDisplayMonitors.Call(0, 0, enumMonitorsCallback, 0)
+	monitors := enumMonitors
+	enumMonitors = nil
+	enumMutex.Unlock()
+	if ret == 0 {
+		return Topology{}, fmt.Errorf("枚举显示器失败: %v", err)
+	}
+	if len(monitors) == 0 {
+		return Topology{}, fmt.Errorf("未检测到显示器")
+	}
+	return Topology{Monitors: monitors}, nil
+}
+
+func enumMonitorsProc(hMonitor win.HMONITOR, _ win.HDC, _ *win.RECT, _ uintptr) uintptr {
+	var info win.MONITORINFO
+	info.CbSize = uint32(unsafe.Sizeof(info))
+	if !win.GetMonitorInfo(hMonitor, &info) {
+		return 1
+	}
+	enumMonitors = append(enumMonitors, Monitor{
+		Index:   len(enumMonitors),
+		Bounds:  image.Rect(int(info.RcMonitor.Left), int(info.RcMonitor.Top), int(info.RcMonitor.Right), int(info.RcMonitor.Bottom)),
+		Scale:   monitorScale(hMonitor),
+		Primary: info.DwFlags&win.MONITORINFOF_PRIMARY != 0,
+	})
+	return 1
+}
+
+// monitorScale 返回显示器的有效缩放比例，Windows 8.1 之前的系统只有统一的系统 DPI
+func monitorScale(hMonitor win.HMONITOR) float64 {
+	if procGetDpiForMonitor.Find() == nil {
+		var dpiX, dpiY uint32
+		ret, _, _ := procGetDpiForMonitor.Call(uintptr(hMonitor), mdtEffectiveDpi, uintptr(unsafe.Pointer(&dpiX)), uintptr(unsafe.Pointer(&dpiY)))
+		if ret == 0 && dpiX > 0 {
+			return float64(dpiX) / 96
+		}
+	}
+	hdc := win.GetDC(0)
+	if hdc == 0 {
+		return 1
+	}
+	defer win.ReleaseDC(0, hdc)
+	if dpi := win.GetDeviceCaps(hdc, win.LOGPIXELSX); dpi > 0 {
+		return float64(dpi) / 96
+	}
+	return 1
+}
@@ -0,0 +1,72 @@
+package imageproc
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"strings"
+
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// MaxDecodeBytes 为可导入图片文件的最大字节数
+	MaxDecodeBytes = 32 << 20
+	// maxDecodePixels 为可导入图片的最大像素数，避免解码超大图片耗尽内存
+	maxDecodePixels = 8192 * 8192
+)
+
+// decodeMIMETypes 为支持导入的 MIME 类型
+var decodeMIMETypes = map[string]bool{
+	"image/png":      true,
+	"image/jpeg":     true,
+	"image/jpg":      true,
+	"image/pjpeg":    true,
+	"image/gif":      true,
+	"image/bmp":      true,
+	"image/x-bmp":    true,
+	"image/x-ms-bmp": true,
+	"image/webp":     true,
+}
+
+// Decode 解码导入的 PNG、JPEG、GIF、BMP 或 WebP 图片，GIF 动图只取第一帧。
+// mimeType 为空或为 application/octet-stream 时按内容识别格式；声明的类型与内容不符时以内容为准，
+// 声明了不支持的类型则直接报错。
+func Decode(data []byte, mimeType string) (image.Image, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("图片内容为空")
+	}
+	if len(data) > MaxDecodeBytes {
+		return nil, fmt.Errorf("图片过大（%d KB），上限为 %d KB", len(data)>>10, MaxDecodeBytes>>10)
+	}
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+	if index := strings.Index(mimeType, ";"); index >= 0 {
+		mimeType = strings.TrimSpace(mimeType[:index])
+	}
+	if mimeType != "" && mimeType != "application/octet-stream" {
+		if !decodeMIMETypes[mimeType] {
+			return nil, fmt.Errorf("不支持的图片格式 %s，仅支持 PNG、JPEG、GIF、BMP 与 WebP", mimeType)
+		}
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("无法识别图片格式: %w", err)
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, fmt.Errorf("图片尺寸无效: %dx%d", config.Width, config.Height)
+	}
+	if config.Width*config.Height > maxDecodePixels {
+		return nil, fmt.Errorf("图片尺寸过大: %dx%d", config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("解码 %s 图片失败: %w", format, err)
+	}
+	return img, nil
+}
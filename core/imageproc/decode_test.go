@@ -0,0 +1,90 @@
+package imageproc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/bmp"
+)
+
+// webpPixel 为 1x1 的无损 WebP 图片
+const webpPixel = "UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA=="
+
+func encodeFixture(t *testing.T, encode func(*bytes.Buffer, image.Image) error) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	img.SetRGBA(0, 0, color.RGBA{200, 0, 0, 255})
+	var buf bytes.Buffer
+	if err := encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecode(t *testing.T) {
+	pngData := encodeFixture(t, func(buf *bytes.Buffer, img image.Image) error { return png.Encode(buf, img) })
+	jpegData := encodeFixture(t, func(buf *bytes.Buffer, img image.Image) error { return jpeg.Encode(buf, img, nil) })
+	gifData := encodeFixture(t, func(buf *bytes.Buffer, img image.Image) error { return gif.Encode(buf, img, nil) })
+	bmpData := encodeFixture(t, func(buf *bytes.Buffer, img image.Image) error { return bmp.Encode(buf, img) })
+	webpData, err := base64.StdEncoding.DecodeString(webpPixel)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 仅改写 BMP 头中的宽高，DecodeConfig 读到的尺寸超过像素上限
+	hugeBMP := append([]byte(nil), bmpData...)
+	binary.LittleEndian.PutUint32(hugeBMP[18:22], 9000)
+	binary.LittleEndian.PutUint32(hugeBMP[22:26], 9000)
+
+	cases := []struct {
+		name     string
+		data     []byte
+		mimeType string
+		wantSize image.Point
+		wantErr  string
+	}{
+		{name: "png", data: pngData, mimeType: "image/png", wantSize: image.Pt(3, 2)},
+		{name: "jpeg", data: jpegData, mimeType: "image/jpeg", wantSize: image.Pt(3, 2)},
+		{name: "gif", data: gifData, mimeType: "image/gif", wantSize: image.Pt(3, 2)},
+		{name: "bmp", data: bmpData, mimeType: "image/bmp", wantSize: image.Pt(3, 2)},
+		{name: "webp", data: webpData, mimeType: "image/webp", wantSize: image.Pt(1, 1)},
+		{name: "sniffed without mime type", data: pngData, wantSize: image.Pt(3, 2)},
+		{name: "octet-stream is sniffed", data: bmpData, mimeType: "application/octet-stream", wantSize: image.Pt(3, 2)},
+		{name: "mime parameters ignored", data: pngData, mimeType: " Image/PNG; charset=binary", wantSize: image.Pt(3, 2)},
+		{name: "content wins over declared type", data: pngData, mimeType: "image/jpeg", wantSize: image.Pt(3, 2)},
+		{name: "unsupported mime type", data: pngData, mimeType: "image/tiff", wantErr: "不支持的图片格式"},
+		{name: "empty", data: nil, mimeType: "image/png", wantErr: "为空"},
+		{name: "too many bytes", data: make([]byte, MaxDecodeBytes+1), mimeType: "image/png", wantErr: "图片过大"},
+		{name: "too many pixels", data: hugeBMP, mimeType: "image/bmp", wantErr: "尺寸过大"},
+		{name: "unknown format", data: []byte("not an image at all"), wantErr: "无法识别"},
+		{name: "truncated png", data: pngData[:len(pngData)/2+8], mimeType: "image/png", wantErr: "解码 png"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			img, err := Decode(tc.data, tc.mimeType)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("err = %v, want error containing %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if size := img.Bounds().Size(); size != tc.wantSize {
+				t.Fatalf("size = %v, want %v", size, tc.wantSize)
+			}
+		})
+	}
+}
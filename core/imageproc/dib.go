@@ -0,0 +1,73 @@
+package imageproc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	dibHeaderLen = 40
+	biRGB        = 0
+	biBitFields  = 3
+)
+
+// DIBToBMP 为剪贴板 CF_DIB 数据补上文件头。BITMAPV4/V5 头统一改写为 BITMAPINFOHEADER，
+// 标准 BGR 掩码的 BI_BITFIELDS 视为 BI_RGB，以便按普通 BMP 文件解码
+func DIBToBMP(dib []byte) ([]byte, error) {
+	if len(dib) < dibHeaderLen {
+		return nil, errors.New("剪贴板位图数据不完整")
+	}
+	headerLen := int(binary.LittleEndian.Uint32(dib[0:4]))
+	bitCount := binary.LittleEndian.Uint16(dib[14:16])
+	compression := binary.LittleEndian.Uint32(dib[16:20])
+	colorsUsed := int(binary.LittleEndian.Uint32(dib[32:36]))
+	if headerLen < dibHeaderLen || headerLen > len(dib) {
+		return nil, errors.New("剪贴板位图头无效")
+	}
+
+	pixels := headerLen
+	switch compression {
+	case biRGB:
+	case biBitFields:
+		masks := dib[dibHeaderLen:]
+		if headerLen == dibHeaderLen {
+			// BITMAPINFOHEADER 的颜色掩码紧跟在头之后
+			pixels += 12
+		}
+		if len(masks) < 12 || bitCount != 32 ||
+			binary.LittleEndian.Uint32(masks[0:4]) != 0xff0000 ||
+			binary.LittleEndian.Uint32(masks[4:8]) != 0xff00 ||
+			binary.LittleEndian.Uint32(masks[8:12]) != 0xff {
+			return nil, fmt.Errorf("不支持的剪贴板位图格式（%d 位，自定义颜色掩码）", bitCount)
+		}
+	default:
+		return nil, fmt.Errorf("不支持的剪贴板位图压缩方式 %d", compression)
+	}
+
+	paletteLen := 0
+	if bitCount <= 8 {
+		if colorsUsed == 0 {
+			colorsUsed = 1 << bitCount
+		}
+		paletteLen = colorsUsed * 4
+	}
+	if pixels+paletteLen > len(dib) {
+		return nil, errors.New("剪贴板位图数据不完整")
+	}
+	palette := dib[pixels : pixels+paletteLen]
+	pixelData := dib[pixels+paletteLen:]
+
+	offset := 14 + dibHeaderLen + paletteLen
+	out := make([]byte, 0, offset+len(pixelData))
+	out = append(out, 'B', 'M')
+	out = binary.LittleEndian.AppendUint32(out, uint32(offset+len(pixelData)))
+	out = binary.LittleEndian.AppendUint32(out, 0)
+	out = binary.LittleEndian.AppendUint32(out, uint32(offset))
+	header := append([]byte(nil), dib[:dibHeaderLen]...)
+	binary.LittleEndian.PutUint32(header[0:4], dibHeaderLen)
+	binary.LittleEndian.PutUint32(header[16:20], biRGB)
+	out = append(out, header...)
+	out = append(out, palette...)
+	return append(out, pixelData...), nil
+}
@@ -0,0 +1,141 @@
+package imageproc
+
+import (
+	"encoding/binary"
+	"image/color"
+	"testing"
+)
+
+// 2x2 测试图像：左上红、右上绿、左下蓝、右下白
+var dibColors = [2][2]color.NRGBA{
+	{{255, 0, 0, 255}, {0, 255, 0, 255}},
+	{{0, 0, 255, 255}, {255, 255, 255, 255}},
+}
+
+// dibHeader 构造 headerLen 字节的位图头，height 为负表示自上而下存储
+func dibHeader(headerLen int, height int32, bitCount uint16, compression uint32, colorsUsed uint32) []byte {
+	header := make([]byte, headerLen)
+	binary.LittleEndian.PutUint32(header[0:4], uint32(headerLen))
+	binary.LittleEndian.PutUint32(header[4:8], 2)
+	binary.LittleEndian.PutUint32(header[8:12], uint32(height))
+	binary.LittleEndian.PutUint16(header[12:14], 1)
+	binary.LittleEndian.PutUint16(header[14:16], bitCount)
+	binary.LittleEndian.PutUint32(header[16:20], compression)
+	binary.LittleEndian.PutUint32(header[32:36], colorsUsed)
+	return header
+}
+
+// bgrMasks 为 32 位 BGRX 的标准颜色掩码
+func bgrMasks() []byte {
+	masks := binary.LittleEndian.AppendUint32(nil, 0xff0000)
+	masks = binary.LittleEndian.AppendUint32(masks, 0xff00)
+	return binary.LittleEndian.AppendUint32(masks, 0xff)
+}
+
+// dibPixels 按 bitCount（24 或 32）编码 dibColors，topDown 为 false 时从最下一行开始，每行按 4 字节对齐
+func dibPixels(bitCount int, topDown bool) []byte {
+	var data []byte
+	for i := 0; i < 2; i++ {
+		y := 1 - i
+		if topDown {
+			y = i
+		}
+		var row []byte
+		for _, c := range dibColors[y] {
+			row = append(row, c.B, c.G, c.R)
+			if bitCount == 32 {
+				row = append(row, 0)
+			}
+		}
+		for len(row)%4 != 0 {
+			row = append(row, 0)
+		}
+		data = append(data, row...)
+	}
+	return data
+}
+
+func concatBytes(parts ...[]byte) []byte {
+	var out []byte
+	for _, part := range parts {
+		out = append(out, part...)
+	}
+	return out
+}
+
+func TestDIBToBMP(t *testing.T) {
+	v5 := dibHeader(124, 2, 32, biBitFields, 0)
+	copy(v5[dibHeaderLen:], bgrMasks())
+
+	// 8 位调色板图像：两种颜色，索引 0 为红、1 为蓝，每行 2 字节补齐到 4 字节
+	palette := []byte{0, 0, 255, 0, 255, 0, 0, 0}
+	indexed := []byte{1, 1, 0, 0, 0, 0, 0, 0}
+
+	cases := []struct {
+		name string
+		dib  []byte
+		want [2][2]color.NRGBA
+	}{
+		{"24-bit bottom-up", concatBytes(dibHeader(dibHeaderLen, 2, 24, biRGB, 0), dibPixels(24, false)), dibColors},
+		{"24-bit top-down", concatBytes(dibHeader(dibHeaderLen, -2, 24, biRGB, 0), dibPixels(24, true)), dibColors},
+		{"32-bit bitfields after info header", concatBytes(dibHeader(dibHeaderLen, 2, 32, biBitFields, 0), bgrMasks(), dibPixels(32, false)), dibColors},
+		{"32-bit bitfields in V5 header", concatBytes(v5, dibPixels(32, false)), dibColors},
+		{
+			"8-bit palette",
+			concatBytes(dibHeader(dibHeaderLen, 2, 8, biRGB, 2), palette, indexed),
+			[2][2]color.NRGBA{
+				{{255, 0, 0, 255}, {255, 0, 0, 255}},
+				{{0, 0, 255, 255}, {0, 0, 255, 255}},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := DIBToBMP(tc.dib)
+			if err != nil {
+				t.Fatal(err)
+			}
+			img, err := Decode(data, "image/bmp")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if size := img.Bounds().Size(); size.X != 2 || size.Y != 2 {
+				t.Fatalf("size = %v, want 2x2", size)
+			}
+			for y := 0; y < 2; y++ {
+				for x := 0; x < 2; x++ {
+					got := color.NRGBAModel.Convert(img.At(img.Bounds().Min.X+x, img.Bounds().Min.Y+y))
+					if got != tc.want[y][x] {
+						t.Fatalf("pixel (%d,%d) = %v, want %v", x, y, got, tc.want[y][x])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDIBToBMPErrors(t *testing.T) {
+	customMasks := binary.LittleEndian.AppendUint32(nil, 0xf800)
+	customMasks = binary.LittleEndian.AppendUint32(customMasks, 0x7e0)
+	customMasks = binary.LittleEndian.AppendUint32(customMasks, 0x1f)
+	oversized := dibHeader(dibHeaderLen, 2, 24, biRGB, 0)
+	binary.LittleEndian.PutUint32(oversized[0:4], 200)
+
+	cases := []struct {
+		name string
+		dib  []byte
+	}{
+		{"shorter than header", make([]byte, 20)},
+		{"header length beyond data", oversized},
+		{"custom bitfield masks", concatBytes(dibHeader(dibHeaderLen, 2, 16, biBitFields, 0), customMasks, make([]byte, 8))},
+		{"run-length encoding", concatBytes(dibHeader(dibHeaderLen, 2, 8, 1, 0), make([]byte, 1024))},
+		{"truncated palette", concatBytes(dibHeader(dibHeaderLen, 2, 8, biRGB, 0), make([]byte, 16))},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := DIBToBMP(tc.dib); err == nil {
+				t.Fatal("DIBToBMP succeeded, want error")
+			}
+		})
+	}
+}
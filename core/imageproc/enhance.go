@@ -0,0 +1,274 @@
+package imageproc
+
+import (
+	"image"
+)
+
+const (
+	// DefaultMaxUpscale 为未指定时的整数放大倍数上限
+	DefaultMaxUpscale = 3
+	// trimTolerance 为裁边时视为与边框同色的通道差值
+	trimTolerance = 12
+	// trimPadding 为裁边后保留的留白，避免文字紧贴图像边缘
+	trimPadding = 4
+	// darkThreshold 为平均亮度低于该值时视为深色界面
+	darkThreshold = 110
+	// contrastClip 为自动对比度时两端忽略的像素比例
+	contrastClip = 0.01
+)
+
+// Enhancement 为提升小字号、低对比度文字识别率的增强步骤，零值表示全部关闭。
+// 各步骤按裁边、反色、自动对比度、放大、锐化的顺序执行。
+type Enhancement struct {
+	// TrimBorders 裁掉四周与角落颜色一致的空白边框
+	TrimBorders bool `json:"trimBorders"`
+	// InvertDark 在平均亮度较低（深色主题）时反色为浅底深字
+	InvertDark bool `json:"invertDark"`
+	// AutoContrast 按亮度直方图拉伸色阶，使最暗与最亮处分别接近黑白
+	AutoContrast bool `json:"autoContrast"`
+	// Sharpen 使用拉普拉斯核锐化文字边缘
+	Sharpen bool `json:"sharpen"`
+	// UpscaleBelow 为长边小于该像素数时按整数倍放大，0 表示不放大
+	UpscaleBelow int `json:"upscaleBelow"`
+	// MaxUpscale 为放大倍数上限，<=1 时使用 DefaultMaxUpscale
+	MaxUpscale int `json:"maxUpscale"`
+}
+
+// Enabled 报告是否开启了任意增强步骤
+func (e Enhancement) Enabled() bool {
+	return e.TrimBorders || e.InvertDark || e.AutoContrast || e.Sharpen || e.UpscaleBelow > 0
+}
+
+// Enhance 按 e 依次执行增强步骤并返回新图像，不修改 img
+func Enhance(img image.Image, e Enhancement) image.Image {
+	enhanced, _ := enhance(img, e)
+	return enhanced
+}
+
+// enhance 执行增强步骤，并返回裁边后保留的区域（相对于 img 左上角），未裁边时为整张图
+func enhance(img image.Image, e Enhancement) (image.Image, image.Rectangle) {
+	crop := image.Rectangle{Max: img.Bounds().Size()}
+	if !e.Enabled() || img.Bounds().Empty() {
+		return img, crop
+	}
+	rgba := copyRGBA(img)
+	if e.TrimBorders {
+		rgba, crop = trimBorders(rgba)
+	}
+	if e.InvertDark && meanLuminance(rgba) < darkThreshold {
+		invert(rgba)
+	}
+	if e.AutoContrast {
+		autoContrast(rgba)
+	}
+	if e.UpscaleBelow > 0 {
+		rgba = upscale(rgba, e.UpscaleBelow, e.MaxUpscale)
+	}
+	if e.Sharpen {
+		rgba = sharpen(rgba)
+	}
+	return rgba, crop
+}
+
+// copyRGBA 复制为从原点开始的 *image.RGBA，之后的步骤可以原地修改像素
+func copyRGBA(img image.Image) *image.RGBA {
+	src := toRGBA(img)
+	if src != img {
+		return src
+	}
+	dst := image.NewRGBA(src.Rect)
+	copy(dst.Pix, src.Pix)
+	return dst
+}
+
+// luminance 按 BT.601 系数计算亮度
+func luminance(r, g, b uint8) int {
+	return (299*int(r) + 587*int(g) + 114*int(b)) / 1000
+}
+
+func meanLuminance(img *image.RGBA) int {
+	bounds := img.Rect
+	pixels := bounds.Dx() * bounds.Dy()
+	if pixels == 0 {
+		return 0
+	}
+	var total int
+	for y := 0; y < bounds.Dy(); y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+bounds.Dx()*4]
+		for i := 0; i < len(row); i += 4 {
+			total += luminance(row[i], row[i+1], row[i+2])
+		}
+	}
+	return total / pixels
+}
+
+// trimBorders 以左上角像素为边框色，裁掉与之相近的整行整列，并保留少量留白，同时返回保留的区域
+func trimBorders(img *image.RGBA) (*image.RGBA, image.Rectangle) {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	border := img.Pix[0:4]
+	similar := func(x, y int) bool {
+		offset := y*img.Stride + x*4
+		for c := 0; c < 3; c++ {
+			diff := int(img.Pix[offset+c]) - int(border[c])
+			if diff > trimTolerance || diff < -trimTolerance {
+				return false
+			}
+		}
+		return true
+	}
+	uniformRow := func(y int) bool {
+		for x := 0; x < width; x++ {
+			if !similar(x, y) {
+				return false
+			}
+		}
+		return true
+	}
+	uniformColumn := func(x, top, bottom int) bool {
+		for y := top; y < bottom; y++ {
+			if !similar(x, y) {
+				return false
+			}
+		}
+		return true
+	}
+
+	top, bottom := 0, height
+	for top < bottom && uniformRow(top) {
+		top++
+	}
+	if top == bottom {
+		// 整张图都是边框色，没有可裁的内容
+		return img, img.Rect
+	}
+	for bottom > top && uniformRow(bottom-1) {
+		bottom--
+	}
+	left, right := 0, width
+	for left < right && uniformColumn(left, top, bottom) {
+		left++
+	}
+	for right > left && uniformColumn(right-1, top, bottom) {
+		right--
+	}
+
+	crop := image.Rect(
+		max(left-trimPadding, 0),
+		max(top-trimPadding, 0),
+		min(right+trimPadding, width),
+		min(bottom+trimPadding, height),
+	)
+	if crop == img.Rect {
+		return img, crop
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, crop.Dx(), crop.Dy()))
+	for y := 0; y < crop.Dy(); y++ {
+		start := (crop.Min.Y+y)*img.Stride + crop.Min.X*4
+		copy(dst.Pix[y*dst.Stride:], img.Pix[start:start+crop.Dx()*4])
+	}
+	return dst, crop
+}
+
+// invert 原地反转 RGB 通道，保留 alpha
+func invert(img *image.RGBA) {
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 255 - img.Pix[i]
+		img.Pix[i+1] = 255 - img.Pix[i+1]
+		img.Pix[i+2] = 255 - img.Pix[i+2]
+	}
+}
+
+// autoContrast 忽略两端 contrastClip 比例的像素后，将亮度范围线性拉伸到 0-255
+func autoContrast(img *image.RGBA) {
+	var histogram [256]int
+	for i := 0; i < len(img.Pix); i += 4 {
+		histogram[luminance(img.Pix[i], img.Pix[i+1], img.Pix[i+2])]++
+	}
+	pixels := len(img.Pix) / 4
+	clip := int(float64(pixels) * contrastClip)
+
+	low, count := 0, 0
+	for ; low < 255; low++ {
+		count += histogram[low]
+		if count > clip {
+			break
+		}
+	}
+	high := 255
+	for count = 0; high > 0; high-- {
+		count += histogram[high]
+		if count > clip {
+			break
+		}
+	}
+	if high <= low {
+		return
+	}
+
+	var levels [256]uint8
+	for value := range levels {
+		scaled := (value - low) * 255 / (high - low)
+		levels[value] = uint8(min(max(scaled, 0), 255))
+	}
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i] = levels[img.Pix[i]]
+		img.Pix[i+1] = levels[img.Pix[i+1]]
+		img.Pix[i+2] = levels[img.Pix[i+2]]
+	}
+}
+
+// upscale 在长边小于 below 时按最近邻整数倍放大，保持文字边缘锐利
+func upscale(img *image.RGBA, below, maxFactor int) *image.RGBA {
+	if maxFactor <= 1 {
+		maxFactor = DefaultMaxUpscale
+	}
+	edge := longEdge(img.Rect)
+	if edge == 0 || edge >= below {
+		return img
+	}
+	factor := min((below+edge-1)/edge, maxFactor)
+	if factor <= 1 {
+		return img
+	}
+
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width*factor, height*factor))
+	for y := 0; y < height; y++ {
+		src := img.Pix[y*img.Stride : y*img.Stride+width*4]
+		row := dst.Pix[y*factor*dst.Stride : y*factor*dst.Stride+width*factor*4]
+		for x := 0; x < width; x++ {
+			pixel := src[x*4 : x*4+4]
+			for k := 0; k < factor; k++ {
+				copy(row[(x*factor+k)*4:], pixel)
+			}
+		}
+		for k := 1; k < factor; k++ {
+			copy(dst.Pix[(y*factor+k)*dst.Stride:], row)
+		}
+	}
+	return dst
+}
+
+// sharpen 使用 3x3 拉普拉斯核（中心 5，上下左右 -1）锐化，边缘像素保持不变
+func sharpen(img *image.RGBA) *image.RGBA {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	dst := image.NewRGBA(img.Rect)
+	copy(dst.Pix, img.Pix)
+	if width < 3 || height < 3 {
+		return dst
+	}
+	stride := img.Stride
+	for y := 1; y < height-1; y++ {
+		for x := 1; x < width-1; x++ {
+			offset := y*stride + x*4
+			for c := 0; c < 3; c++ {
+				i := offset + c
+				value := 5*int(img.Pix[i]) -
+					int(img.Pix[i-4]) - int(img.Pix[i+4]) -
+					int(img.Pix[i-stride]) - int(img.Pix[i+stride])
+				dst.Pix[i] = uint8(min(max(value, 0), 255))
+			}
+		}
+	}
+	return dst
+}
@@ -0,0 +1,218 @@
+package imageproc
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// grayImage 按行构造灰度图，每个值同时作为 R、G、B，alpha 为 255
+func grayImage(rows [][]uint8) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, len(rows[0]), len(rows)))
+	for y, row := range rows {
+		for x, value := range row {
+			img.SetRGBA(x, y, color.RGBA{value, value, value, 255})
+		}
+	}
+	return img
+}
+
+// filled 返回 width x height、所有像素均为 value 的灰度行
+func filled(width, height int, value uint8) [][]uint8 {
+	rows := make([][]uint8, height)
+	for y := range rows {
+		rows[y] = make([]uint8, width)
+		for x := range rows[y] {
+			rows[y][x] = value
+		}
+	}
+	return rows
+}
+
+// assertPixels 逐像素比较 got 与 want，尺寸不同或任一像素不同即失败
+func assertPixels(t *testing.T, got image.Image, want *image.RGBA) {
+	t.Helper()
+	if got.Bounds().Size() != want.Rect.Size() {
+		t.Fatalf("size = %v, want %v", got.Bounds().Size(), want.Rect.Size())
+	}
+	gotRGBA := toRGBA(got)
+	for y := 0; y < want.Rect.Dy(); y++ {
+		for x := 0; x < want.Rect.Dx(); x++ {
+			g := gotRGBA.RGBAAt(gotRGBA.Rect.Min.X+x, gotRGBA.Rect.Min.Y+y)
+			w := want.RGBAAt(x, y)
+			if g != w {
+				t.Fatalf("pixel (%d,%d) = %v, want %v", x, y, g, w)
+			}
+		}
+	}
+}
+
+func TestUpscaleGolden(t *testing.T) {
+	src := grayImage([][]uint8{{10, 200}})
+	cases := []struct {
+		name      string
+		below     int
+		maxFactor int
+		want      [][]uint8
+	}{
+		{
+			name:  "factor rounded up to reach below",
+			below: 5, maxFactor: 3,
+			want: [][]uint8{
+				{10, 10, 10, 200, 200, 200},
+				{10, 10, 10, 200, 200, 200},
+				{10, 10, 10, 200, 200, 200},
+			},
+		},
+		{
+			name:  "factor capped by maxFactor",
+			below: 100, maxFactor: 2,
+			want: [][]uint8{
+				{10, 10, 200, 200},
+				{10, 10, 200, 200},
+			},
+		},
+		{
+			name:  "long edge already large enough",
+			below: 2, maxFactor: 3,
+			want: [][]uint8{{10, 200}},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := upscale(copyRGBA(src), tc.below, tc.maxFactor)
+			assertPixels(t, got, grayImage(tc.want))
+		})
+	}
+}
+
+func TestAutoContrastGolden(t *testing.T) {
+	cases := []struct {
+		name string
+		src  [][]uint8
+		want [][]uint8
+	}{
+		{
+			name: "levels stretched to full range",
+			src:  [][]uint8{{50, 100, 150, 200}},
+			want: [][]uint8{{0, 85, 170, 255}},
+		},
+		{
+			name: "flat image left unchanged",
+			src:  [][]uint8{{120, 120, 120}},
+			want: [][]uint8{{120, 120, 120}},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			img := grayImage(tc.src)
+			autoContrast(img)
+			assertPixels(t, img, grayImage(tc.want))
+		})
+	}
+}
+
+func TestSharpenGolden(t *testing.T) {
+	cases := []struct {
+		name string
+		src  [][]uint8
+		want [][]uint8
+	}{
+		{
+			name: "center boosted against neighbours",
+			src: [][]uint8{
+				{50, 50, 50},
+				{50, 60, 50},
+				{50, 50, 50},
+			},
+			want: [][]uint8{
+				{50, 50, 50},
+				{50, 100, 50},
+				{50, 50, 50},
+			},
+		},
+		{
+			name: "results clamped to 0-255",
+			src: [][]uint8{
+				{0, 50, 0, 0},
+				{50, 10, 200, 0},
+				{0, 50, 0, 0},
+			},
+			want: [][]uint8{
+				{0, 50, 0, 0},
+				{50, 0, 255, 0},
+				{0, 50, 0, 0},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			src := grayImage(tc.src)
+			got := sharpen(src)
+			assertPixels(t, got, grayImage(tc.want))
+			assertPixels(t, src, grayImage(tc.src))
+		})
+	}
+}
+
+func TestInvertDarkGolden(t *testing.T) {
+	cases := []struct {
+		name string
+		src  [][]uint8
+		want [][]uint8
+	}{
+		{
+			name: "dark capture inverted",
+			src:  [][]uint8{{0, 30}, {255, 20}},
+			want: [][]uint8{{255, 225}, {0, 235}},
+		},
+		{
+			name: "light capture kept",
+			src:  [][]uint8{{200, 220}, {255, 10}},
+			want: [][]uint8{{200, 220}, {255, 10}},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Enhance(grayImage(tc.src), Enhancement{InvertDark: true})
+			assertPixels(t, got, grayImage(tc.want))
+		})
+	}
+}
+
+func TestTrimBordersGolden(t *testing.T) {
+	src := filled(12, 10, 255)
+	for y := 4; y < 6; y++ {
+		src[y][5], src[y][6] = 0, 0
+	}
+	want := filled(10, 10, 255)
+	for y := 4; y < 6; y++ {
+		want[y][4], want[y][5] = 0, 0
+	}
+
+	got, crop := trimBorders(grayImage(src))
+	if wantCrop := image.Rect(1, 0, 11, 10); crop != wantCrop {
+		t.Fatalf("crop = %v, want %v", crop, wantCrop)
+	}
+	assertPixels(t, got, grayImage(want))
+}
+
+func TestTrimBordersUniformImage(t *testing.T) {
+	src := filled(4, 3, 128)
+	got, crop := trimBorders(grayImage(src))
+	if wantCrop := image.Rect(0, 0, 4, 3); crop != wantCrop {
+		t.Fatalf("crop = %v, want %v", crop, wantCrop)
+	}
+	assertPixels(t, got, grayImage(src))
+}
+
+func TestEnhanceDisabledReturnsInput(t *testing.T) {
+	src := grayImage([][]uint8{{1, 2}, {3, 4}})
+	got, crop := enhance(src, Enhancement{})
+	if got != image.Image(src) {
+		t.Fatal("disabled enhancement should return the input image")
+	}
+	if crop != image.Rect(0, 0, 2, 2) {
+		t.Fatalf("crop = %v", crop)
+	}
+}
@@ -0,0 +1,49 @@
+package imageproc
+
+import (
+	"image"
+	"math/bits"
+)
+
+// PerceptualHash 计算图像的 64 位差异哈希（dHash）：缩小为网格后比较水平相邻格子的明暗，
+// 对轻微缩放、压缩噪点与整体亮度变化不敏感。宽高比超过 3:1 的区域（如字幕条）使用 16x4 网格，
+// 以保留文字排布方向上的细节。
+func PerceptualHash(img image.Image) uint64 {
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return 0
+	}
+	cols, rows := 8, 8
+	switch {
+	case bounds.Dx() >= 3*bounds.Dy():
+		cols, rows = 16, 4
+	case bounds.Dy() >= 3*bounds.Dx():
+		cols, rows = 4, 16
+	}
+
+	// 多取一列，使每行得到 cols 个相邻差值
+	stride := (cols + 1) * 4
+	grid := make([]uint8, stride*rows)
+	src := toRGBA(img)
+	areaAverage(src.Pix, src.Stride, 4, bounds.Dx(), bounds.Dy(), grid, stride, cols+1, rows)
+
+	var hash uint64
+	bit := 0
+	for y := 0; y < rows; y++ {
+		row := grid[y*stride : (y+1)*stride]
+		for x := 0; x < cols; x++ {
+			left := luminance(row[x*4], row[x*4+1], row[x*4+2])
+			right := luminance(row[x*4+4], row[x*4+5], row[x*4+6])
+			if left > right {
+				hash |= 1 << bit
+			}
+			bit++
+		}
+	}
+	return hash
+}
+
+// HashDistance 返回两个感知哈希不同的位数（0-64），数值越大画面差异越大
+func HashDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
@@ -0,0 +1,112 @@
+package imageproc
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// cellImage 构造 cols x rows 个 cellW x cellH 的纯色格子，lum 返回格子的灰度
+func cellImage(cols, rows, cellW, cellH int, lum func(cx, cy int) uint8) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, cols*cellW, rows*cellH))
+	for y := 0; y < rows*cellH; y++ {
+		for x := 0; x < cols*cellW; x++ {
+			value := lum(x/cellW, y/cellH)
+			img.SetRGBA(x, y, color.RGBA{value, value, value, 255})
+		}
+	}
+	return img
+}
+
+// textFrame 为 9x8 个格子的画面，水平相邻格子的灰度至少相差 60
+func textFrame() *image.RGBA {
+	return cellImage(9, 8, 10, 10, func(cx, cy int) uint8 {
+		return uint8(40 + (cx*3+cy*5)%9*20)
+	})
+}
+
+// withNoise 为每个像素叠加 -8 到 8 的确定性噪点，模拟压缩或缩放带来的细微差异
+func withNoise(src *image.RGBA) *image.RGBA {
+	img := image.NewRGBA(src.Rect)
+	for y := 0; y < src.Rect.Dy(); y++ {
+		for x := 0; x < src.Rect.Dx(); x++ {
+			value := int(src.RGBAAt(x, y).R) + (x*31+y*17)%17 - 8
+			img.SetRGBA(x, y, color.RGBA{uint8(value), uint8(value), uint8(value), 255})
+		}
+	}
+	return img
+}
+
+// withText 在画面中部画一条深色横条，模拟出现新的一行文字
+func withText(src *image.RGBA) *image.RGBA {
+	img := image.NewRGBA(src.Rect)
+	copy(img.Pix, src.Pix)
+	for y := 30; y < 50; y++ {
+		for x := 15; x < 75; x++ {
+			img.SetRGBA(x, y, color.RGBA{0, 0, 0, 255})
+		}
+	}
+	return img
+}
+
+func TestHashDistance(t *testing.T) {
+	base := textFrame()
+	cases := []struct {
+		name    string
+		other   *image.RGBA
+		minDist int
+		maxDist int
+	}{
+		{"identical frame", textFrame(), 0, 0},
+		{"compression noise", withNoise(base), 0, 4},
+		{"changed text", withText(base), 5, 64},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := HashDistance(PerceptualHash(base), PerceptualHash(tc.other))
+			if d < tc.minDist || d > tc.maxDist {
+				t.Fatalf("distance = %d, want %d-%d", d, tc.minDist, tc.maxDist)
+			}
+		})
+	}
+}
+
+func TestPerceptualHashGrid(t *testing.T) {
+	cases := []struct {
+		name string
+		img  *image.RGBA
+		want uint64
+	}{
+		{
+			// 17x4 个格子：左侧 9 个格子逐渐变暗，其余不变，每行只有前 8 位为 1
+			name: "wide region uses 16x4 grid",
+			img: cellImage(17, 4, 10, 5, func(cx, cy int) uint8 {
+				return uint8(200 - 20*min(cx, 8))
+			}),
+			want: 0x00FF00FF00FF00FF,
+		},
+		{
+			// 5x16 个格子：偶数行向右变暗、奇数行向右变亮，8x8 网格会把相邻两行平均掉
+			name: "tall region uses 4x16 grid",
+			img: cellImage(5, 16, 4, 10, func(cx, cy int) uint8 {
+				if cy%2 == 0 {
+					return uint8(200 - 30*cx)
+				}
+				return uint8(40 + 30*cx)
+			}),
+			want: 0x0F0F0F0F0F0F0F0F,
+		},
+		{
+			name: "empty image",
+			img:  image.NewRGBA(image.Rectangle{}),
+			want: 0,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := PerceptualHash(tc.img); got != tc.want {
+				t.Fatalf("PerceptualHash = %#016x, want %#016x", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package imageproc
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"strings"
+)
+
+// 支持的输出格式
+const (
+	FormatPNG  = "png"
+	FormatJPEG = "jpeg"
+)
+
+const (
+	// DefaultJPEGQuality 为未指定质量时的 JPEG 编码质量
+	DefaultJPEGQuality = 85
+	// minJPEGQuality 为压缩到字节预算以内时允许降低到的最低质量
+	minJPEGQuality = 50
+	// minLongEdge 为压缩到字节预算以内时允许缩小到的最短长边，再小文字将无法辨认
+	minLongEdge = 480
+	// shrinkRatio 为超出字节预算时每轮缩小的比例
+	shrinkRatio = 0.8
+)
+
+// Options 控制上传前的图像预处理，零值字段表示不做对应处理
+type Options struct {
+	// Enhance 为缩放与编码前执行的文字增强步骤
+	Enhance Enhancement
+	// MaxLongEdge 为长边的最大像素数，超出时等比缩小
+	MaxLongEdge int
+	// Format 为输出格式（png 或 jpeg），留空为 png
+	Format string
+	// JPEGQuality 为 JPEG 编码质量（1-100），仅在 Format 为 jpeg 时生效
+	JPEGQuality int
+	// Grayscale 为 true 时转换为灰度图，可进一步减小体积
+	Grayscale bool
+	// MaxBytes 为编码后的字节预算，超出时依次降低 JPEG 质量与缩小尺寸
+	MaxBytes int
+}
+
+// Output 为预处理后的图像
+type Output struct {
+	Data     []byte
+	MIMEType string
+	Width    int
+	Height   int
+	// Crop 为输出图像对应的原图区域（相对于原图左上角），裁边后小于原图，缩放不改变该区域
+	Crop image.Rectangle
+}
+
+// NormalizeFormat 将格式名称归一化为 png 或 jpeg
+func NormalizeFormat(format string) string {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "jpeg", "jpg", "image/jpeg":
+		return FormatJPEG
+	default:
+		return FormatPNG
+	}
+}
+
+// MIMEType 返回格式对应的 MIME 类型
+func MIMEType(format string) string {
+	if NormalizeFormat(format) == FormatJPEG {
+		return "image/jpeg"
+	}
+	return "image/png"
+}
+
+// Process 按选项增强、缩放、转换并编码图像。超出字节预算时 JPEG 先逐步降低质量，
+// 仍然超出则逐步缩小尺寸；缩小到下限后返回最后一次的结果，不视为错误。
+func Process(img image.Image, opts Options) (Output, error) {
+	if img == nil || img.Bounds().Empty() {
+		return Output{}, fmt.Errorf("图像为空")
+	}
+
+	format := NormalizeFormat(opts.Format)
+	quality := opts.JPEGQuality
+	if quality <= 0 || quality > 100 {
+		quality = DefaultJPEGQuality
+	}
+
+	img, crop := enhance(img, opts.Enhance)
+	if opts.MaxLongEdge > 0 {
+		img = resize(img, opts.MaxLongEdge)
+	}
+	if opts.Grayscale {
+		img = grayscale(img)
+	}
+
+	for {
+		data, err := encode(img, format, quality)
+		if err != nil {
+			return Output{}, err
+		}
+		bounds := img.Bounds()
+		output := Output{
+			Data:     data,
+			MIMEType: MIMEType(format),
+			Width:    bounds.Dx(),
+			Height:   bounds.Dy(),
+			Crop:     crop,
+		}
+		if opts.MaxBytes <= 0 || len(data) <= opts.MaxBytes {
+			return output, nil
+		}
+
+		if format == FormatJPEG && quality > minJPEGQuality {
+			quality = max(quality-15, minJPEGQuality)
+			continue
+		}
+		edge := longEdge(bounds)
+		if edge <= minLongEdge {
+			return output, nil
+		}
+		img = resize(img, max(int(float64(edge)*shrinkRatio), minLongEdge))
+	}
+}
+
+func encode(img image.Image, format string, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if format == FormatJPEG {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("编码图像失败: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func longEdge(bounds image.Rectangle) int {
+	return max(bounds.Dx(), bounds.Dy())
+}
+
+// resize 将长边等比缩小到 limit 以内，采用区域平均以保留细小文字的笔画；不会放大图像
+func resize(img image.Image, limit int) image.Image {
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	edge := longEdge(bounds)
+	if edge <= limit {
+		return img
+	}
+
+	dstW := max(srcW*limit/edge, 1)
+	dstH := max(srcH*limit/edge, 1)
+
+	if gray, ok := img.(*image.Gray); ok && gray.Rect.Min == (image.Point{}) {
+		dst := image.NewGray(image.Rect(0, 0, dstW, dstH))
+		areaAverage(gray.Pix, gray.Stride, 1, srcW, srcH, dst.Pix, dst.Stride, dstW, dstH)
+		return dst
+	}
+	src := toRGBA(img)
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	areaAverage(src.Pix, src.Stride, 4, srcW, srcH, dst.Pix, dst.Stride, dstW, dstH)
+	return dst
+}
+
+// areaAverage 对每个目标像素取其覆盖的源像素区域的平均值，channels 为每像素的字节数
+func areaAverage(src []uint8, srcStride, channels, srcW, srcH int, dst []uint8, dstStride, dstW, dstH int) {
+	sums := make([]uint32, channels)
+	for y := 0; y < dstH; y++ {
+		y0 := y * srcH / dstH
+		y1 := max((y+1)*srcH/dstH, y0+1)
+		for x := 0; x < dstW; x++ {
+			x0 := x * srcW / dstW
+			x1 := max((x+1)*srcW/dstW, x0+1)
+			clear(sums)
+			for sy := y0; sy < y1; sy++ {
+				row := src[sy*srcStride+x0*channels : sy*srcStride+x1*channels]
+				for i, value := range row {
+					sums[i%channels] += uint32(value)
+				}
+			}
+			count := uint32((y1 - y0) * (x1 - x0))
+			offset := y*dstStride + x*channels
+			for c := 0; c < channels; c++ {
+				dst[offset+c] = uint8(sums[c] / count)
+			}
+		}
+	}
+}
+
+// toRGBA 返回从原点开始的 *image.RGBA，必要时复制像素
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba
+	}
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return rgba
+}
+
+// grayscale 按亮度转换为灰度图
+func grayscale(img image.Image) image.Image {
+	if _, ok := img.(*image.Gray); ok {
+		return img
+	}
+	bounds := img.Bounds()
+	gray := image.NewGray(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			gray.SetGray(x, y, color.GrayModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.Gray))
+		}
+	}
+	return gray
+}
@@ -150,6 +150,72 @@ func BuildVisionDirectTranslationPrompt(vars PromptVariables) string {
 直接输出翻译后的文字，不要添加任何其他内容。`, targetLang, sourceLang)
 }
 
+// BuildLayoutExtractPrompt 构建版面分块模式的提取提示词，要求按文本块返回原文与归一化坐标
+func BuildLayoutExtractPrompt(vars PromptVariables) string {
+	sourceLang := getLanguageDisplayName(vars.SourceLanguage)
+	if vars.SourceLanguage == "auto" {
+		sourceLang = "图像中的原始语言"
+	}
+
+	return fmt.Sprintf(`你是一个专业的版面分析与文字识别专家。请将图像中的文字按版面划分为若干文本块（段落、标题、按钮、标签、对话气泡等），并给出每个文本块的位置。
+**识别要求：**
+1. 每个文本块内的文字保持%[1]s原文，保留块内的换行与符号；
+2. 位置为文本块的外接矩形 [x0, y0, x1, y1]，以图像左上角为原点，横纵坐标均按图像宽高归一化到 0-1000 的整数；
+3. 文本块按阅读顺序（从上到下、从左到右）排列，相邻且属于同一句话的行合并为一个块；
+4. "background" 字段简要描述图像场景，供翻译时参考。
+
+**输出格式：**
+严格输出以下 JSON，不要添加任何额外说明：
+{
+  "background": "...",
+  "blocks": [
+    {"text": "...", "box": [x0, y0, x1, y1]}
+  ]
+}`, sourceLang)
+}
+
+// BuildLayoutTranslatePrompt 构建版面分块模式的翻译提示词，要求按输入顺序逐块返回译文数组
+func BuildLayoutTranslatePrompt(vars PromptVariables) string {
+	targetLang := getLanguageDisplayName(vars.TargetLanguage)
+	sourceLang := getLanguageDisplayName(vars.SourceLanguage)
+
+	return fmt.Sprintf(`你是一个专业的翻译 AI。你将收到一个 JSON 对象：
+- "background" 字段提供场景参考；
+- "blocks" 字段为按阅读顺序排列的文本块原文（语种：%[2]s），它们来自同一画面，前后文相互关联。
+
+请结合全部文本块的上下文，将每个文本块翻译为%[1]s。遵循以下原则：
+1. 译文与 "blocks" 一一对应，数量和顺序必须完全一致，不得合并、拆分或遗漏；
+2. 保持块内的换行、专有名词、数字与符号；
+3. 无需翻译的内容（如代码、纯数字）原样保留。
+
+**输出格式：**
+只输出一个 JSON 字符串数组，例如 ["译文1", "译文2"]，不要添加任何额外说明。`, targetLang, sourceLang)
+}
+
+// BuildLayoutDirectPrompt 构建版面分块模式的视觉直出提示词，要求同时返回每个文本块的原文、译文与位置
+func BuildLayoutDirectPrompt(vars PromptVariables) string {
+	targetLang := getLanguageDisplayName(vars.TargetLanguage)
+	sourceLang := getLanguageDisplayName(vars.SourceLanguage)
+	if vars.SourceLanguage == "auto" {
+		sourceLang = "自动检测到的语言"
+	}
+
+	return fmt.Sprintf(`你是一个专业的视觉翻译专家。请将图像中的文字按版面划分为若干文本块（段落、标题、按钮、标签、对话气泡等），并结合整幅图像的上下文将每个文本块从%[2]s翻译为%[1]s。
+**要求：**
+1. 位置为文本块的外接矩形 [x0, y0, x1, y1]，以图像左上角为原点，横纵坐标均按图像宽高归一化到 0-1000 的整数；
+2. 文本块按阅读顺序（从上到下、从左到右）排列，相邻且属于同一句话的行合并为一个块；
+3. 译文保持块内的换行、专有名词、数字与符号。
+
+**输出格式：**
+严格输出以下 JSON，不要添加任何额外说明：
+{
+  "background": "...",
+  "blocks": [
+    {"text": "原文", "translation": "译文", "box": [x0, y0, x1, y1]}
+  ]
+}`, targetLang, sourceLang)
+}
+
 // 可覆盖的提示词，允许运行时根据配置动态调整
 var (
 	ExtractPrompt   = DefaultExtractPrompt
@@ -0,0 +1,143 @@
+package screenshot
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/kbinani/screenshot"
+)
+
+// 截图后端名称，对应设置中的 captureBackend
+const (
+	BackendAuto = "auto"
+	BackendX11  = "x11"
+)
+
+// Capturer 为截图后端，rect 为屏幕坐标系下的截图区域。
+// 返回图像的原点为 (0, 0)，尺寸与 rect 一致。
+type Capturer interface {
+	CaptureRect(rect image.Rectangle) (image.Image, error)
+}
+
+// DefaultCapturer 为未显式指定后端时使用的截图实现
+var DefaultCapturer Capturer = KbinaniCapturer{}
+
+// NewCapturer 按名称创建截图后端，空字符串与 "auto" 使用 kbinani 后端
+func NewCapturer(backend string) (Capturer, error) {
+	switch strings.ToLower(strings.TrimSpace(backend)) {
+	case "", BackendAuto:
+		return DefaultCapturer, nil
+	case BackendX11:
+		capturer, err := NewX11Capturer("")
+		if err != nil {
+			return nil, err
+		}
+		return capturer, nil
+	default:
+		return nil, fmt.Errorf("未知的截图后端: %s", backend)
+	}
+}
+
+// CaptureWith 使用指定后端截取区域，capturer 为 nil 时使用 DefaultCapturer
+func CaptureWith(capturer Capturer, startX, startY, endX, endY int) (image.Image, error) {
+	if capturer == nil {
+		capturer = DefaultCapturer
+	}
+	// image.Rect 会保证坐标为左上到右下
+	img, err := capturer.CaptureRect(image.Rect(startX, startY, endX, endY))
+	if err != nil {
+		return nil, fmt.Errorf("截图失败: %v", err)
+	}
+	return img, nil
+}
+
+// KbinaniCapturer 基于 kbinani/screenshot 截图，支持 Windows、macOS 与 Linux
+type KbinaniCapturer struct{}
+
+// CaptureRect 实现 Capturer
+func (KbinaniCapturer) CaptureRect(rect image.Rectangle) (image.Image, error) {
+	return screenshot.CaptureRect(rect)
+}
+
+// ImageCapturer 从固定图像中裁剪截图区域，用于测试或回放已保存的截图。
+// 图像坐标即屏幕坐标，超出图像的部分以不透明黑色填充，与真实屏幕边缘的行为一致。
+type ImageCapturer struct {
+	mu    sync.Mutex
+	image image.Image
+	err   error
+	rects []image.Rectangle
+}
+
+// NewImageCapturer 创建基于内存图像的截图后端
+func NewImageCapturer(img image.Image) *ImageCapturer {
+	return &ImageCapturer{image: img}
+}
+
+// NewFileCapturer 读取 PNG、JPEG 或 GIF 文件作为截图来源
+func NewFileCapturer(path string) (*ImageCapturer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开截图文件失败: %w", err)
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("解码截图文件失败: %w", err)
+	}
+	return NewImageCapturer(img), nil
+}
+
+// SetImage 替换截图来源图像，用于模拟画面变化
+func (c *ImageCapturer) SetImage(img image.Image) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.image = img
+}
+
+// SetError 设置后续截图返回的错误，传入 nil 恢复正常截图
+func (c *ImageCapturer) SetError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = err
+}
+
+// Rects 返回已请求过的截图区域，按调用顺序排列
+func (c *ImageCapturer) Rects() []image.Rectangle {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]image.Rectangle(nil), c.rects...)
+}
+
+// CaptureRect 实现 Capturer
+func (c *ImageCapturer) CaptureRect(rect image.Rectangle) (image.Image, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rects = append(c.rects, rect)
+	if c.err != nil {
+		return nil, c.err
+	}
+	if c.image == nil {
+		return nil, errors.New("未设置截图来源图像")
+	}
+	if rect.Empty() {
+		return nil, fmt.Errorf("截图区域为空: %v", rect)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	visible := rect.Intersect(c.image.Bounds())
+	if !visible.Empty() {
+		draw.Draw(img, visible.Sub(rect.Min), c.image, visible.Min, draw.Src)
+	}
+	return img, nil
+}
@@ -0,0 +1,113 @@
+//go:build linux || freebsd || openbsd || netbsd
+
+package screenshot
+
+import (
+	"fmt"
+	"image"
+	"sync"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/xproto"
+)
+
+// X11Capturer 通过 X11 协议直接读取根窗口像素，可在 Xvfb 等无物理屏幕的环境下使用。
+// 连接在创建时建立并复用，使用完毕需调用 Close。
+type X11Capturer struct {
+	mu        sync.Mutex
+	conn      *xgb.Conn
+	root      xproto.Window
+	bounds    image.Rectangle
+	bigEndian bool
+}
+
+// NewX11Capturer 连接指定的 X 显示，display 为空时读取 DISPLAY 环境变量
+func NewX11Capturer(display string) (*X11Capturer, error) {
+	conn, err := xgb.NewConnDisplay(display)
+	if err != nil {
+		return nil, fmt.Errorf("连接 X11 显示失败: %w", err)
+	}
+
+	setup := xproto.Setup(conn)
+	screen := setup.DefaultScreen(conn)
+	bitsPerPixel := 0
+	for _, format := range setup.PixmapFormats {
+		if format.Depth == screen.RootDepth {
+			bitsPerPixel = int(format.BitsPerPixel)
+			break
+		}
+	}
+	// 仅支持每像素 32 位的真彩色根窗口（深度 24 或 32），这也是 Xvfb 与常见桌面的默认配置
+	if bitsPerPixel != 32 {
+		conn.Close()
+		return nil, fmt.Errorf("不支持的 X11 像素格式: 深度 %d，每像素 %d 位", screen.RootDepth, bitsPerPixel)
+	}
+
+	return &X11Capturer{
+		conn:      conn,
+		root:      screen.Root,
+		bounds:    image.Rect(0, 0, int(screen.WidthInPixels), int(screen.HeightInPixels)),
+		bigEndian: setup.ImageByteOrder == xproto.ImageOrderMSBFirst,
+	}, nil
+}
+
+// Close 关闭 X11 连接
+func (c *X11Capturer) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+	return nil
+}
+
+// CaptureRect 实现 Capturer，超出屏幕的部分以不透明黑色填充
+func (c *X11Capturer) CaptureRect(rect image.Rectangle) (image.Image, error) {
+	if rect.Empty() {
+		return nil, fmt.Errorf("截图区域为空: %v", rect)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 0xff
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil, fmt.Errorf("X11 连接已关闭")
+	}
+
+	visible := rect.Intersect(c.bounds)
+	if visible.Empty() {
+		return img, nil
+	}
+
+	reply, err := xproto.GetImage(c.conn, xproto.ImageFormatZPixmap, xproto.Drawable(c.root),
+		int16(visible.Min.X), int16(visible.Min.Y),
+		uint16(visible.Dx()), uint16(visible.Dy()), 0xffffffff).Reply()
+	if err != nil {
+		return nil, fmt.Errorf("读取 X11 图像失败: %w", err)
+	}
+	if len(reply.Data) < visible.Dx()*visible.Dy()*4 {
+		return nil, fmt.Errorf("X11 图像数据不完整: %d 字节", len(reply.Data))
+	}
+
+	// ZPixmap 每像素 4 字节，小端序为 B G R X，大端序为 X R G B
+	offset := 0
+	for y := visible.Min.Y; y < visible.Max.Y; y++ {
+		row := img.PixOffset(visible.Min.X-rect.Min.X, y-rect.Min.Y)
+		for x := visible.Min.X; x < visible.Max.X; x++ {
+			pixel := reply.Data[offset : offset+4]
+			if c.bigEndian {
+				img.Pix[row], img.Pix[row+1], img.Pix[row+2] = pixel[1], pixel[2], pixel[3]
+			} else {
+				img.Pix[row], img.Pix[row+1], img.Pix[row+2] = pixel[2], pixel[1], pixel[0]
+			}
+			row += 4
+			offset += 4
+		}
+	}
+	return img, nil
+}
@@ -0,0 +1,24 @@
+//go:build !(linux || freebsd || openbsd || netbsd)
+
+package screenshot
+
+import (
+	"errors"
+	"image"
+)
+
+var errX11Unsupported = errors.New("当前平台不支持 X11 截图后端")
+
+// X11Capturer 在非 X11 平台上不可用
+type X11Capturer struct{}
+
+// NewX11Capturer 在非 X11 平台上始终返回错误
+func NewX11Capturer(_ string) (*X11Capturer, error) { return nil, errX11Unsupported }
+
+// Close 实现与 X11 平台一致的接口
+func (c *X11Capturer) Close() error { return nil }
+
+// CaptureRect 实现 Capturer
+func (c *X11Capturer) CaptureRect(_ image.Rectangle) (image.Image, error) {
+	return nil, errX11Unsupported
+}
@@ -0,0 +1,29 @@
+package screenshot
+
+import (
+	"fmt"
+	"image"
+
+	"Translater/core/display"
+)
+
+// MonitorCount 返回当前活动显示器的数量
+func MonitorCount() int {
+	topology, err := display.Current()
+	if err != nil {
+		return 0
+	}
+	return len(topology.Monitors)
+}
+
+// MonitorBounds 返回第 index 个显示器（从 0 开始）在虚拟桌面坐标系中的物理像素范围
+func MonitorBounds(index int) (image.Rectangle, error) {
+	topology, err := display.Current()
+	if err != nil {
+		return image.Rectangle{}, err
+	}
+	if index < 0 || index >= len(topology.Monitors) {
+		return image.Rectangle{}, fmt.Errorf("显示器编号 %d 超出范围，当前共有 %d 个显示器", index, len(topology.Monitors))
+	}
+	return topology.Monitors[index].Bounds, nil
+}
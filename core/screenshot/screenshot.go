@@ -3,6 +3,7 @@ package screenshot
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"image"
 	"image/png"
@@ -10,10 +11,12 @@ import (
 	"sync"
 	"time"
 
-	"github.com/kbinani/screenshot"
 	hook "github.com/robotn/gohook"
 )
 
+// ErrNoLastRegion 表示尚未完成过截图，无法重复上一次的区域
+var ErrNoLastRegion = errors.New("尚无上一次截图区域")
+
 // CaptureHandler 截图处理函数类型
 type CaptureHandler func(ctx context.Context, startX, startY, endX, endY int) bool
 
@@ -21,8 +24,11 @@ type CaptureHandler func(ctx context.Context, startX, startY, endX, endY int) bo
 type Manager struct {
 	mu        sync.Mutex
 	onCapture CaptureHandler
+	onWatch   CaptureHandler
 	cancel    context.CancelFunc
 	done      chan struct{}
+	// lastRegion 为最近一次交给截图处理函数的区域
+	lastRegion image.Rectangle
 }
 
 // NewManager 创建新的截图管理器
@@ -37,6 +43,13 @@ func (m *Manager) SetCaptureHandler(handler CaptureHandler) {
 	m.onCapture = handler
 }
 
+// SetWatchHandler 设置监视模式的处理函数，处理函数应持续运行直到 ctx 被取消
+func (m *Manager) SetWatchHandler(handler CaptureHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onWatch = handler
+}
+
 // CancelActiveCapture 主动终止正在进行的截图任务（如果存在）
 func (m *Manager) CancelActiveCapture() {
 	m.mu.Lock()
@@ -52,21 +65,73 @@ func (m *Manager) CancelActiveCapture() {
 	}
 }
 
-// StartOnce 执行一次截图操作
+// StartOnce 执行一次截图操作，由用户拖拽选择截图区域
 func (m *Manager) StartOnce() {
+	m.run(nil, false)
+}
+
+// StartRegion 直接截取固定的屏幕区域，无需拖拽；翻译过程中仍可按 Esc 取消
+func (m *Manager) StartRegion(rect image.Rectangle) {
+	rect = rect.Canon()
+	m.run(&rect, false)
+}
+
+// StartWatch 将固定区域交给监视处理函数持续监视，直到按 Esc 或调用 CancelActiveCapture
+func (m *Manager) StartWatch(rect image.Rectangle) {
+	rect = rect.Canon()
+	m.run(&rect, true)
+}
+
+// StartMonitor 截取第 index 个显示器（从 0 开始）的完整画面
+func (m *Manager) StartMonitor(index int) error {
+	bounds, err := MonitorBounds(index)
+	if err != nil {
+		return err
+	}
+	m.StartRegion(bounds)
+	return nil
+}
+
+// StartLast 重新截取上一次使用的区域，尚无记录时返回 ErrNoLastRegion
+func (m *Manager) StartLast() error {
+	rect, ok := m.LastRegion()
+	if !ok {
+		return ErrNoLastRegion
+	}
+	m.StartRegion(rect)
+	return nil
+}
+
+// LastRegion 返回最近一次截图使用的区域
+func (m *Manager) LastRegion() (image.Rectangle, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lastRegion, !m.lastRegion.Empty()
+}
+
+// run 执行一次截图任务。region 为 nil 时监听鼠标拖拽选区，否则直接处理给定区域；
+// watch 为 true 时使用监视处理函数
+func (m *Manager) run(region *image.Rectangle, watch bool) {
 	// 确保不会存在遗留的截图任务
 	m.CancelActiveCapture()
 
-	fmt.Println("开始监听鼠标事件...")
-	fmt.Println("请按下鼠标左键并拖拽，然后释放来选择截图区域")
-
 	ctx, cancel := context.WithCancel(context.Background())
 	done := make(chan struct{})
 
-	preview := newSelectionPreview()
-	if err := preview.Start(); err != nil {
-		fmt.Printf("选区预览启动失败: %v\n", err)
-		preview = nil
+	var preview selectionPreview
+	if region == nil {
+		fmt.Println("开始监听鼠标事件...")
+		fmt.Println("请按下鼠标左键并拖拽，然后释放来选择截图区域")
+
+		preview = newSelectionPreview()
+		if err := preview.Start(); err != nil {
+			fmt.Printf("选区预览启动失败: %v\n", err)
+			preview = nil
+		}
+	} else if watch {
+		fmt.Printf("开始监视区域: %v，按 Esc 停止\n", *region)
+	} else {
+		fmt.Printf("截取固定区域: %v\n", *region)
 	}
 
 	m.mu.Lock()
@@ -96,6 +161,34 @@ func (m *Manager) StartOnce() {
 	var translationStarted bool
 	var translationDone chan bool
 
+	// begin 记录区域并在后台调用截图处理函数，未设置处理函数时返回 false
+	begin := func(x0, y0, x1, y1 int) bool {
+		handler := m.getCaptureHandler()
+		if watch {
+			handler = m.getWatchHandler()
+		}
+		if handler == nil {
+			fmt.Println("未设置截图处理函数，直接退出")
+			fmt.Println("截图监听已结束，等待下次热键触发...")
+			return false
+		}
+		if rect := image.Rect(x0, y0, x1, y1); !rect.Empty() {
+			m.mu.Lock()
+			m.lastRegion = rect
+			m.mu.Unlock()
+		}
+		translationDone = make(chan bool, 1)
+		translationStarted = true
+		go func() {
+			defer close(translationDone)
+			translationDone <- handler(ctx, x0, y0, x1, y1)
+		}()
+		return true
+	}
+	if region != nil && !begin(region.Min.X, region.Min.Y, region.Max.X, region.Max.Y) {
+		return
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -147,17 +240,7 @@ func (m *Manager) StartOnce() {
 					}
 					fmt.Printf("鼠标释放: (%d, %d)\n", endX, endY)
 
-					handler := m.getCaptureHandler()
-					if handler != nil {
-						translationDone = make(chan bool, 1)
-						translationStarted = true
-						go func() {
-							defer close(translationDone)
-							translationDone <- handler(ctx, startX, startY, endX, endY)
-						}()
-					} else {
-						fmt.Println("未设置截图处理函数，直接退出")
-						fmt.Println("截图监听已结束，等待下次热键触发...")
+					if !begin(startX, startY, endX, endY) {
 						return
 					}
 				}
@@ -184,23 +267,18 @@ func (m *Manager) getCaptureHandler() CaptureHandler {
 	return m.onCapture
 }
 
+func (m *Manager) getWatchHandler() CaptureHandler {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.onWatch
+}
+
 // Capture 截取指定区域的屏幕并保存
 func Capture(startX, startY, endX, endY int) bool {
-	// 确保坐标是正确的（左上到右下）
-	if startX > endX {
-		startX, endX = endX, startX
-	}
-	if startY > endY {
-		startY, endY = endY, startY
-	}
-
-	// 创建一个 Rectangle 对象
-	rect := image.Rect(startX, startY, endX, endY)
-
 	// 截图
-	img, err := screenshot.CaptureRect(rect)
+	img, err := CaptureImage(startX, startY, endX, endY)
 	if err != nil {
-		fmt.Printf("截图失败: %v\n", err)
+		fmt.Printf("%v\n", err)
 		return false
 	}
 
@@ -232,23 +310,16 @@ func saveImage(img image.Image, filename string) bool {
 	return true
 }
 
-// CaptureToBytes 截取指定区域的屏幕并返回图像字节数据
-func CaptureToBytes(startX, startY, endX, endY int) ([]byte, error) {
-	// 确保坐标是正确的（左上到右下）
-	if startX > endX {
-		startX, endX = endX, startX
-	}
-	if startY > endY {
-		startY, endY = endY, startY
-	}
-
-	// 创建一个 Rectangle 对象
-	rect := image.Rect(startX, startY, endX, endY)
+// CaptureImage 使用默认截图后端截取指定区域的屏幕并返回未编码的图像
+func CaptureImage(startX, startY, endX, endY int) (image.Image, error) {
+	return CaptureWith(DefaultCapturer, startX, startY, endX, endY)
+}
 
-	// 截图
-	img, err := screenshot.CaptureRect(rect)
+// CaptureToBytes 截取指定区域的屏幕并返回无损 PNG 字节数据
+func CaptureToBytes(startX, startY, endX, endY int) ([]byte, error) {
+	img, err := CaptureImage(startX, startY, endX, endY)
 	if err != nil {
-		return nil, fmt.Errorf("截图失败: %v", err)
+		return nil, err
 	}
 
 	// 将图像编码为字节数据
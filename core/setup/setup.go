@@ -0,0 +1,128 @@
+package setup
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"Translater/core/ai"
+	"Translater/core/config"
+	"Translater/core/translation"
+)
+
+// EnvFiles 为未在设置中填写 API Key 时依次尝试读取的文件，兼容从 frontend 目录启动
+var EnvFiles = []string{".env", "env", "../.env", "../env"}
+
+// ResolveAPIKeys 根据 useVisionForTranslation 设置解析主 API Key 和翻译 API Key
+// 主 API Key 优先从 visionApiKeyOverride 读取（向前兼容），翻译 API Key 根据模式决定
+func ResolveAPIKeys(settings config.Settings) (mainKey string, translateKey string, err error) {
+	// 1. 解析主 API Key（视觉 API Key 优先）
+	mainKey = strings.TrimSpace(settings.VisionAPIKeyOverride)
+	if mainKey == "" {
+		// 向后兼容：回退到 apiKeyOverride
+		mainKey = strings.TrimSpace(settings.APIKeyOverride)
+	}
+	if mainKey == "" {
+		// 最后尝试从文件读取
+		reader := config.NewFileAPIKeyReader(EnvFiles)
+		mainKey, err = reader.ReadAPIKey()
+		if err != nil || mainKey == "" {
+			return "", "", fmt.Errorf("需要配置视觉 API Key (visionApiKeyOverride) 或在 .env 文件中设置")
+		}
+	}
+
+	// 2. 解析翻译 API Key
+	if settings.UseVisionForTranslation {
+		// 视觉直出模式：翻译也用主 key
+		translateKey = mainKey
+	} else {
+		// 文本模型模式：翻译 key 可选，留空则回退到主 key
+		translateKey = strings.TrimSpace(settings.APIKeyOverride)
+		if translateKey == "" {
+			translateKey = mainKey
+		}
+	}
+
+	return mainKey, translateKey, nil
+}
+
+// ClientConfig 根据配置解析接口地址、模型、协议与 API Key，生成客户端配置。
+// OnUsage 需由调用方按需注入
+func ClientConfig(settings config.Settings) (ai.ClientConfig, error) {
+	mainKey, translateKey, err := ResolveAPIKeys(settings)
+	if err != nil {
+		return ai.ClientConfig{}, err
+	}
+
+	baseURL := ai.NormalizeBaseURL(settings.APIBaseURL)
+	translateModel := strings.TrimSpace(settings.TranslateModel)
+	if translateModel == "" {
+		translateModel = ai.DefaultTranslateModel
+	}
+	visionModel := strings.TrimSpace(settings.VisionModel)
+	if visionModel == "" {
+		visionModel = ai.DefaultVisionModel
+	}
+
+	// 视觉 API 配置（使用主 key）
+	visionBaseURL := strings.TrimSpace(settings.VisionAPIBaseURL)
+	if visionBaseURL == "" {
+		visionBaseURL = baseURL
+	} else {
+		visionBaseURL = ai.NormalizeBaseURL(visionBaseURL)
+	}
+
+	provider := ai.NormalizeProvider(settings.TranslateProvider)
+	visionProvider := strings.TrimSpace(settings.VisionProvider)
+	if visionProvider == "" {
+		visionProvider = provider
+	} else {
+		visionProvider = ai.NormalizeProvider(visionProvider)
+	}
+
+	return ai.ClientConfig{
+		APIKey:             translateKey,
+		BaseURL:            baseURL,
+		TranslateModel:     translateModel,
+		VisionModel:        visionModel,
+		VisionAPIKey:       mainKey,
+		VisionBaseURL:      visionBaseURL,
+		Provider:           provider,
+		VisionProvider:     visionProvider,
+		TranslateFallbacks: settings.TranslateFallbacks,
+		VisionFallbacks:    settings.VisionFallbacks,
+		Timeouts:           settings.Timeouts.Client(),
+		DetectRefusals:     settings.DetectRefusals,
+		FallbackOnBlocked:  settings.FallbackOnBlocked,
+	}, nil
+}
+
+// TranslationOptions 根据配置生成翻译服务选项
+func TranslationOptions(settings config.Settings) translation.Options {
+	return translation.Options{
+		Stream:                  settings.EnableStreamOutput,
+		UseVisionForTranslation: settings.UseVisionForTranslation,
+		LayoutBlocks:            settings.LayoutBlocks,
+		PipelineTranslation:     settings.PipelineTranslation,
+		SourceLanguage:          settings.SourceLanguage,
+		TargetLanguage:          settings.TargetLanguage,
+		Budget:                  settings.Budget,
+		ExtractSampling:         settings.Sampling.Extract,
+		TranslateSampling:       settings.Sampling.Translate,
+		DirectSampling:          settings.Sampling.Direct,
+		ShowReasoning:           settings.ShowReasoning,
+		Deadlines:               StageDeadlines(settings.Timeouts),
+		Image:                   settings.Image.Options(),
+		AutoContinue:            settings.AutoContinue,
+		MaxContinuations:        settings.MaxContinuations,
+	}
+}
+
+// StageDeadlines 将各阶段的秒数配置转换为翻译服务的总时长预算
+func StageDeadlines(timeouts config.TimeoutSettings) translation.StageDeadlines {
+	return translation.StageDeadlines{
+		Capture:   time.Duration(timeouts.CaptureSeconds) * time.Second,
+		OCR:       time.Duration(timeouts.OCRSeconds) * time.Second,
+		Translate: time.Duration(timeouts.TranslateSeconds) * time.Second,
+	}
+}
@@ -0,0 +1,99 @@
+package setup
+
+import (
+	"testing"
+	"time"
+
+	"Translater/core/ai"
+	"Translater/core/config"
+)
+
+func TestClientConfigKeys(t *testing.T) {
+	cases := []struct {
+		name          string
+		visionKey     string
+		translateKey  string
+		visionOnly    bool
+		wantVision    string
+		wantTranslate string
+	}{
+		{"separate translation key", "vision", "text", false, "vision", "text"},
+		{"translation key falls back to vision key", "vision", "", false, "vision", "vision"},
+		{"vision-only mode ignores translation key", "vision", "text", true, "vision", "vision"},
+		{"legacy translation key used for both", "", "legacy", false, "legacy", "legacy"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			settings := config.DefaultSettings()
+			settings.VisionAPIKeyOverride = tc.visionKey
+			settings.APIKeyOverride = tc.translateKey
+			settings.UseVisionForTranslation = tc.visionOnly
+			cfg, err := ClientConfig(settings)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.VisionAPIKey != tc.wantVision || cfg.APIKey != tc.wantTranslate {
+				t.Fatalf("keys = %q/%q, want %q/%q", cfg.VisionAPIKey, cfg.APIKey, tc.wantVision, tc.wantTranslate)
+			}
+		})
+	}
+}
+
+func TestClientConfigFromSettings(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.VisionAPIKeyOverride = "key"
+	settings.APIBaseURL = " https://api.example.com/v1/ "
+	settings.VisionAPIBaseURL = ""
+	settings.TranslateModel = ""
+	settings.VisionProvider = ""
+	settings.TranslateProvider = ai.ProviderAnthropic
+	settings.Timeouts.ConnectSeconds = 7
+	settings.DetectRefusals = true
+	settings.FallbackOnBlocked = false
+
+	cfg, err := ClientConfig(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.BaseURL != "https://api.example.com/v1" || cfg.VisionBaseURL != cfg.BaseURL {
+		t.Errorf("base URLs = %q/%q", cfg.BaseURL, cfg.VisionBaseURL)
+	}
+	if cfg.TranslateModel != ai.DefaultTranslateModel {
+		t.Errorf("TranslateModel = %q", cfg.TranslateModel)
+	}
+	if cfg.Provider != ai.ProviderAnthropic || cfg.VisionProvider != ai.ProviderAnthropic {
+		t.Errorf("providers = %q/%q", cfg.Provider, cfg.VisionProvider)
+	}
+	if cfg.Timeouts != settings.Timeouts.Client() || cfg.Timeouts.Connect != 7*time.Second {
+		t.Errorf("Timeouts = %+v", cfg.Timeouts)
+	}
+	if !cfg.DetectRefusals || cfg.FallbackOnBlocked {
+		t.Errorf("block policy = %v/%v", cfg.DetectRefusals, cfg.FallbackOnBlocked)
+	}
+}
+
+func TestTranslationOptionsFromSettings(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.PipelineTranslation = true
+	settings.AutoContinue = true
+	settings.MaxContinuations = 3
+	settings.ShowReasoning = true
+	settings.Timeouts.OCRSeconds = 12
+	temperature := 0.3
+	settings.Sampling.Translate.Temperature = &temperature
+	settings.Image.Enhance.AutoContrast = true
+
+	options := TranslationOptions(settings)
+	if !options.PipelineTranslation || !options.AutoContinue || options.MaxContinuations != 3 || !options.ShowReasoning {
+		t.Errorf("flags not forwarded: %+v", options)
+	}
+	if options.Deadlines.OCR != 12*time.Second {
+		t.Errorf("Deadlines.OCR = %v", options.Deadlines.OCR)
+	}
+	if options.TranslateSampling.Temperature == nil || *options.TranslateSampling.Temperature != temperature {
+		t.Errorf("TranslateSampling = %+v", options.TranslateSampling)
+	}
+	if !options.Image.Enhance.AutoContrast {
+		t.Errorf("Image.Enhance = %+v", options.Image.Enhance)
+	}
+}
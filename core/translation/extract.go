@@ -0,0 +1,225 @@
+package translation
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ExtractResult 为文字提取阶段的结构化输出，对应提取提示词要求的 {"background", "words"} JSON
+type ExtractResult struct {
+	// Background 为图像的场景描述，仅作为翻译语境
+	Background string `json:"background"`
+	// Words 为识别到的原文
+	Words string `json:"words"`
+	// Raw 为模型的原始输出，便于排查
+	Raw string `json:"-"`
+	// Structured 表示成功解析出 JSON；为 false 时 Words 为去掉代码块标记后的原始输出
+	Structured bool `json:"-"`
+}
+
+// TranslateInput 返回发送给翻译模型的内容：解析成功时为规范化的 JSON，否则为原始输出
+func (r ExtractResult) TranslateInput() string {
+	if !r.Structured {
+		return r.Words
+	}
+	data, err := json.MarshalIndent(struct {
+		Background string `json:"background"`
+		Words      string `json:"words"`
+	}{r.Background, r.Words}, "", "  ")
+	if err != nil {
+		return r.Raw
+	}
+	return string(data)
+}
+
+// ParseExtractResult 解析提取阶段的输出，可容忍 Markdown 代码块、JSON 前后的说明文字，
+// 以及字符串内的裸换行、多余逗号、未闭合的引号与括号等常见格式问题。
+// 无法解析时将整段输出视为原文。
+func ParseExtractResult(raw string) ExtractResult {
+	result := ExtractResult{Raw: raw}
+	text := stripCodeFence(strings.TrimSpace(raw))
+
+	if object, ok := findJSONObject(text); ok {
+		if parsed, ok := decodeExtract(object); ok {
+			parsed.Raw = raw
+			return parsed
+		}
+		if parsed, ok := decodeExtract(repairJSON(object)); ok {
+			parsed.Raw = raw
+			return parsed
+		}
+	}
+
+	result.Words = text
+	return result
+}
+
+// decodeExtract 解码提取结果，words 为数组时按行拼接，缺少 words 字段视为失败
+func decodeExtract(text string) (ExtractResult, bool) {
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(text), &payload); err != nil {
+		return ExtractResult{}, false
+	}
+	words, ok := payload["words"]
+	if !ok {
+		return ExtractResult{}, false
+	}
+	return ExtractResult{
+		Background: strings.TrimSpace(jsonText(payload["background"])),
+		Words:      strings.TrimSpace(jsonText(words)),
+		Structured: true,
+	}, true
+}
+
+// jsonText 将字符串、字符串数组或其他 JSON 值转换为文本
+func jsonText(value json.RawMessage) string {
+	if len(value) == 0 {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(value, &text); err == nil {
+		return text
+	}
+	var items []interface{}
+	if err := json.Unmarshal(value, &items); err == nil {
+		lines := make([]string, 0, len(items))
+		for _, item := range items {
+			if line, ok := item.(string); ok {
+				lines = append(lines, line)
+				continue
+			}
+			encoded, _ := json.Marshal(item)
+			lines = append(lines, string(encoded))
+		}
+		return strings.Join(lines, "\n")
+	}
+	if string(value) == "null" {
+		return ""
+	}
+	return string(value)
+}
+
+// stripCodeFence 去掉包裹整段输出的 ``` 或 ```json 代码块标记
+func stripCodeFence(text string) string {
+	start := strings.Index(text, "```")
+	if start < 0 {
+		return text
+	}
+	body := text[start+3:]
+	if newline := strings.IndexByte(body, '\n'); newline >= 0 && !strings.ContainsAny(body[:newline], "{}\"") {
+		body = body[newline+1:]
+	}
+	if end := strings.LastIndex(body, "```"); end >= 0 {
+		body = body[:end]
+	}
+	return strings.TrimSpace(body)
+}
+
+// findJSONObject 返回从首个 { 开始、括号配平的 JSON 对象；没有闭合时返回到文本末尾的内容，交由修复步骤补全
+func findJSONObject(text string) (string, bool) {
+	return findJSONValue(text, '{', '}')
+}
+
+// findJSONArray 与 findJSONObject 相同，但查找 JSON 数组
+func findJSONArray(text string) (string, bool) {
+	return findJSONValue(text, '[', ']')
+}
+
+func findJSONValue(text string, open, close byte) (string, bool) {
+	start := strings.IndexByte(text, open)
+	if start < 0 {
+		return "", false
+	}
+	depth := 0
+	inString, escaped := false, false
+	for i := start; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == open:
+			depth++
+		case c == close:
+			depth--
+			if depth == 0 {
+				return text[start : i+1], true
+			}
+		}
+	}
+	return text[start:], true
+}
+
+// repairJSON 修复模型输出中常见的 JSON 问题：转义字符串内的裸换行与制表符，
+// 删除对象或数组末尾多余的逗号，并补齐未闭合的字符串与括号
+func repairJSON(text string) string {
+	var builder strings.Builder
+	var closers []byte
+	inString, escaped := false, false
+
+	trimTrailingComma := func() {
+		current := strings.TrimRight(builder.String(), " \t\r\n")
+		if strings.HasSuffix(current, ",") {
+			current = current[:len(current)-1]
+			builder.Reset()
+			builder.WriteString(current)
+		}
+	}
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+				builder.WriteByte(c)
+			case c == '\\':
+				escaped = true
+				builder.WriteByte(c)
+			case c == '"':
+				inString = false
+				builder.WriteByte(c)
+			case c == '\n':
+				builder.WriteString(`\n`)
+			case c == '\r':
+				builder.WriteString(`\r`)
+			case c == '\t':
+				builder.WriteString(`\t`)
+			default:
+				builder.WriteByte(c)
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			closers = append(closers, '}')
+		case '[':
+			closers = append(closers, ']')
+		case '}', ']':
+			trimTrailingComma()
+			if len(closers) > 0 {
+				closers = closers[:len(closers)-1]
+			}
+		}
+		builder.WriteByte(c)
+	}
+
+	if escaped {
+		builder.WriteByte('\\')
+	}
+	if inString {
+		builder.WriteByte('"')
+	}
+	for i := len(closers) - 1; i >= 0; i-- {
+		trimTrailingComma()
+		builder.WriteByte(closers[i])
+	}
+	return builder.String()
+}
@@ -0,0 +1,121 @@
+package translation
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFindJSONValue(t *testing.T) {
+	cases := []struct {
+		name   string
+		text   string
+		open   byte
+		close  byte
+		want   string
+		wantOK bool
+	}{
+		{"object with prose around it", `Here you go: {"words":"a"} hope it helps`, '{', '}', `{"words":"a"}`, true},
+		{"nested object", `{"a":{"b":1},"c":2} tail`, '{', '}', `{"a":{"b":1},"c":2}`, true},
+		{"braces inside strings ignored", `{"words":"}{ and \"quoted\" }"} x`, '{', '}', `{"words":"}{ and \"quoted\" }"}`, true},
+		{"unterminated returns rest", `note {"words":"abc`, '{', '}', `{"words":"abc`, true},
+		{"no object", `plain text`, '{', '}', "", false},
+		{"array", `result: ["a",["b"]] done`, '[', ']', `["a",["b"]]`, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := findJSONValue(tc.text, tc.open, tc.close)
+			if got != tc.want || ok != tc.wantOK {
+				t.Fatalf("findJSONValue(%q) = %q, %v, want %q, %v", tc.text, got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestRepairJSON(t *testing.T) {
+	cases := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"valid input unchanged", `{"words":"a","background":"b"}`, `{"words":"a","background":"b"}`},
+		{"raw newline and tab in string", "{\"words\":\"line1\nline2\tend\"}", `{"words":"line1\nline2\tend"}`},
+		{"carriage return in string", "{\"words\":\"a\r\nb\"}", `{"words":"a\r\nb"}`},
+		{"trailing comma in object", `{"words":"a",}`, `{"words":"a"}`},
+		{"trailing comma with whitespace in array", "{\"words\":[\"a\",\"b\",\n]}", "{\"words\":[\"a\",\"b\"]}"},
+		{"unterminated string and object", `{"words":"abc`, `{"words":"abc"}`},
+		{"unterminated nested array", `{"words":["a","b"`, `{"words":["a","b"]}`},
+		{"dangling comma before missing closer", `{"words":"a",`, `{"words":"a"}`},
+		{"trailing backslash in string", `{"words":"a\`, `{"words":"a\\"}`},
+		{"comma inside string kept", `{"words":"a,}"}`, `{"words":"a,}"}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := repairJSON(tc.text)
+			if got != tc.want {
+				t.Fatalf("repairJSON(%q) = %q, want %q", tc.text, got, tc.want)
+			}
+			if !json.Valid([]byte(got)) {
+				t.Fatalf("repairJSON(%q) = %q is not valid JSON", tc.text, got)
+			}
+		})
+	}
+}
+
+func TestParseExtractResult(t *testing.T) {
+	cases := []struct {
+		name           string
+		raw            string
+		wantWords      string
+		wantBackground string
+		wantStructured bool
+	}{
+		{
+			name:           "plain JSON",
+			raw:            `{"background":"菜单","words":"Coffee"}`,
+			wantWords:      "Coffee",
+			wantBackground: "菜单",
+			wantStructured: true,
+		},
+		{
+			name:           "code fence with language",
+			raw:            "```json\n{\"background\":\"路牌\",\"words\":\"Exit\"}\n```",
+			wantWords:      "Exit",
+			wantBackground: "路牌",
+			wantStructured: true,
+		},
+		{
+			name:           "words array joined by lines",
+			raw:            `{"words":["Line 1","Line 2"]}`,
+			wantWords:      "Line 1\nLine 2",
+			wantStructured: true,
+		},
+		{
+			name:           "repaired truncated output",
+			raw:            "说明文字 {\"background\":\"对话\",\"words\":\"Hello\nWorld",
+			wantWords:      "Hello\nWorld",
+			wantBackground: "对话",
+			wantStructured: true,
+		},
+		{
+			name:      "missing words falls back to raw text",
+			raw:       `{"background":"x"}`,
+			wantWords: `{"background":"x"}`,
+		},
+		{
+			name:      "plain text",
+			raw:       "  Just text  ",
+			wantWords: "Just text",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ParseExtractResult(tc.raw)
+			if got.Words != tc.wantWords || got.Background != tc.wantBackground || got.Structured != tc.wantStructured {
+				t.Fatalf("ParseExtractResult(%q) = %+v", tc.raw, got)
+			}
+			if got.Raw != tc.raw {
+				t.Fatalf("Raw = %q, want %q", got.Raw, tc.raw)
+			}
+		})
+	}
+}
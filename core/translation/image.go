@@ -0,0 +1,42 @@
+package translation
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"time"
+
+	"Translater/core/imageproc"
+)
+
+// TranslateImage 对导入的图片（文件、拖放或剪贴板）执行与截图相同的预处理、提取与翻译。
+// 支持 PNG、JPEG、GIF、BMP 与 WebP，mimeType 为空时按内容识别格式。
+// 结果的 Bounds 为图片自身的像素范围，不对应屏幕位置。
+func (s *ServiceImpl) TranslateImage(ctx context.Context, data []byte, mimeType string) (*ScreenshotTranslationResult, error) {
+	if s.AIClient == nil {
+		return nil, fmt.Errorf("AI client 未初始化")
+	}
+
+	started := time.Now()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	img, err := imageproc.Decode(data, mimeType)
+	if err != nil {
+		return nil, fmt.Errorf("读取图片失败: %w", err)
+	}
+	return s.translateCaptured(ctx, img, imageBounds(img), started)
+}
+
+// imageBounds 返回以图片左上角为原点的范围，缩放比例固定为 1
+func imageBounds(img image.Image) ScreenshotBounds {
+	size := img.Bounds().Size()
+	return ScreenshotBounds{
+		EndX:   size.X,
+		EndY:   size.Y,
+		Width:  size.X,
+		Height: size.Y,
+		Scale:  1,
+	}
+}
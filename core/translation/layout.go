@@ -0,0 +1,270 @@
+package translation
+
+import (
+	"encoding/json"
+	"image"
+	"math"
+	"strings"
+)
+
+// layoutScale 为提示词约定的归一化坐标范围，模型返回 0-1 的小数时按比例直接使用
+const layoutScale = 1000
+
+// BlockBox 为文本块相对于截图区域的归一化外接矩形（0-1），原点在左上角
+type BlockBox struct {
+	X0 float64 `json:"x0"`
+	Y0 float64 `json:"y0"`
+	X1 float64 `json:"x1"`
+	Y1 float64 `json:"y1"`
+}
+
+// BlockBounds 为文本块在屏幕上的位置
+type BlockBounds struct {
+	Left   int `json:"left"`
+	Top    int `json:"top"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// TranslatedBlock 为版面分块模式下一个文本块的原文、译文与位置
+type TranslatedBlock struct {
+	Source string   `json:"source"`
+	Target string   `json:"target"`
+	Box    BlockBox `json:"box"`
+	// Screen 由 Box 与截图范围换算得到，不写入缓存
+	Screen BlockBounds `json:"-"`
+}
+
+// LayoutBlock 为版面分块提取结果中的一个文本块，Box 相对于上传给模型的图像
+type LayoutBlock struct {
+	Text string
+	// Translation 仅在视觉直出模式下由模型一并返回
+	Translation string
+	Box         BlockBox
+}
+
+// LayoutResult 为版面分块提取的结构化输出，对应 {"background", "blocks"} JSON
+type LayoutResult struct {
+	Background string
+	Blocks     []LayoutBlock
+	Raw        string
+}
+
+// ParseLayoutResult 解析版面分块模式的输出，容错规则与 ParseExtractResult 相同。
+// 缺少坐标或文字为空的块会被跳过，没有可用的块时返回 false。
+func ParseLayoutResult(raw string) (LayoutResult, bool) {
+	text := stripCodeFence(strings.TrimSpace(raw))
+	object, ok := findJSONObject(text)
+	if !ok {
+		return LayoutResult{}, false
+	}
+	result, ok := decodeLayout(object)
+	if !ok {
+		result, ok = decodeLayout(repairJSON(object))
+	}
+	if !ok {
+		return LayoutResult{}, false
+	}
+	result.Raw = raw
+	return result, true
+}
+
+func decodeLayout(text string) (LayoutResult, bool) {
+	var payload struct {
+		Background json.RawMessage `json:"background"`
+		Blocks     []struct {
+			Text        json.RawMessage `json:"text"`
+			Translation json.RawMessage `json:"translation"`
+			Box         json.RawMessage `json:"box"`
+		} `json:"blocks"`
+	}
+	if err := json.Unmarshal([]byte(text), &payload); err != nil {
+		return LayoutResult{}, false
+	}
+
+	result := LayoutResult{Background: strings.TrimSpace(jsonText(payload.Background))}
+	for _, item := range payload.Blocks {
+		content := strings.TrimSpace(jsonText(item.Text))
+		box, ok := parseBlockBox(item.Box)
+		if content == "" || !ok {
+			continue
+		}
+		result.Blocks = append(result.Blocks, LayoutBlock{
+			Text:        content,
+			Translation: strings.TrimSpace(jsonText(item.Translation)),
+			Box:         box,
+		})
+	}
+	return result, len(result.Blocks) > 0
+}
+
+// parseBlockBox 解析 [x0, y0, x1, y1] 或 {"x0", "y0", "x1", "y1"} 形式的坐标。
+// 全部不超过 1 时视为 0-1 的小数，否则按 0-1000 归一化；坐标会被排序并限制在图像范围内。
+func parseBlockBox(value json.RawMessage) (BlockBox, bool) {
+	var coords []float64
+	if err := json.Unmarshal(value, &coords); err != nil {
+		var object struct {
+			X0 *float64 `json:"x0"`
+			Y0 *float64 `json:"y0"`
+			X1 *float64 `json:"x1"`
+			Y1 *float64 `json:"y1"`
+		}
+		if err := json.Unmarshal(value, &object); err != nil || object.X0 == nil || object.Y0 == nil || object.X1 == nil || object.Y1 == nil {
+			return BlockBox{}, false
+		}
+		coords = []float64{*object.X0, *object.Y0, *object.X1, *object.Y1}
+	}
+	if len(coords) != 4 {
+		return BlockBox{}, false
+	}
+
+	scale := 1.0
+	for _, value := range coords {
+		if value > 1 {
+			scale = layoutScale
+			break
+		}
+	}
+	for i := range coords {
+		coords[i] = math.Min(math.Max(coords[i]/scale, 0), 1)
+	}
+	box := BlockBox{
+		X0: math.Min(coords[0], coords[2]),
+		Y0: math.Min(coords[1], coords[3]),
+		X1: math.Max(coords[0], coords[2]),
+		Y1: math.Max(coords[1], coords[3]),
+	}
+	if box.X1 <= box.X0 || box.Y1 <= box.Y0 {
+		return BlockBox{}, false
+	}
+	return box, true
+}
+
+// ExtractResult 将分块结果转换为普通提取结果，原文按块顺序逐行拼接
+func (r LayoutResult) ExtractResult() ExtractResult {
+	return ExtractResult{
+		Background: r.Background,
+		Words:      strings.Join(r.texts(), "\n"),
+		Raw:        r.Raw,
+		Structured: true,
+	}
+}
+
+// TranslateInput 返回发送给翻译模型的 {"background", "blocks"} JSON，blocks 为各块原文
+func (r LayoutResult) TranslateInput() string {
+	data, err := json.MarshalIndent(struct {
+		Background string   `json:"background"`
+		Blocks     []string `json:"blocks"`
+	}{r.Background, r.texts()}, "", "  ")
+	if err != nil {
+		return strings.Join(r.texts(), "\n")
+	}
+	return string(data)
+}
+
+func (r LayoutResult) texts() []string {
+	texts := make([]string, len(r.Blocks))
+	for i, block := range r.Blocks {
+		texts[i] = block.Text
+	}
+	return texts
+}
+
+// ParseBlockTranslations 解析翻译模型返回的译文数组。数组元素可以是字符串或带 text/translation 字段的对象；
+// 数量与原文块不一致时无法确定对应关系，返回已解析的译文与 false。
+func ParseBlockTranslations(raw string, count int) ([]string, bool) {
+	text := stripCodeFence(strings.TrimSpace(raw))
+	array, ok := findJSONArray(text)
+	if !ok {
+		return nil, false
+	}
+	targets, ok := decodeTranslations(array)
+	if !ok {
+		targets, ok = decodeTranslations(repairJSON(array))
+	}
+	if !ok {
+		return nil, false
+	}
+	return targets, len(targets) == count
+}
+
+func decodeTranslations(text string) ([]string, bool) {
+	var items []json.RawMessage
+	if err := json.Unmarshal([]byte(text), &items); err != nil {
+		return nil, false
+	}
+	targets := make([]string, len(items))
+	for i, item := range items {
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(item, &object); err == nil {
+			if value, ok := object["translation"]; ok {
+				item = value
+			} else if value, ok := object["text"]; ok {
+				item = value
+			}
+		}
+		targets[i] = strings.TrimSpace(jsonText(item))
+	}
+	return targets, true
+}
+
+// newTranslatedBlocks 组合原文块与译文，并将坐标从上传图像换算为相对于截图区域的比例。
+// crop 为上传图像对应的截图区域（预处理裁边后的范围），size 为截图的像素尺寸。
+func newTranslatedBlocks(layout LayoutResult, targets []string, crop image.Rectangle, size image.Point) []TranslatedBlock {
+	blocks := make([]TranslatedBlock, 0, len(layout.Blocks))
+	for i, block := range layout.Blocks {
+		target := block.Translation
+		if i < len(targets) {
+			target = targets[i]
+		}
+		blocks = append(blocks, TranslatedBlock{
+			Source: block.Text,
+			Target: target,
+			Box:    block.Box.within(crop, size),
+		})
+	}
+	return blocks
+}
+
+// within 将相对于 crop 的比例坐标换算为相对于整张截图（尺寸为 size）的比例坐标
+func (b BlockBox) within(crop image.Rectangle, size image.Point) BlockBox {
+	if size.X <= 0 || size.Y <= 0 || crop.Empty() {
+		return b
+	}
+	width, height := float64(size.X), float64(size.Y)
+	mapX := func(value float64) float64 {
+		return (float64(crop.Min.X) + value*float64(crop.Dx())) / width
+	}
+	mapY := func(value float64) float64 {
+		return (float64(crop.Min.Y) + value*float64(crop.Dy())) / height
+	}
+	return BlockBox{X0: mapX(b.X0), Y0: mapY(b.Y0), X1: mapX(b.X1), Y1: mapY(b.Y1)}
+}
+
+// placeBlocks 按截图范围计算每个文本块的屏幕坐标
+func placeBlocks(blocks []TranslatedBlock, bounds ScreenshotBounds) []TranslatedBlock {
+	placed := make([]TranslatedBlock, len(blocks))
+	for i, block := range blocks {
+		left := bounds.Left + int(math.Round(block.Box.X0*float64(bounds.Width)))
+		top := bounds.Top + int(math.Round(block.Box.Y0*float64(bounds.Height)))
+		right := bounds.Left + int(math.Round(block.Box.X1*float64(bounds.Width)))
+		bottom := bounds.Top + int(math.Round(block.Box.Y1*float64(bounds.Height)))
+		block.Screen = BlockBounds{
+			Left:   left,
+			Top:    top,
+			Width:  max(right-left, 1),
+			Height: max(bottom-top, 1),
+		}
+		placed[i] = block
+	}
+	return placed
+}
+
+// blockTargets 返回按块顺序逐行拼接的译文
+func blockTargets(blocks []TranslatedBlock) string {
+	targets := make([]string, len(blocks))
+	for i, block := range blocks {
+		targets[i] = block.Target
+	}
+	return strings.Join(targets, "\n")
+}
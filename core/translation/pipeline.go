@@ -0,0 +1,527 @@
+package translation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"Translater/core/ai"
+)
+
+const (
+	// minSegmentRunes 为按单个换行或句末标点切分时片段的最少字符数，过短的句子与后文合并翻译，减少请求次数并保留上下文
+	minSegmentRunes = 60
+	// segmentQueueSize 为等待翻译的片段队列长度，队列已满时提取阶段的流式读取会等待
+	segmentQueueSize = 32
+)
+
+// segment 为可以单独翻译的一段原文，sep 为其后的空白分隔符，拼接译文时原样保留
+type segment struct {
+	text string
+	sep  string
+	// background 与 structured 为切分时的提取结果，用于构造与整段翻译一致的输入
+	background string
+	structured bool
+}
+
+// translateInput 返回该片段发送给翻译模型的内容
+func (g segment) translateInput() string {
+	return ExtractResult{Background: g.background, Words: g.text, Structured: g.structured}.TranslateInput()
+}
+
+// segmenter 从逐步增长的原文中切出完整的段落或句子
+type segmenter struct {
+	// consumed 为已切出的字节数（含分隔符）
+	consumed int
+}
+
+// next 返回 words 中下一段完整的片段。words 必须是上次调用时原文的延续；
+// final 为 true 表示原文已完整，剩余内容整体作为最后一段。
+func (g *segmenter) next(words string, final bool) (segment, bool) {
+	if g.consumed > len(words) {
+		return segment{}, false
+	}
+	pending := words[g.consumed:]
+	// 开头的空白只会出现在原文起始处，此后的空白已作为上一段的分隔符切出
+	trimmed := strings.TrimLeftFunc(pending, unicode.IsSpace)
+	g.consumed += len(pending) - len(trimmed)
+	pending = trimmed
+	if pending == "" {
+		return segment{}, false
+	}
+
+	end, sepEnd := segmentBoundary(pending)
+	if end < 0 {
+		if !final {
+			return segment{}, false
+		}
+		g.consumed += len(pending)
+		return segment{text: strings.TrimRightFunc(pending, unicode.IsSpace)}, true
+	}
+	g.consumed += sepEnd
+	return segment{text: pending[:end], sep: pending[end:sepEnd]}, true
+}
+
+// segmentBoundary 查找 text 中第一个完整的段落或句子边界，返回片段结尾与分隔符结尾的字节位置。
+// 空行总是切分；单个换行或句末标点仅在片段达到 minSegmentRunes 时切分。
+// 边界之后的空白必须已经结束（后面出现了非空白字符），否则分隔符可能还未收全，返回 -1。
+func segmentBoundary(text string) (int, int) {
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case r == '\n':
+			sepEnd := skipSpace(text, i)
+			if sepEnd == len(text) {
+				return -1, -1
+			}
+			if strings.Count(text[i:sepEnd], "\n") >= 2 || utf8.RuneCountInString(text[:i]) >= minSegmentRunes {
+				return i, sepEnd
+			}
+			i = sepEnd
+			continue
+		case isSentenceEnd(r):
+			end := skipClosers(text, i+size)
+			if end == len(text) {
+				return -1, -1
+			}
+			sepEnd := skipSpace(text, end)
+			if sepEnd == len(text) {
+				return -1, -1
+			}
+			// 西文句号后必须有空白，避免在小数、缩写与网址中切分
+			fullWidth := r >= 0x3000
+			if (fullWidth || sepEnd > end) && utf8.RuneCountInString(text[:end]) >= minSegmentRunes {
+				return end, sepEnd
+			}
+			i = end
+			continue
+		}
+		i += size
+	}
+	return -1, -1
+}
+
+func isSentenceEnd(r rune) bool {
+	switch r {
+	case '。', '！', '？', '；', '.', '!', '?':
+		return true
+	}
+	return false
+}
+
+// skipClosers 跳过句末标点后的右引号与右括号
+func skipClosers(text string, i int) int {
+	for i < len(text) {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !strings.ContainsRune(`"')]}”’」』）》`, r) {
+			break
+		}
+		i += size
+	}
+	return i
+}
+
+func skipSpace(text string, i int) int {
+	for i < len(text) {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		i += size
+	}
+	return i
+}
+
+// streamedWords 从尚未完整的提取输出中取出已收到的原文。输出为 {"background", "words"} JSON 时
+// 返回 words 字段已解码的部分（words 为数组时按行拼接），否则将去掉代码块标记的输出视为原文。
+func streamedWords(raw string) (background, words string, structured bool) {
+	text := strings.TrimLeftFunc(raw, unicode.IsSpace)
+	if strings.HasPrefix(text, "```") {
+		newline := strings.IndexByte(text, '\n')
+		if newline < 0 {
+			return "", "", false
+		}
+		text = strings.TrimLeftFunc(text[newline+1:], unicode.IsSpace)
+	} else if strings.HasPrefix("```", text) {
+		// 可能是代码块标记的开头，等待更多内容
+		return "", "", false
+	}
+	if text == "" {
+		return "", "", false
+	}
+	if text[0] != '{' {
+		if fence := strings.Index(text, "```"); fence >= 0 {
+			text = text[:fence]
+		}
+		return "", text, false
+	}
+
+	if value, ok := jsonValueStart(text, "background"); ok && value[0] == '"' {
+		background, _ = decodePartialString(value[1:])
+	}
+	value, ok := jsonValueStart(text, "words")
+	if !ok {
+		return background, "", true
+	}
+	if value[0] == '"' {
+		words, _ = decodePartialString(value[1:])
+		return background, words, true
+	}
+	if value[0] == '[' {
+		var lines []string
+		rest := value[1:]
+		for {
+			rest = strings.TrimLeft(rest, " \t\r\n,")
+			if rest == "" || rest[0] != '"' {
+				break
+			}
+			line, consumed := decodePartialString(rest[1:])
+			lines = append(lines, line)
+			if consumed < 0 {
+				break
+			}
+			rest = rest[1+consumed:]
+		}
+		return background, strings.Join(lines, "\n"), true
+	}
+	return background, "", true
+}
+
+// jsonValueStart 返回 text 中键 key 之后的值（从值的首个字符开始），值尚未出现时返回 false
+func jsonValueStart(text, key string) (string, bool) {
+	token := `"` + key + `"`
+	for offset := 0; ; {
+		index := strings.Index(text[offset:], token)
+		if index < 0 {
+			return "", false
+		}
+		index += offset
+		offset = index + len(token)
+		// 字符串内的引号必须转义，未转义的 "key" 后接冒号只可能是键
+		if index > 0 && text[index-1] == '\\' {
+			continue
+		}
+		rest := strings.TrimLeft(text[offset:], " \t\r\n")
+		if !strings.HasPrefix(rest, ":") {
+			continue
+		}
+		rest = strings.TrimLeft(rest[1:], " \t\r\n")
+		if rest == "" {
+			return "", false
+		}
+		return rest, true
+	}
+}
+
+// decodePartialString 解码开引号之后的 JSON 字符串内容，遇到闭合引号时返回已消耗的字节数（含引号），
+// 字符串尚未结束时返回 -1；末尾不完整的转义序列会被忽略，等待后续内容
+func decodePartialString(text string) (string, int) {
+	var builder strings.Builder
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '"':
+			return builder.String(), i + 1
+		case c != '\\':
+			builder.WriteByte(c)
+			i++
+			continue
+		}
+		if i+1 >= len(text) {
+			break
+		}
+		switch text[i+1] {
+		case 'n':
+			builder.WriteByte('\n')
+		case 't':
+			builder.WriteByte('\t')
+		case 'r':
+			builder.WriteByte('\r')
+		case 'b':
+			builder.WriteByte('\b')
+		case 'f':
+			builder.WriteByte('\f')
+		case 'u':
+			r, size, ok := decodeUnicodeEscape(text[i:])
+			if !ok {
+				return builder.String(), -1
+			}
+			builder.WriteRune(r)
+			i += size
+			continue
+		default:
+			builder.WriteByte(text[i+1])
+		}
+		i += 2
+	}
+	return builder.String(), -1
+}
+
+// decodeUnicodeEscape 解码 \uXXXX（含代理对），内容不完整时返回 false
+func decodeUnicodeEscape(text string) (rune, int, bool) {
+	parse := func(s string) (rune, bool) {
+		if len(s) < 6 {
+			return 0, false
+		}
+		var value rune
+		for _, c := range s[2:6] {
+			digit := strings.IndexRune("0123456789abcdef", unicode.ToLower(c))
+			if digit < 0 {
+				return utf8.RuneError, true
+			}
+			value = value<<4 | rune(digit)
+		}
+		return value, true
+	}
+	high, ok := parse(text)
+	if !ok {
+		return 0, 0, false
+	}
+	if !utf16.IsSurrogate(high) {
+		return high, 6, true
+	}
+	if len(text) < 12 {
+		return 0, 0, false
+	}
+	if text[6] != '\\' || text[7] != 'u' {
+		return utf8.RuneError, 6, true
+	}
+	low, _ := parse(text[6:])
+	return utf16.DecodeRune(high, low), 12, true
+}
+
+// pipelineOutput 为逐段翻译的汇总结果
+type pipelineOutput struct {
+	text          string
+	reasoning     string
+	continuations int
+	truncated     bool
+	servedBy      *ai.ServedBy
+	err           error
+}
+
+// translateSegments 按顺序逐段流式翻译 segments 中的片段，直到通道关闭。
+// 各段的增量按顺序推送给 onEvent，Length 在段间累计；reset 为 true 时首个事件带 Reset，覆盖此前推送的内容。
+// 翻译阶段的时长预算从收到第一段时开始计算；每段翻译前检查预算硬上限。出错后不再翻译后续片段，但仍会读完通道。
+func (s *ServiceImpl) translateSegments(ctx context.Context, segments <-chan segment, prompt string, onEvent ai.StreamHandler, reset bool, partial func() string) pipelineOutput {
+	var output pipelineOutput
+	var translated, emitted strings.Builder
+	var reasoning []string
+
+	var translateCtx context.Context
+	cancelTranslate := context.CancelFunc(func() {})
+	defer func() { cancelTranslate() }()
+
+	emit := func(event ai.StreamEvent) {
+		if reset {
+			event.Reset = true
+			reset = false
+		}
+		onEvent(event)
+	}
+
+	for seg := range segments {
+		if output.err != nil || strings.TrimSpace(seg.text) == "" {
+			continue
+		}
+		if err := s.budgetExceeded(); err != nil {
+			output.err = err
+			continue
+		}
+		if translateCtx == nil {
+			translateCtx, cancelTranslate = withStageDeadline(ctx, ai.StageTranslate, s.options.Deadlines.Translate)
+		}
+
+		// 本段的增量接在已推送内容之后；本段输出被作废时连同之前各段一起重新推送
+		prefix := emitted.String()
+		offset := utf8.RuneCountInString(prefix)
+		var streamed strings.Builder
+		handler := func(event ai.StreamEvent) {
+			if event.Reset {
+				streamed.Reset()
+			}
+			streamed.WriteString(event.Delta)
+			event.Length += offset
+			if event.Reset {
+				event.Delta = prefix + event.Delta
+			}
+			emit(event)
+		}
+
+		response, err := s.AIClient.TranslateStreamWithContext(translateCtx, seg.translateInput(), prompt, handler, ai.WithSampling(s.options.TranslateSampling))
+		if err == nil && len(response.Choices) == 0 {
+			err = fmt.Errorf("翻译结果为空")
+		}
+		var text string
+		if err == nil {
+			text, err = messageContentToString(response.Choices[0].Message.Content)
+		}
+		var piece continued
+		if err == nil {
+			piece, err = s.continueTruncated(translateCtx, response, text, handler)
+		}
+		if err != nil {
+			output.err = stageError(ctx, translateCtx, err, partial())
+			continue
+		}
+
+		translated.WriteString(strings.TrimSpace(piece.text))
+		translated.WriteString(seg.sep)
+		emitted.WriteString(streamed.String())
+		if seg.sep != "" {
+			emitted.WriteString(seg.sep)
+			emit(ai.StreamEvent{Delta: seg.sep, Length: utf8.RuneCountInString(emitted.String())})
+		}
+		if piece.reasoning != "" {
+			reasoning = append(reasoning, piece.reasoning)
+		}
+		output.continuations += piece.continuations
+		output.truncated = output.truncated || piece.truncated
+		output.servedBy = &response.ServedBy
+	}
+
+	output.text = strings.TrimSpace(translated.String())
+	output.reasoning = strings.Join(reasoning, "\n\n")
+	return output
+}
+
+// pipelineTranslate 流式提取文字，并在识别出完整的段落或句子后立即开始翻译，
+// 译文按原文顺序流式推送。提取阶段因备用接口重新生成而作废已翻译的内容时，
+// 改为在提取完成后重新逐段翻译全部原文，首个事件带 Reset。
+func (s *ServiceImpl) pipelineTranslate(ctx context.Context, result *ScreenshotTranslationResult, imageData []byte, mimeType string, onEvent ai.StreamHandler, partial func() string) error {
+	pipeCtx, cancelPipe := context.WithCancel(ctx)
+	defer cancelPipe()
+
+	start := func(reset bool) (chan<- segment, <-chan pipelineOutput) {
+		segments := make(chan segment, segmentQueueSize)
+		done := make(chan pipelineOutput, 1)
+		go func() {
+			output := s.translateSegments(pipeCtx, segments, result.TranslatePrompt, onEvent, reset, partial)
+			if output.err != nil {
+				// 翻译失败时停止提取
+				cancelPipe()
+			}
+			done <- output
+		}()
+		return segments, done
+	}
+
+	segments, done := start(false)
+	var raw strings.Builder
+	var cut segmenter
+	dispatched, abandoned := false, false
+	dispatch := func(final bool) {
+		background, words, structured := streamedWords(raw.String())
+		for {
+			seg, ok := cut.next(words, final)
+			if !ok {
+				return
+			}
+			seg.background, seg.structured = background, structured
+			select {
+			case segments <- seg:
+				dispatched = true
+			case <-pipeCtx.Done():
+				return
+			}
+		}
+	}
+	onExtract := func(event ai.StreamEvent) {
+		if pipeCtx.Err() != nil {
+			return
+		}
+		if event.Reset {
+			raw.Reset()
+			abandoned = abandoned || dispatched
+		}
+		raw.WriteString(event.Delta)
+		if !abandoned {
+			dispatch(false)
+		}
+	}
+
+	ocrCtx, cancelOCR := withStageDeadline(pipeCtx, ai.StageOCR, s.options.Deadlines.OCR)
+	defer cancelOCR()
+	extracted, err := s.streamExtract(ocrCtx, result.ExtractPrompt, imageData, mimeType, onExtract)
+	if err != nil {
+		close(segments)
+		if output := <-done; output.err != nil {
+			return fmt.Errorf("翻译失败: %w", output.err)
+		}
+		return fmt.Errorf("文字提取失败: %w", stageError(ctx, ocrCtx, err, ""))
+	}
+
+	extract := ParseExtractResult(extracted.output.text)
+	result.Extract = extract
+	result.ExtractedText = extract.Words
+	result.RawExtractedText = extract.Raw
+	result.ExtractReasoning = extracted.output.reasoning
+	result.Continuations = extracted.output.continuations
+	result.Truncated = extracted.output.truncated
+	result.VisionServedBy = extracted.servedBy
+
+	if abandoned {
+		close(segments)
+		<-done
+		segments, done = start(true)
+	}
+	if !dispatched || abandoned {
+		// 尚未切出任何片段时按解析后的原文切分，兼容流式解析不到 words 字段的输出
+		words := extract.Words
+		cut = segmenter{}
+		for {
+			seg, ok := cut.next(words, true)
+			if !ok {
+				break
+			}
+			seg.background, seg.structured = extract.Background, extract.Structured
+			segments <- seg
+		}
+	} else {
+		dispatch(true)
+	}
+	close(segments)
+
+	output := <-done
+	if output.err != nil {
+		return fmt.Errorf("翻译失败: %w", output.err)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	result.TranslatedText = output.text
+	result.Reasoning = output.reasoning
+	result.Continuations += output.continuations
+	result.Truncated = result.Truncated || output.truncated
+	result.TranslateServedBy = output.servedBy
+	return nil
+}
+
+// extracted 为流式提取阶段的输出
+type extracted struct {
+	output   continued
+	servedBy *ai.ServedBy
+}
+
+// streamExtract 流式调用视觉模型提取文字，并在输出被截断时自动续写
+func (s *ServiceImpl) streamExtract(ctx context.Context, prompt string, imageData []byte, mimeType string, onEvent ai.StreamHandler) (extracted, error) {
+	response, err := s.AIClient.ImageToWordsStreamWithContext(ctx, prompt, imageData, mimeType, "", onEvent, ai.WithSampling(s.options.ExtractSampling))
+	if err != nil {
+		return extracted{}, err
+	}
+	if len(response.Choices) == 0 {
+		return extracted{}, fmt.Errorf("文字提取结果为空")
+	}
+	text, err := messageContentToString(response.Choices[0].Message.Content)
+	if err != nil {
+		return extracted{}, fmt.Errorf("提取内容解析失败: %w", err)
+	}
+	output, err := s.continueTruncated(ctx, response, text, onEvent)
+	if err != nil {
+		return extracted{}, err
+	}
+	return extracted{output: output, servedBy: &response.ServedBy}, nil
+}
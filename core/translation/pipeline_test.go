@@ -0,0 +1,130 @@
+package translation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSegmentBoundary(t *testing.T) {
+	long := strings.Repeat("a", minSegmentRunes)
+	longCJK := strings.Repeat("字", minSegmentRunes)
+	cases := []struct {
+		name       string
+		text       string
+		wantEnd    int
+		wantSepEnd int
+	}{
+		{"blank line always splits", "第一段\n\n第二段", len("第一段"), len("第一段\n\n")},
+		{"blank line with indentation", "one\n  \n two", 3, len("one\n  \n ")},
+		{"short line is merged", "短句\n下一句", -1, -1},
+		{"separator not finished", "第一段\n\n", -1, -1},
+		{"long line splits on single newline", long + "\nnext", len(long), len(long) + 1},
+		{"long sentence splits after period and space", long + ". Next", len(long) + 1, len(long) + 2},
+		{"period without space is not a boundary", long + ".5 more", -1, -1},
+		{"short sentence is merged", "Hi. There", -1, -1},
+		{"full-width period needs no space", longCJK + "。下一句", len(longCJK + "。"), len(longCJK + "。")},
+		{"closing quote stays with sentence", longCJK + "。」下一句", len(longCJK + "。」"), len(longCJK + "。」")},
+		{"sentence end at text end waits", longCJK + "。", -1, -1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			end, sepEnd := segmentBoundary(tc.text)
+			if end != tc.wantEnd || sepEnd != tc.wantSepEnd {
+				t.Fatalf("segmentBoundary(%q) = %d, %d, want %d, %d", tc.text, end, sepEnd, tc.wantEnd, tc.wantSepEnd)
+			}
+		})
+	}
+}
+
+func TestSegmenterGrowingText(t *testing.T) {
+	var g segmenter
+	var got []segment
+	collect := func(words string, final bool) {
+		for {
+			seg, ok := g.next(words, final)
+			if !ok {
+				return
+			}
+			got = append(got, seg)
+		}
+	}
+
+	collect("  第一段", false)
+	collect("  第一段\n\n", false)
+	collect("  第一段\n\n第二", false)
+	collect("  第一段\n\n第二段\n\n第三段  ", false)
+	collect("  第一段\n\n第二段\n\n第三段  ", true)
+
+	want := []segment{
+		{text: "第一段", sep: "\n\n"},
+		{text: "第二段", sep: "\n\n"},
+		{text: "第三段"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("segments = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("segment %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestStreamedWords(t *testing.T) {
+	cases := []struct {
+		name           string
+		raw            string
+		wantBackground string
+		wantWords      string
+		wantStructured bool
+	}{
+		{"empty", "", "", "", false},
+		{"possible fence prefix waits", "``", "", "", false},
+		{"fence line not finished", "```js", "", "", false},
+		{"plain text", "Hello world", "", "Hello world", false},
+		{"plain text in fence", "```\nHello\n```", "", "Hello\n", false},
+		{"json before words", `{"background":"菜单`, "菜单", "", true},
+		{"partial words string", `{"background":"菜单","words":"Cof`, "菜单", "Cof", true},
+		{"escapes decoded", `{"words":"a\nb \"c\""}`, "", "a\nb \"c\"", true},
+		{"fenced json", "```json\n{\"words\":\"Exit", "", "Exit", true},
+		{"words array", `{"words":["one", "two", "thr`, "", "one\ntwo\nthr", true},
+		{"key text inside value is ignored", `{"background":"the \"words\": label","words":"x"}`, "the \"words\": label", "x", true},
+		{"words value not started", `{"words":`, "", "", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			background, words, structured := streamedWords(tc.raw)
+			if background != tc.wantBackground || words != tc.wantWords || structured != tc.wantStructured {
+				t.Fatalf("streamedWords(%q) = %q, %q, %v, want %q, %q, %v",
+					tc.raw, background, words, structured, tc.wantBackground, tc.wantWords, tc.wantStructured)
+			}
+		})
+	}
+}
+
+func TestDecodePartialString(t *testing.T) {
+	cases := []struct {
+		name         string
+		text         string
+		want         string
+		wantConsumed int
+	}{
+		{"closed string", `abc", "next"`, "abc", 4},
+		{"open string", `abc`, "abc", -1},
+		{"simple escapes", `a\n\t\r\\\/\"b"`, "a\n\t\r\\/\"b", 15},
+		{"unicode escape", `\u4e2d\u6587"`, "中文", 13},
+		{"surrogate pair", `\ud83d\ude00"`, "😀", 13},
+		{"incomplete unicode escape waits", `ab\u4e`, "ab", -1},
+		{"incomplete surrogate pair waits", `\ud83d\ude`, "", -1},
+		{"trailing backslash ignored", `ab\`, "ab", -1},
+		{"invalid hex becomes replacement", `\uzzzz"`, "\uFFFD", 7},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, consumed := decodePartialString(tc.text)
+			if got != tc.want || consumed != tc.wantConsumed {
+				t.Fatalf("decodePartialString(%q) = %q, %d, want %q, %d", tc.text, got, consumed, tc.want, tc.wantConsumed)
+			}
+		})
+	}
+}
@@ -2,13 +2,21 @@ package translation
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"image"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"Translater/core/ai"
+	"Translater/core/cache"
+	"Translater/core/display"
+	"Translater/core/imageproc"
 	"Translater/core/prompts"
 	"Translater/core/screenshot"
+	"Translater/core/usage"
 )
 
 // Service 翻译服务接口
@@ -17,24 +25,37 @@ type Service interface {
 	ProcessScreenshotWithContext(ctx context.Context, startX, startY, endX, endY int) bool
 	ProcessScreenshotDetailed(startX, startY, endX, endY int) (*ScreenshotTranslationResult, error)
 	ProcessScreenshotDetailedWithContext(ctx context.Context, startX, startY, endX, endY int) (*ScreenshotTranslationResult, error)
+	WatchRegion(ctx context.Context, startX, startY, endX, endY int, opts WatchOptions, handler WatchHandler) error
+	TranslateImage(ctx context.Context, data []byte, mimeType string) (*ScreenshotTranslationResult, error)
 	TranslateText(input string) (*TextTranslationResult, error)
 	TranslateTextWithContext(ctx context.Context, input string) (*TextTranslationResult, error)
 	UpdatePrompts(extract, translate string)
 	UpdateOptions(opts Options)
 	SetStreamHandler(handler StreamHandler)
+	SetUsageLedger(ledger *usage.Ledger)
+	SetResultCache(resultCache *cache.Cache)
+	SetBudgetWarningHandler(handler BudgetWarningHandler)
+	CheckBudget() ([]usage.BudgetWarning, error)
 }
 
 // ServiceImpl 翻译服务实现
 type ServiceImpl struct {
 	AIClient        *ai.Client
+	capturer        screenshot.Capturer
 	extractPrompt   string
 	translatePrompt string
 	options         Options
 	streamHandler   StreamHandler
+	usageLedger     *usage.Ledger
+	budgetHandler   BudgetWarningHandler
+	resultCache     *cache.Cache
 }
 
-// StreamHandler 用于接收翻译过程中的流式文本
-type StreamHandler func(stage string, content string)
+// StreamHandler 用于接收翻译过程中的流式增量，调用方需自行拼接 event.Delta
+type StreamHandler func(stage string, event ai.StreamEvent)
+
+// BudgetWarningHandler 用于接收达到软上限的预算提醒
+type BudgetWarningHandler func(warnings []usage.BudgetWarning)
 
 // Options 控制翻译服务行为
 type Options struct {
@@ -42,16 +63,62 @@ type Options struct {
 	UseVisionForTranslation bool
 	SourceLanguage          string
 	TargetLanguage          string
+	Budget                  usage.Budget
+	// 各阶段的采样参数，未设置的字段沿用客户端默认值
+	ExtractSampling   ai.Sampling
+	TranslateSampling ai.Sampling
+	DirectSampling    ai.Sampling
+	// Deadlines 为截图、提取与翻译阶段的总时长预算
+	Deadlines StageDeadlines
+	// AutoContinue 为 true 时，输出因 max_tokens 被截断会自动发送续写请求，最多 MaxContinuations 次
+	AutoContinue     bool
+	MaxContinuations int
+	// ShowReasoning 为 true 时保留推理模型的思考过程（流式事件与结果中的 Reasoning 字段），用于调试
+	ShowReasoning bool
+	// Image 为截图上传前的预处理选项（缩放、格式、灰度与字节预算）
+	Image imageproc.Options
+	// PipelineTranslation 为 true 且开启流式输出时，两段式模式会流式提取文字，
+	// 每识别出完整的段落或句子即开始翻译，译文按原文顺序流式推送
+	PipelineTranslation bool
+	// LayoutBlocks 为 true 时按文本块识别并翻译截图，结果中的 Blocks 带有每块的屏幕坐标；
+	// 此模式不使用自定义提取与翻译提示词，译文在整体完成后一次性推送
+	LayoutBlocks bool
+}
+
+// StageDeadlines 为各阶段的总时长预算，0 表示不限制。
+// 超时时返回 *ai.TimeoutError，其中 Partial 保存已收到的部分译文。
+type StageDeadlines struct {
+	Capture time.Duration
+	OCR     time.Duration
+	// Translate 同时用于文本翻译与视觉直出翻译
+	Translate time.Duration
 }
 
 // ScreenshotTranslationResult 包含一次截图翻译的详情
 type ScreenshotTranslationResult struct {
-	ExtractedText   string
-	TranslatedText  string
-	ExtractPrompt   string
-	TranslatePrompt string
-	ProcessingTime  time.Duration
-	Bounds          ScreenshotBounds
+	// ExtractedText 为识别到的原文（提取结果中的 words），RawExtractedText 为提取阶段的原始输出
+	ExtractedText    string
+	RawExtractedText string
+	// Extract 为解析后的提取结果，视觉直出模式下为零值
+	Extract        ExtractResult
+	TranslatedText string
+	// ExtractReasoning 与 Reasoning 分别为提取和翻译阶段的思考过程，仅在 ShowReasoning 开启时填充
+	ExtractReasoning string
+	Reasoning        string
+	ExtractPrompt    string
+	TranslatePrompt  string
+	ProcessingTime   time.Duration
+	Bounds           ScreenshotBounds
+	// CacheHit 表示结果来自缓存，未调用模型
+	CacheHit bool
+	// Continuations 为自动续写的次数，Truncated 表示最终输出仍因 max_tokens 被截断
+	Continuations int
+	Truncated     bool
+	// Blocks 为版面分块模式下逐块的原文与译文，未开启或模型未返回可用的分块时为空
+	Blocks []TranslatedBlock
+	// VisionServedBy 与 TranslateServedBy 记录各阶段实际应答的接口，未调用时为 nil
+	VisionServedBy    *ai.ServedBy
+	TranslateServedBy *ai.ServedBy
 }
 
 // ScreenshotBounds 描述一次截图对应的屏幕区域
@@ -64,6 +131,8 @@ type ScreenshotBounds struct {
 	Top    int `json:"top"`
 	Width  int `json:"width"`
 	Height int `json:"height"`
+	// Scale 为区域所在显示器的缩放比例，坐标均为物理像素，换算为逻辑像素时除以该值
+	Scale float64 `json:"scale"`
 }
 
 // TextTranslationResult 记录纯文本翻译的结果
@@ -71,13 +140,33 @@ type TextTranslationResult struct {
 	OriginalText    string
 	TranslatedText  string
 	TranslatePrompt string
-	ProcessingTime  time.Duration
+	// Reasoning 为思考过程，仅在 ShowReasoning 开启时填充
+	Reasoning      string
+	ProcessingTime time.Duration
+	// CacheHit 表示结果来自缓存，未调用模型
+	CacheHit bool
+	// Continuations 为自动续写的次数，Truncated 表示最终输出仍因 max_tokens 被截断
+	Continuations int
+	Truncated     bool
+	// ServedBy 记录实际应答的接口，命中缓存时为 nil
+	ServedBy *ai.ServedBy
 }
 
-// NewService 创建新的翻译服务
-func NewService(aiClient *ai.Client, extractPrompt, translatePrompt string, opts Options) Service {
+// cachedTranslation 是写入结果缓存的内容，ExtractedText 保存提取阶段的原始输出
+type cachedTranslation struct {
+	ExtractedText  string            `json:"extractedText,omitempty"`
+	TranslatedText string            `json:"translatedText"`
+	Blocks         []TranslatedBlock `json:"blocks,omitempty"`
+}
+
+// NewService 创建新的翻译服务，capturer 为 nil 时使用 screenshot.DefaultCapturer
+func NewService(aiClient *ai.Client, capturer screenshot.Capturer, extractPrompt, translatePrompt string, opts Options) Service {
+	if capturer == nil {
+		capturer = screenshot.DefaultCapturer
+	}
 	return &ServiceImpl{
 		AIClient:        aiClient,
+		capturer:        capturer,
 		extractPrompt:   normalisePrompt(extractPrompt, prompts.DefaultExtractPrompt),
 		translatePrompt: normalisePrompt(translatePrompt, prompts.DefaultTranslatePrompt),
 		options:         opts,
@@ -121,6 +210,7 @@ func newScreenshotBounds(startX, startY, endX, endY int) ScreenshotBounds {
 		Top:    top,
 		Width:  width,
 		Height: height,
+		Scale:  display.ScaleFor(image.Rect(left, top, left+width, top+height)),
 	}
 }
 
@@ -134,10 +224,25 @@ func (s *ServiceImpl) ProcessScreenshotWithContext(ctx context.Context, startX,
 	fmt.Println("开始处理截图...")
 
 	result, err := s.ProcessScreenshotDetailedWithContext(ctx, startX, startY, endX, endY)
+	var requestErr *ai.RequestError
+	var budgetErr *usage.BudgetExceededError
+	var timeoutErr *ai.TimeoutError
 	switch {
-	case err == context.Canceled:
+	case errors.Is(err, context.Canceled):
 		fmt.Println("截图处理已取消")
 		return false
+	case errors.As(err, &budgetErr):
+		fmt.Printf("截图处理已阻止: %s\n", budgetErr.UserMessage())
+		return false
+	case errors.As(err, &timeoutErr):
+		fmt.Printf("截图处理超时: %s\n", timeoutErr.UserMessage())
+		if timeoutErr.Partial != "" {
+			fmt.Printf("部分译文: %s\n", timeoutErr.Partial)
+		}
+		return false
+	case errors.As(err, &requestErr):
+		fmt.Printf("截图处理失败: %s（%v）\n", requestErr.UserMessage(), err)
+		return false
 	case err != nil:
 		fmt.Printf("截图处理失败: %v\n", err)
 		return false
@@ -172,10 +277,23 @@ func (s *ServiceImpl) ProcessScreenshotDetailedWithContext(ctx context.Context,
 		return nil, err
 	}
 
-	imageData, err := screenshot.CaptureToBytes(startX, startY, endX, endY)
+	captureCtx, cancelCapture := withStageDeadline(ctx, ai.StageCapture, s.options.Deadlines.Capture)
+	captured, err := captureWithContext(captureCtx, s.capturer, startX, startY, endX, endY)
+	err = stageError(ctx, captureCtx, err, "")
+	cancelCapture()
 	if err != nil {
 		return nil, fmt.Errorf("截图失败: %w", err)
 	}
+	return s.translateCaptured(ctx, captured, bounds, started)
+}
+
+// translateCaptured 对已截取的图像执行预处理、提取与翻译，started 为计时起点
+func (s *ServiceImpl) translateCaptured(ctx context.Context, captured image.Image, bounds ScreenshotBounds, started time.Time) (*ScreenshotTranslationResult, error) {
+	prepared, err := imageproc.Process(captured, s.options.Image)
+	if err != nil {
+		return nil, fmt.Errorf("图像预处理失败: %w", err)
+	}
+	imageData, mimeType := prepared.Data, prepared.MIMEType
 
 	if err := ctx.Err(); err != nil {
 		return nil, err
@@ -191,6 +309,9 @@ func (s *ServiceImpl) ProcessScreenshotDetailedWithContext(ctx context.Context,
 	// 处理动态提示词
 	processedExtractPrompt := prompts.ProcessExtractPrompt(s.extractPrompt, vars)
 	processedTranslatePrompt := prompts.ProcessTranslatePrompt(s.translatePrompt, vars)
+	if s.options.LayoutBlocks {
+		processedExtractPrompt = prompts.BuildLayoutExtractPrompt(vars)
+	}
 
 	result := &ScreenshotTranslationResult{
 		ExtractPrompt:   processedExtractPrompt,
@@ -199,32 +320,74 @@ func (s *ServiceImpl) ProcessScreenshotDetailedWithContext(ctx context.Context,
 	}
 
 	streamEnabled := s.options.Stream && s.streamHandler != nil
-	streamCallback := func(stage string) func(string) {
+
+	cacheKey := s.cacheKey("screenshot", imageData, processedExtractPrompt, processedTranslatePrompt)
+	if cached, ok := s.lookupCache(cacheKey); ok {
+		if cached.ExtractedText != "" {
+			result.Extract = ParseExtractResult(cached.ExtractedText)
+			result.ExtractedText = result.Extract.Words
+			result.RawExtractedText = cached.ExtractedText
+		}
+		result.TranslatedText = cached.TranslatedText
+		result.Blocks = placeBlocks(cached.Blocks, bounds)
+		if len(result.Blocks) > 0 {
+			if layout, ok := ParseLayoutResult(cached.ExtractedText); ok {
+				result.Extract = layout.ExtractResult()
+				result.ExtractedText = result.Extract.Words
+			}
+		}
+		result.CacheHit = true
+		if streamEnabled {
+			s.emitCached(cached.TranslatedText)
+		}
+		result.ProcessingTime = time.Since(started)
+		return result, nil
+	}
+
+	if err := s.enforceBudget(); err != nil {
+		return nil, err
+	}
+
+	// partial 记录已推送的译文，阶段超时时随错误返回
+	var partial strings.Builder
+	streamCallback := func(stage string) ai.StreamHandler {
 		if !streamEnabled {
 			return nil
 		}
-		return func(text string) {
+		return func(event ai.StreamEvent) {
 			if ctx.Err() != nil {
 				return
 			}
-			s.emitStream(stage, text)
+			if event.Reset {
+				partial.Reset()
+			}
+			partial.WriteString(event.Delta)
+			s.emitStream(stage, event)
 		}
 	}
 
 	// 视觉直出翻译模式
 	if s.options.UseVisionForTranslation {
 		directPrompt := prompts.BuildVisionDirectTranslationPrompt(vars)
-		if streamEnabled {
+		if s.options.LayoutBlocks {
+			directPrompt = prompts.BuildLayoutDirectPrompt(vars)
+			result.ExtractPrompt = directPrompt
+		}
+		directCtx, cancelDirect := withStageDeadline(ctx, ai.StageDirect, s.options.Deadlines.Translate)
+		defer cancelDirect()
+		// 分块模式的输出为 JSON，不推送流式增量，解析完成后再推送拼接好的译文
+		if streamEnabled && !s.options.LayoutBlocks {
 			translateResponse, err := s.AIClient.ImageToTranslationStreamWithContext(
-				ctx,
+				directCtx,
 				directPrompt,
 				imageData,
-				"image/png",
+				mimeType,
 				"",
 				streamCallback("translate"),
+				ai.WithSampling(s.options.DirectSampling),
 			)
 			if err != nil {
-				return nil, fmt.Errorf("视觉直出翻译失败: %w", err)
+				return nil, fmt.Errorf("视觉直出翻译失败: %w", stageError(ctx, directCtx, err, partial.String()))
 			}
 			if len(translateResponse.Choices) == 0 {
 				return nil, fmt.Errorf("视觉直出翻译结果为空")
@@ -233,17 +396,26 @@ func (s *ServiceImpl) ProcessScreenshotDetailedWithContext(ctx context.Context,
 			if err != nil {
 				return nil, fmt.Errorf("翻译内容解析失败: %w", err)
 			}
-			result.TranslatedText = translatedText
+			output, err := s.continueTruncated(directCtx, translateResponse, translatedText, streamCallback("translate"))
+			if err != nil {
+				return nil, fmt.Errorf("视觉直出翻译失败: %w", stageError(ctx, directCtx, err, partial.String()))
+			}
+			result.TranslatedText = output.text
+			result.Reasoning = output.reasoning
+			result.Continuations = output.continuations
+			result.Truncated = output.truncated
+			result.VisionServedBy = &translateResponse.ServedBy
 		} else {
 			translateResponse, err := s.AIClient.ImageToTranslationWithContext(
-				ctx,
+				directCtx,
 				directPrompt,
 				imageData,
-				"image/png",
+				mimeType,
 				"",
+				ai.WithSampling(s.options.DirectSampling),
 			)
 			if err != nil {
-				return nil, fmt.Errorf("视觉直出翻译失败: %w", err)
+				return nil, fmt.Errorf("视觉直出翻译失败: %w", stageError(ctx, directCtx, err, ""))
 			}
 			if len(translateResponse.Choices) == 0 {
 				return nil, fmt.Errorf("视觉直出翻译结果为空")
@@ -252,15 +424,48 @@ func (s *ServiceImpl) ProcessScreenshotDetailedWithContext(ctx context.Context,
 			if err != nil {
 				return nil, fmt.Errorf("翻译内容解析失败: %w", err)
 			}
-			result.TranslatedText = translatedText
+			output, err := s.continueTruncated(directCtx, translateResponse, translatedText, nil)
+			if err != nil {
+				return nil, fmt.Errorf("视觉直出翻译失败: %w", stageError(ctx, directCtx, err, ""))
+			}
+			result.TranslatedText = output.text
+			result.Reasoning = output.reasoning
+			result.Continuations = output.continuations
+			result.Truncated = output.truncated
+			result.VisionServedBy = &translateResponse.ServedBy
+
+			if s.options.LayoutBlocks {
+				// 模型未按分块格式输出时保留原始输出作为译文
+				if layout, ok := ParseLayoutResult(output.text); ok {
+					result.Extract = layout.ExtractResult()
+					result.ExtractedText = result.Extract.Words
+					result.RawExtractedText = layout.Raw
+					result.Blocks = placeBlocks(newTranslatedBlocks(layout, nil, prepared.Crop, captured.Bounds().Size()), bounds)
+					result.TranslatedText = blockTargets(result.Blocks)
+				}
+				if streamEnabled {
+					s.emitCached(result.TranslatedText)
+				}
+			}
+		}
+	} else if streamEnabled && s.options.PipelineTranslation && !s.options.LayoutBlocks {
+		// 流水线模式：边提取边翻译
+		if err := s.pipelineTranslate(ctx, result, imageData, mimeType, streamCallback("translate"), partial.String); err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(result.ExtractedText) == "" {
+			result.ProcessingTime = time.Since(started)
+			return result, nil
 		}
 	} else {
 		// 传统模式：先提取，再翻译
 
 		// OCR 阶段
-		extractResponse, err := s.AIClient.ImageToWordsWithContext(ctx, processedExtractPrompt, imageData, "image/png", "")
+		ocrCtx, cancelOCR := withStageDeadline(ctx, ai.StageOCR, s.options.Deadlines.OCR)
+		defer cancelOCR()
+		extractResponse, err := s.AIClient.ImageToWordsWithContext(ocrCtx, processedExtractPrompt, imageData, mimeType, "", ai.WithSampling(s.options.ExtractSampling))
 		if err != nil {
-			return nil, fmt.Errorf("文字提取失败: %w", err)
+			return nil, fmt.Errorf("文字提取失败: %w", stageError(ctx, ocrCtx, err, ""))
 		}
 
 		if len(extractResponse.Choices) == 0 {
@@ -272,9 +477,28 @@ func (s *ServiceImpl) ProcessScreenshotDetailedWithContext(ctx context.Context,
 			return nil, fmt.Errorf("提取内容解析失败: %w", err)
 		}
 
-		result.ExtractedText = extractedText
+		extracted, err := s.continueTruncated(ocrCtx, extractResponse, extractedText, nil)
+		if err != nil {
+			return nil, fmt.Errorf("文字提取失败: %w", stageError(ctx, ocrCtx, err, ""))
+		}
+		extract := ParseExtractResult(extracted.text)
+		// 分块解析失败时按普通提取结果继续，退回整段翻译
+		layout, layoutMode := LayoutResult{}, false
+		if s.options.LayoutBlocks {
+			if layout, layoutMode = ParseLayoutResult(extracted.text); layoutMode {
+				extract = layout.ExtractResult()
+			}
+		}
+
+		result.Extract = extract
+		result.ExtractedText = extract.Words
+		result.RawExtractedText = extract.Raw
+		result.ExtractReasoning = extracted.reasoning
+		result.Continuations = extracted.continuations
+		result.Truncated = extracted.truncated
+		result.VisionServedBy = &extractResponse.ServedBy
 
-		if strings.TrimSpace(extractedText) == "" {
+		if strings.TrimSpace(extract.Words) == "" {
 			result.ProcessingTime = time.Since(started)
 			return result, nil
 		}
@@ -283,20 +507,35 @@ func (s *ServiceImpl) ProcessScreenshotDetailedWithContext(ctx context.Context,
 			return nil, err
 		}
 
+		if err := s.budgetExceeded(); err != nil {
+			return nil, err
+		}
+
 		// 翻译阶段
+		translateCtx, cancelTranslate := withStageDeadline(ctx, ai.StageTranslate, s.options.Deadlines.Translate)
+		defer cancelTranslate()
+		translateInput, translatePrompt := extract.TranslateInput(), processedTranslatePrompt
+		onTranslate := streamCallback("translate")
+		if layoutMode {
+			// 分块模式的输出为 JSON 数组，不推送流式增量，解析完成后再推送拼接好的译文
+			translateInput, translatePrompt = layout.TranslateInput(), prompts.BuildLayoutTranslatePrompt(vars)
+			result.TranslatePrompt = translatePrompt
+			onTranslate = nil
+		}
 		var translateResponse *ai.ZhipuAIResponse
-		if streamEnabled {
+		if onTranslate != nil {
 			translateResponse, err = s.AIClient.TranslateStreamWithContext(
-				ctx,
-				extractedText,
-				processedTranslatePrompt,
-				streamCallback("translate"),
+				translateCtx,
+				translateInput,
+				translatePrompt,
+				onTranslate,
+				ai.WithSampling(s.options.TranslateSampling),
 			)
 		} else {
-			translateResponse, err = s.AIClient.TranslateWithContext(ctx, extractedText, processedTranslatePrompt)
+			translateResponse, err = s.AIClient.TranslateWithContext(translateCtx, translateInput, translatePrompt, ai.WithSampling(s.options.TranslateSampling))
 		}
 		if err != nil {
-			return nil, fmt.Errorf("翻译失败: %w", err)
+			return nil, fmt.Errorf("翻译失败: %w", stageError(ctx, translateCtx, err, partial.String()))
 		}
 
 		if len(translateResponse.Choices) == 0 {
@@ -308,13 +547,45 @@ func (s *ServiceImpl) ProcessScreenshotDetailedWithContext(ctx context.Context,
 			return nil, fmt.Errorf("翻译内容解析失败: %w", err)
 		}
 
-		result.TranslatedText = translatedText
+		output, err := s.continueTruncated(translateCtx, translateResponse, translatedText, onTranslate)
+		if err != nil {
+			return nil, fmt.Errorf("翻译失败: %w", stageError(ctx, translateCtx, err, partial.String()))
+		}
+
+		result.TranslatedText = output.text
+		if layoutMode {
+			// 译文数量与原文块不一致时无法逐块对应，退回为整段译文
+			targets, ok := ParseBlockTranslations(output.text, len(layout.Blocks))
+			switch {
+			case ok:
+				result.Blocks = placeBlocks(newTranslatedBlocks(layout, targets, prepared.Crop, captured.Bounds().Size()), bounds)
+				result.TranslatedText = blockTargets(result.Blocks)
+			case len(targets) > 0:
+				result.TranslatedText = strings.Join(targets, "\n")
+			}
+			if streamEnabled {
+				s.emitCached(result.TranslatedText)
+			}
+		}
+		result.Reasoning = output.reasoning
+		result.Continuations += output.continuations
+		result.Truncated = result.Truncated || output.truncated
+		result.TranslateServedBy = &translateResponse.ServedBy
 	}
 
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
 
+	// 截断的结果不写入缓存，下次可以重新请求完整译文；
+	// 缓存键只包含主接口的模型，备用接口生成的结果也不写入，避免主接口恢复后仍返回备用模型的译文
+	if !result.Truncated && !servedByFallback(result.VisionServedBy, result.TranslateServedBy) {
+		s.storeCache(cacheKey, cachedTranslation{
+			ExtractedText:  result.RawExtractedText,
+			TranslatedText: result.TranslatedText,
+			Blocks:         result.Blocks,
+		})
+	}
 	result.ProcessingTime = time.Since(started)
 	return result, nil
 }
@@ -351,25 +622,54 @@ func (s *ServiceImpl) TranslateTextWithContext(ctx context.Context, input string
 	// 处理动态提示词
 	processedTranslatePrompt := prompts.ProcessTranslatePrompt(s.translatePrompt, vars)
 
+	cacheKey := s.cacheKey("text", []byte(input), "", processedTranslatePrompt)
+	if cached, ok := s.lookupCache(cacheKey); ok {
+		if streamEnabled {
+			s.emitCached(cached.TranslatedText)
+		}
+		return &TextTranslationResult{
+			OriginalText:    input,
+			TranslatedText:  cached.TranslatedText,
+			TranslatePrompt: processedTranslatePrompt,
+			ProcessingTime:  time.Since(started),
+			CacheHit:        true,
+		}, nil
+	}
+
+	if err := s.enforceBudget(); err != nil {
+		return nil, err
+	}
+
+	translateCtx, cancelTranslate := withStageDeadline(ctx, ai.StageTranslate, s.options.Deadlines.Translate)
+	defer cancelTranslate()
+
 	var translateResponse *ai.ZhipuAIResponse
+	var partial strings.Builder
+	var onEvent ai.StreamHandler
 	var err error
 	if streamEnabled {
+		onEvent = func(event ai.StreamEvent) {
+			if ctx.Err() != nil {
+				return
+			}
+			if event.Reset {
+				partial.Reset()
+			}
+			partial.WriteString(event.Delta)
+			s.emitStream("translate", event)
+		}
 		translateResponse, err = s.AIClient.TranslateStreamWithContext(
-			ctx,
+			translateCtx,
 			input,
 			processedTranslatePrompt,
-			func(text string) {
-				if ctx.Err() != nil {
-					return
-				}
-				s.emitStream("translate", text)
-			},
+			onEvent,
+			ai.WithSampling(s.options.TranslateSampling),
 		)
 	} else {
-		translateResponse, err = s.AIClient.TranslateWithContext(ctx, input, processedTranslatePrompt)
+		translateResponse, err = s.AIClient.TranslateWithContext(translateCtx, input, processedTranslatePrompt, ai.WithSampling(s.options.TranslateSampling))
 	}
 	if err != nil {
-		return nil, fmt.Errorf("翻译失败: %w", err)
+		return nil, fmt.Errorf("翻译失败: %w", stageError(ctx, translateCtx, err, partial.String()))
 	}
 
 	if len(translateResponse.Choices) == 0 {
@@ -381,11 +681,23 @@ func (s *ServiceImpl) TranslateTextWithContext(ctx context.Context, input string
 		return nil, fmt.Errorf("翻译内容解析失败: %w", err)
 	}
 
+	output, err := s.continueTruncated(translateCtx, translateResponse, translatedText, onEvent)
+	if err != nil {
+		return nil, fmt.Errorf("翻译失败: %w", stageError(ctx, translateCtx, err, partial.String()))
+	}
+
+	if !output.truncated && !translateResponse.ServedBy.IsFallback() {
+		s.storeCache(cacheKey, cachedTranslation{TranslatedText: output.text})
+	}
 	return &TextTranslationResult{
 		OriginalText:    input,
-		TranslatedText:  translatedText,
+		TranslatedText:  output.text,
 		TranslatePrompt: processedTranslatePrompt,
+		Reasoning:       output.reasoning,
 		ProcessingTime:  time.Since(started),
+		Continuations:   output.continuations,
+		Truncated:       output.truncated,
+		ServedBy:        &translateResponse.ServedBy,
 	}, nil
 }
 
@@ -405,11 +717,241 @@ func (s *ServiceImpl) SetStreamHandler(handler StreamHandler) {
 	s.streamHandler = handler
 }
 
-func (s *ServiceImpl) emitStream(stage, content string) {
+// SetUsageLedger 配置用于预算检查的用量账本，为 nil 时不检查预算
+func (s *ServiceImpl) SetUsageLedger(ledger *usage.Ledger) {
+	s.usageLedger = ledger
+}
+
+// SetResultCache 配置结果缓存，为 nil 时不使用缓存
+func (s *ServiceImpl) SetResultCache(resultCache *cache.Cache) {
+	s.resultCache = resultCache
+}
+
+// SetBudgetWarningHandler 配置预算提醒回调
+func (s *ServiceImpl) SetBudgetWarningHandler(handler BudgetWarningHandler) {
+	s.budgetHandler = handler
+}
+
+// CheckBudget 按当前用量检查预算，达到硬上限时返回 *usage.BudgetExceededError
+func (s *ServiceImpl) CheckBudget() ([]usage.BudgetWarning, error) {
+	if s.usageLedger == nil {
+		return nil, nil
+	}
+	return s.usageLedger.CheckBudget(s.options.Budget, time.Now())
+}
+
+// enforceBudget 在一次翻译开始前检查预算，软上限通过回调提醒，硬上限直接返回错误
+func (s *ServiceImpl) enforceBudget() error {
+	warnings, err := s.CheckBudget()
+	if err != nil {
+		return err
+	}
+	if len(warnings) > 0 && s.budgetHandler != nil {
+		s.budgetHandler(warnings)
+	}
+	return nil
+}
+
+// budgetExceeded 在同一次翻译的后续模型请求前检查硬上限，软上限已在 enforceBudget 中提醒过，此处不再重复
+func (s *ServiceImpl) budgetExceeded() error {
+	_, err := s.CheckBudget()
+	return err
+}
+
+// cacheKey 由输入内容、处理后的提示词、模型名称、语言对与采样参数组合生成缓存键
+func (s *ServiceImpl) cacheKey(kind string, input []byte, extractPrompt, translatePrompt string) string {
+	if s.resultCache == nil {
+		return ""
+	}
+	mode := "relay"
+	if s.options.UseVisionForTranslation {
+		mode = "vision"
+	}
+	if s.options.LayoutBlocks {
+		mode += "+layout"
+	}
+	return cache.Key(
+		[]byte(kind),
+		input,
+		[]byte(extractPrompt),
+		[]byte(translatePrompt),
+		[]byte(mode),
+		[]byte(s.AIClient.VisionModel()),
+		[]byte(s.AIClient.TranslateModel()),
+		[]byte(s.options.SourceLanguage),
+		[]byte(s.options.TargetLanguage),
+		s.samplingKey(),
+	)
+}
+
+func (s *ServiceImpl) samplingKey() []byte {
+	data, _ := json.Marshal([]ai.Sampling{s.options.ExtractSampling, s.options.TranslateSampling, s.options.DirectSampling})
+	return data
+}
+
+func (s *ServiceImpl) lookupCache(key string) (cachedTranslation, bool) {
+	var cached cachedTranslation
+	if s.resultCache == nil || key == "" {
+		return cached, false
+	}
+	if !s.resultCache.Get(key, &cached) || strings.TrimSpace(cached.TranslatedText) == "" {
+		return cachedTranslation{}, false
+	}
+	return cached, true
+}
+
+// servedByFallback 报告各阶段中是否有由备用接口应答的
+func servedByFallback(served ...*ai.ServedBy) bool {
+	for _, item := range served {
+		if item != nil && item.IsFallback() {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *ServiceImpl) storeCache(key string, value cachedTranslation) {
+	if s.resultCache == nil || key == "" || strings.TrimSpace(value.TranslatedText) == "" {
+		return
+	}
+	if err := s.resultCache.Set(key, value); err != nil {
+		fmt.Printf("写入结果缓存失败: %v\n", err)
+	}
+}
+
+// emitStream 推送流式增量，未开启 ShowReasoning 时丢弃推理内容及仅含推理内容的事件
+func (s *ServiceImpl) emitStream(stage string, event ai.StreamEvent) {
 	if !s.options.Stream || s.streamHandler == nil {
 		return
 	}
-	s.streamHandler(stage, content)
+	if !s.options.ShowReasoning && event.Reasoning != "" {
+		event.Reasoning = ""
+		if event.Delta == "" && event.FinishReason == "" && event.Usage == nil && !event.Reset {
+			return
+		}
+	}
+	s.streamHandler(stage, event)
+}
+
+// reasoning 返回响应中的思考过程，未开启 ShowReasoning 时返回空字符串
+func (s *ServiceImpl) reasoning(response *ai.ZhipuAIResponse) string {
+	if !s.options.ShowReasoning {
+		return ""
+	}
+	return strings.TrimSpace(response.Reasoning())
+}
+
+// emitCached 以单个增量推送缓存中的译文，保持与模型流式输出一致的事件格式
+func (s *ServiceImpl) emitCached(text string) {
+	s.emitStream("translate", ai.StreamEvent{
+		Delta:        text,
+		Length:       utf8.RuneCountInString(text),
+		FinishReason: "stop",
+	})
+}
+
+// continued 为经过自动续写拼接后的输出
+type continued struct {
+	text          string
+	reasoning     string
+	continuations int
+	truncated     bool
+}
+
+// continueTruncated 在输出因 max_tokens 被截断且开启 AutoContinue 时发送续写请求并拼接正文，
+// 最多续写 MaxContinuations 次。续写失败或达到预算硬上限时保留已有内容并标记为截断，仅在 ctx 结束时返回错误。
+func (s *ServiceImpl) continueTruncated(ctx context.Context, response *ai.ZhipuAIResponse, text string, onEvent ai.StreamHandler) (continued, error) {
+	output := continued{
+		text:      text,
+		reasoning: s.reasoning(response),
+		truncated: response.Truncated(),
+	}
+	for output.truncated && s.options.AutoContinue && output.continuations < s.options.MaxContinuations {
+		var handler ai.StreamHandler
+		if onEvent != nil {
+			// 续写的增量接在已有译文之后，Length 需要累计；续写内容被作废时连同已有译文一起重新推送
+			prefix := output.text
+			offset := utf8.RuneCountInString(prefix)
+			handler = func(event ai.StreamEvent) {
+				event.Length += offset
+				if event.Reset {
+					event.Delta = prefix + event.Delta
+				}
+				onEvent(event)
+			}
+		}
+
+		if err := s.budgetExceeded(); err != nil {
+			fmt.Printf("已达预算上限，停止续写: %v\n", err)
+			break
+		}
+
+		next, err := s.AIClient.ContinueWithContext(ctx, response, handler)
+		if err != nil {
+			if ctx.Err() != nil {
+				return output, err
+			}
+			fmt.Printf("续写请求失败: %v\n", err)
+			break
+		}
+		if len(next.Choices) == 0 {
+			break
+		}
+		piece, err := messageContentToString(next.Choices[0].Message.Content)
+		if err != nil {
+			break
+		}
+
+		output.continuations++
+		output.text += piece
+		if reasoning := s.reasoning(next); reasoning != "" {
+			output.reasoning = strings.TrimSpace(output.reasoning + "\n\n" + reasoning)
+		}
+		output.truncated = next.Truncated()
+		response = next
+	}
+	return output, nil
+}
+
+// withStageDeadline 为阶段设置总时长预算，到期后 stageCtx 的 Cause 为 *ai.TimeoutError
+func withStageDeadline(ctx context.Context, stage string, limit time.Duration) (context.Context, context.CancelFunc) {
+	if limit <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeoutCause(ctx, limit, &ai.TimeoutError{Stage: stage, Limit: ai.TimeoutTotal, Duration: limit})
+}
+
+// stageError 在阶段预算到期时返回带部分译文的 *ai.TimeoutError，其他错误原样返回
+func stageError(parent, stageCtx context.Context, err error, partial string) error {
+	if err == nil || parent.Err() != nil || !errors.Is(stageCtx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	var timeoutErr *ai.TimeoutError
+	if !errors.As(context.Cause(stageCtx), &timeoutErr) {
+		return err
+	}
+	expired := *timeoutErr
+	expired.Partial = partial
+	return &expired
+}
+
+// captureWithContext 在后台截图，超出截图预算或被取消时立即返回，迟到的截图结果会被丢弃
+func captureWithContext(ctx context.Context, capturer screenshot.Capturer, startX, startY, endX, endY int) (image.Image, error) {
+	type captured struct {
+		img image.Image
+		err error
+	}
+	done := make(chan captured, 1)
+	go func() {
+		img, err := screenshot.CaptureWith(capturer, startX, startY, endX, endY)
+		done <- captured{img: img, err: err}
+	}()
+	select {
+	case result := <-done:
+		return result.img, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
 }
 
 // buildVisionTranslationMessage 保留此函数以保持向后兼容性，但不再使用
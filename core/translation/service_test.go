@@ -0,0 +1,272 @@
+package translation
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"Translater/core/ai"
+	"Translater/core/cache"
+	"Translater/core/screenshot"
+	"Translater/core/usage"
+)
+
+// chatServer 返回固定译文的 OpenAI 兼容接口；status 非 200 时返回错误，hits 统计请求数
+func chatServer(t *testing.T, status int, content string, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if status != http.StatusOK {
+			http.Error(w, `{"error":{"message":"unavailable"}}`, status)
+			return
+		}
+		fmt.Fprintf(w, `{"choices":[{"message":{"role":"assistant","content":%q},"finish_reason":"stop"}],"usage":{"total_tokens":1}}`, content)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newCachedService(t *testing.T, primaryStatus int) (Service, *atomic.Int32, *atomic.Int32) {
+	t.Helper()
+	var primaryHits, fallbackHits atomic.Int32
+	primary := chatServer(t, primaryStatus, "主接口译文", &primaryHits)
+	fallback := chatServer(t, http.StatusOK, "备用接口译文", &fallbackHits)
+
+	client := ai.NewClient(ai.ClientConfig{
+		APIKey:             "key",
+		BaseURL:            primary.URL,
+		TranslateModel:     "primary-model",
+		Retry:              ai.RetryPolicy{MaxAttempts: 1},
+		TranslateFallbacks: []ai.EndpointConfig{{BaseURL: fallback.URL, Model: "fallback-model"}},
+	})
+	resultCache, err := cache.New("", cache.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := NewService(client, nil, "", "请翻译为中文", Options{})
+	service.SetResultCache(resultCache)
+	return service, &primaryHits, &fallbackHits
+}
+
+func TestTranslateTextCachesPrimaryResult(t *testing.T) {
+	service, primaryHits, _ := newCachedService(t, http.StatusOK)
+
+	for i, wantHit := range []bool{false, true} {
+		result, err := service.TranslateTextWithContext(context.Background(), "hello")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.CacheHit != wantHit || result.TranslatedText != "主接口译文" {
+			t.Fatalf("call %d: CacheHit = %v, text = %q", i, result.CacheHit, result.TranslatedText)
+		}
+	}
+	if primaryHits.Load() != 1 {
+		t.Fatalf("primary hits = %d, want 1", primaryHits.Load())
+	}
+}
+
+func TestTranslateTextSkipsCacheForFallbackResult(t *testing.T) {
+	service, _, fallbackHits := newCachedService(t, http.StatusBadGateway)
+
+	for i := 0; i < 2; i++ {
+		result, err := service.TranslateTextWithContext(context.Background(), "hello")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.CacheHit || result.ServedBy == nil || !result.ServedBy.IsFallback() {
+			t.Fatalf("call %d: CacheHit = %v, ServedBy = %+v", i, result.CacheHit, result.ServedBy)
+		}
+	}
+	if fallbackHits.Load() != 2 {
+		t.Fatalf("fallback hits = %d, want 2", fallbackHits.Load())
+	}
+}
+
+// visionCalls 记录视觉接口收到的图片与纯文本翻译请求数，fail 为接下来需要失败的视觉请求数
+type visionCalls struct {
+	mu        sync.Mutex
+	images    []image.Image
+	translate int
+	fail      int
+}
+
+func (v *visionCalls) counts() (images, translate int) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return len(v.images), v.translate
+}
+
+// visionServer 模拟同时提供视觉与翻译的接口：带图片的请求返回提取结果并记录解码后的图片，纯文本请求返回译文
+func visionServer(t *testing.T, calls *visionCalls) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Messages []struct {
+				Content json.RawMessage `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var img image.Image
+		for _, message := range request.Messages {
+			var items []ai.ContentItem
+			if json.Unmarshal(message.Content, &items) != nil {
+				continue
+			}
+			for _, item := range items {
+				if item.Type != "image_url" {
+					continue
+				}
+				_, encoded, _ := strings.Cut(item.ImageURL.URL, ",")
+				data, err := base64.StdEncoding.DecodeString(encoded)
+				if err == nil {
+					img, _, err = image.Decode(bytes.NewReader(data))
+				}
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+			}
+		}
+
+		content := "你好"
+		calls.mu.Lock()
+		if img != nil && calls.fail > 0 {
+			calls.fail--
+			calls.mu.Unlock()
+			http.Error(w, `{"error":{"message":"unavailable"}}`, http.StatusInternalServerError)
+			return
+		}
+		if img != nil {
+			calls.images = append(calls.images, img)
+			content = `{"background":"按钮","words":"Hello"}`
+		} else {
+			calls.translate++
+		}
+		calls.mu.Unlock()
+		fmt.Fprintf(w, `{"choices":[{"message":{"role":"assistant","content":%q},"finish_reason":"stop"}],"usage":{"total_tokens":1}}`, content)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newVisionService(t *testing.T, capturer screenshot.Capturer, calls *visionCalls) *ServiceImpl {
+	t.Helper()
+	server := visionServer(t, calls)
+	client := ai.NewClient(ai.ClientConfig{
+		APIKey:        "key",
+		BaseURL:       server.URL,
+		VisionBaseURL: server.URL,
+		Retry:         ai.RetryPolicy{MaxAttempts: 1},
+	})
+	return NewService(client, capturer, "", "请翻译为中文", Options{}).(*ServiceImpl)
+}
+
+func TestProcessScreenshotCropsCapturedRegion(t *testing.T) {
+	// 白色背景上 (20,10)-(60,40) 为红色，截取该区域后发给模型的图片应为纯红色
+	screen := image.NewRGBA(image.Rect(0, 0, 100, 80))
+	draw.Draw(screen, screen.Bounds(), image.White, image.Point{}, draw.Src)
+	red := color.RGBA{R: 255, A: 255}
+	region := image.Rect(20, 10, 60, 40)
+	draw.Draw(screen, region, image.NewUniform(red), image.Point{}, draw.Src)
+
+	capturer := screenshot.NewImageCapturer(screen)
+	var calls visionCalls
+	service := newVisionService(t, capturer, &calls)
+
+	// 从右下向左上拖选，区域应被规范化
+	result, err := service.ProcessScreenshotDetailedWithContext(context.Background(), 60, 40, 20, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rects := capturer.Rects(); len(rects) != 1 || rects[0] != region {
+		t.Fatalf("Rects() = %v, want [%v]", rects, region)
+	}
+	if b := result.Bounds; b.Left != 20 || b.Top != 10 || b.Width != 40 || b.Height != 30 {
+		t.Fatalf("Bounds = %+v", b)
+	}
+	if result.ExtractedText != "Hello" || result.TranslatedText != "你好" {
+		t.Fatalf("ExtractedText = %q, TranslatedText = %q", result.ExtractedText, result.TranslatedText)
+	}
+
+	if images, translate := calls.counts(); images != 1 || translate != 1 {
+		t.Fatalf("vision calls = %d, translate calls = %d, want 1 and 1", images, translate)
+	}
+	sent := calls.images[0]
+	if size := sent.Bounds().Size(); size != region.Size() {
+		t.Fatalf("sent image size = %v, want %v", size, region.Size())
+	}
+	for _, p := range []image.Point{sent.Bounds().Min, sent.Bounds().Max.Sub(image.Pt(1, 1))} {
+		r, g, b, _ := sent.At(p.X, p.Y).RGBA()
+		if r>>8 != 255 || g != 0 || b != 0 {
+			t.Fatalf("pixel %v = %v, want red", p, sent.At(p.X, p.Y))
+		}
+	}
+}
+
+func TestProcessScreenshotCaptureError(t *testing.T) {
+	capturer := screenshot.NewImageCapturer(image.NewRGBA(image.Rect(0, 0, 10, 10)))
+	capturer.SetError(errors.New("display unavailable"))
+	var calls visionCalls
+	service := newVisionService(t, capturer, &calls)
+
+	_, err := service.ProcessScreenshotDetailedWithContext(context.Background(), 0, 0, 5, 5)
+	if err == nil || !strings.Contains(err.Error(), "display unavailable") {
+		t.Fatalf("err = %v, want capture error", err)
+	}
+	if images, translate := calls.counts(); images != 0 || translate != 0 {
+		t.Fatalf("model called after capture failure: %d vision, %d translate", images, translate)
+	}
+
+	capturer.SetError(nil)
+	if _, err := service.ProcessScreenshotDetailedWithContext(context.Background(), 0, 0, 5, 5); err != nil {
+		t.Fatalf("capture after SetError(nil): %v", err)
+	}
+	if rects := capturer.Rects(); len(rects) != 2 {
+		t.Fatalf("Rects() = %v, want two capture attempts", rects)
+	}
+}
+
+func TestHardBudgetStopsLaterRequests(t *testing.T) {
+	ledger, err := usage.NewLedger(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls visionCalls
+	server := visionServer(t, &calls)
+	client := ai.NewClient(ai.ClientConfig{
+		APIKey:        "key",
+		BaseURL:       server.URL,
+		VisionBaseURL: server.URL,
+		Retry:         ai.RetryPolicy{MaxAttempts: 1},
+		OnUsage:       ledger.Observe,
+	})
+	capturer := screenshot.NewImageCapturer(image.NewRGBA(image.Rect(0, 0, 20, 20)))
+	service := NewService(client, capturer, "", "请翻译为中文", Options{
+		Budget: usage.Budget{Hard: usage.Limits{RequestsPerDay: 1}},
+	})
+	service.SetUsageLedger(ledger)
+
+	// 提取请求计入用量后达到上限，同一次翻译中的翻译请求不应再发出
+	_, err = service.ProcessScreenshotDetailedWithContext(context.Background(), 0, 0, 20, 20)
+	if !errors.Is(err, usage.ErrBudgetExceeded) {
+		t.Fatalf("err = %v, want budget exceeded", err)
+	}
+	if images, translate := calls.counts(); images != 1 || translate != 0 {
+		t.Fatalf("vision calls = %d, translate calls = %d, want 1 and 0", images, translate)
+	}
+}
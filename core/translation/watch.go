@@ -0,0 +1,120 @@
+package translation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"Translater/core/ai"
+	"Translater/core/imageproc"
+)
+
+// 监视模式的默认参数
+const (
+	DefaultWatchInterval  = 500 * time.Millisecond
+	DefaultWatchThreshold = 4
+	DefaultWatchDebounce  = 400 * time.Millisecond
+)
+
+// WatchOptions 控制监视模式的轮询间隔与变化判定
+type WatchOptions struct {
+	// Interval 为两次截图之间的间隔
+	Interval time.Duration
+	// Threshold 为感知哈希的汉明距离阈值（0-64），超过该值才视为内容变化
+	Threshold int
+	// Debounce 为变化后画面需要保持稳定的时长，用于跳过逐字出现的字幕或过场动画
+	Debounce time.Duration
+}
+
+func (o WatchOptions) normalized() WatchOptions {
+	if o.Interval <= 0 {
+		o.Interval = DefaultWatchInterval
+	}
+	if o.Threshold < 0 {
+		o.Threshold = DefaultWatchThreshold
+	}
+	if o.Debounce < 0 {
+		o.Debounce = 0
+	}
+	return o
+}
+
+// WatchEvent 为监视模式下的一次通知
+type WatchEvent struct {
+	// Changed 为 true 表示检测到新内容、即将开始翻译；否则为一次翻译的结果或错误
+	Changed bool
+	Result  *ScreenshotTranslationResult
+	Err     error
+}
+
+// WatchHandler 接收监视模式的通知，在 WatchRegion 所在的 goroutine 中同步调用
+type WatchHandler func(event WatchEvent)
+
+// WatchRegion 按 opts.Interval 轮询截取区域，以感知哈希比较画面：
+// 与上次翻译的画面差异超过阈值、且保持稳定 opts.Debounce 后才重新翻译。
+// 单次翻译失败不会结束监视，该画面再次稳定 opts.Debounce 后会重试；直到 ctx 被取消才返回 ctx.Err()。
+func (s *ServiceImpl) WatchRegion(ctx context.Context, startX, startY, endX, endY int, opts WatchOptions, handler WatchHandler) error {
+	if s.AIClient == nil {
+		return fmt.Errorf("AI client 未初始化")
+	}
+	if handler == nil {
+		handler = func(WatchEvent) {}
+	}
+	opts = opts.normalized()
+	bounds := newScreenshotBounds(startX, startY, endX, endY)
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	var (
+		translated    uint64
+		hasTranslated bool
+		// pending 为尚未稳定的新画面，pendingSince 为其首次出现的时间
+		pending      uint64
+		pendingSince time.Time
+		hasPending   bool
+	)
+	for {
+		captureCtx, cancelCapture := withStageDeadline(ctx, ai.StageCapture, s.options.Deadlines.Capture)
+		frame, err := captureWithContext(captureCtx, s.capturer, startX, startY, endX, endY)
+		err = stageError(ctx, captureCtx, err, "")
+		cancelCapture()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err != nil {
+			handler(WatchEvent{Err: fmt.Errorf("截图失败: %w", err)})
+		} else {
+			hash := imageproc.PerceptualHash(frame)
+			switch {
+			case hasTranslated && imageproc.HashDistance(hash, translated) <= opts.Threshold:
+				// 回到已翻译的画面，放弃尚未稳定的变化
+				hasPending = false
+			case !hasPending || imageproc.HashDistance(hash, pending) > opts.Threshold:
+				// 出现新的变化，重新开始计算稳定时长
+				pending, pendingSince, hasPending = hash, time.Now(), true
+			}
+
+			if hasPending && time.Since(pendingSince) >= opts.Debounce {
+				hasPending = false
+				handler(WatchEvent{Changed: true})
+				result, err := s.translateCaptured(ctx, frame, bounds, time.Now())
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				// 仅在翻译成功后记录画面，失败的画面再次稳定后会重新翻译
+				if err == nil {
+					translated, hasTranslated = hash, true
+				}
+				handler(WatchEvent{Result: result, Err: err})
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	"Translater/core/screenshot"
+)
+
+// watchFrame 构造 9x8 个纯色格子的画面，shift 不同时水平相邻格子的明暗关系不同
+func watchFrame(shift int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 90, 80))
+	for y := 0; y < 80; y++ {
+		for x := 0; x < 90; x++ {
+			value := uint8(40 + (x/10*3+y/10*5+shift)%9*20)
+			img.SetRGBA(x, y, color.RGBA{value, value, value, 255})
+		}
+	}
+	return img
+}
+
+// noisy 为画面叠加细微的确定性噪点，感知哈希应保持不变
+func noisy(src *image.RGBA) *image.RGBA {
+	img := image.NewRGBA(src.Rect)
+	for y := 0; y < src.Rect.Dy(); y++ {
+		for x := 0; x < src.Rect.Dx(); x++ {
+			value := uint8(int(src.RGBAAt(x, y).R) + (x*31+y*17)%17 - 8)
+			img.SetRGBA(x, y, color.RGBA{value, value, value, 255})
+		}
+	}
+	return img
+}
+
+// hold 返回重复 n 次的画面序列
+func hold(img image.Image, n int) []image.Image {
+	frames := make([]image.Image, n)
+	for i := range frames {
+		frames[i] = img
+	}
+	return frames
+}
+
+// frameCapturer 每次截图前将 ImageCapturer 的来源切换为下一帧，所有帧截取完后取消监视
+type frameCapturer struct {
+	*screenshot.ImageCapturer
+	frames []image.Image
+	cancel context.CancelFunc
+}
+
+func (c *frameCapturer) CaptureRect(rect image.Rectangle) (image.Image, error) {
+	if len(c.frames) == 0 {
+		c.cancel()
+		return nil, context.Canceled
+	}
+	c.SetImage(c.frames[0])
+	c.frames = c.frames[1:]
+	return c.ImageCapturer.CaptureRect(rect)
+}
+
+// watchEvents 依次回放 frames 并返回监视过程中的通知
+func watchEvents(t *testing.T, frames []image.Image, opts WatchOptions, calls *visionCalls) []WatchEvent {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	capturer := &frameCapturer{ImageCapturer: screenshot.NewImageCapturer(nil), frames: frames, cancel: cancel}
+	service := newVisionService(t, capturer, calls)
+
+	var events []WatchEvent
+	err := service.WatchRegion(ctx, 0, 0, 90, 80, opts, func(event WatchEvent) {
+		events = append(events, event)
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("WatchRegion returned %v, want context.Canceled", err)
+	}
+	return events
+}
+
+func TestWatchRegionTranslations(t *testing.T) {
+	a, b, c := watchFrame(0), watchFrame(1), watchFrame(2)
+	concat := func(parts ...[]image.Image) []image.Image {
+		var frames []image.Image
+		for _, part := range parts {
+			frames = append(frames, part...)
+		}
+		return frames
+	}
+	alternating := concat(hold(b, 1), hold(c, 1), hold(b, 1), hold(c, 1), hold(b, 1), hold(c, 1), hold(b, 1), hold(c, 1))
+
+	cases := []struct {
+		name      string
+		frames    []image.Image
+		threshold int
+		debounce  time.Duration
+		want      int
+	}{
+		{"stable frame translated once", hold(a, 20), 4, 20 * time.Millisecond, 1},
+		{"noise within threshold", concat(hold(a, 10), hold(noisy(a), 10)), 4, 20 * time.Millisecond, 1},
+		{"change above threshold", concat(hold(a, 10), hold(b, 10)), 4, 20 * time.Millisecond, 2},
+		{"change within a high threshold", concat(hold(a, 10), hold(b, 10)), 64, 20 * time.Millisecond, 1},
+		{"flicker shorter than debounce", concat(hold(a, 10), hold(b, 1), hold(a, 10)), 4, 20 * time.Millisecond, 1},
+		{"changing frames reset debounce", concat(hold(a, 10), alternating, hold(a, 10)), 4, 20 * time.Millisecond, 1},
+		{"return to previously translated frame", concat(hold(a, 1), hold(b, 1), hold(a, 1)), 4, 0, 3},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls visionCalls
+			opts := WatchOptions{Interval: 5 * time.Millisecond, Threshold: tc.threshold, Debounce: tc.debounce}
+			events := watchEvents(t, tc.frames, opts, &calls)
+
+			var changed, results int
+			for _, event := range events {
+				switch {
+				case event.Changed:
+					changed++
+				case event.Err != nil:
+					t.Fatalf("unexpected error: %v", event.Err)
+				case event.Result != nil:
+					results++
+				}
+			}
+			if changed != tc.want || results != tc.want {
+				t.Fatalf("changed = %d, results = %d, want %d", changed, results, tc.want)
+			}
+			if images, _ := calls.counts(); images != tc.want {
+				t.Fatalf("vision calls = %d, want %d", images, tc.want)
+			}
+		})
+	}
+}
+
+func TestWatchRegionRetriesFailedTranslation(t *testing.T) {
+	calls := visionCalls{fail: 1}
+	opts := WatchOptions{Interval: 5 * time.Millisecond, Threshold: 4, Debounce: 20 * time.Millisecond}
+	events := watchEvents(t, hold(watchFrame(0), 30), opts, &calls)
+
+	var changed, failed, results int
+	for _, event := range events {
+		switch {
+		case event.Changed:
+			changed++
+		case event.Err != nil:
+			failed++
+		case event.Result != nil:
+			results++
+		}
+	}
+	if changed != 2 || failed != 1 || results != 1 {
+		t.Fatalf("changed = %d, failed = %d, results = %d, want 2, 1, 1", changed, failed, results)
+	}
+}
@@ -1,9 +1,20 @@
 package overlay
 
 // Rect describes the target area to place the overlay window on screen.
+// Coordinates are physical pixels in the virtual desktop, the same space the
+// mouse hook and the capturer use.
 type Rect struct {
 	Left   int
 	Top    int
 	Width  int
 	Height int
+	// Scale is the DPI scale factor of the monitor the area sits on (1.5 for 150%).
+	// Fonts and padding are sized for it; zero falls back to the system DPI.
+	Scale float64
+}
+
+// Block is a piece of text drawn over its own screen area in a block overlay.
+type Block struct {
+	Text string
+	Rect Rect
 }
@@ -11,8 +11,14 @@ func NewManager() *Manager { return &Manager{} }
 // Show is a stub on non-Windows platforms.
 func (m *Manager) Show(_ string, _ Rect) error { return nil }
 
+// ShowBlocks is a stub on non-Windows platforms.
+func (m *Manager) ShowBlocks(_ Rect, _ []Block) error { return nil }
+
 // Update is a stub on non-Windows platforms.
 func (m *Manager) Update(_ string) error { return nil }
 
+// Append is a stub on non-Windows platforms.
+func (m *Manager) Append(_ string) error { return nil }
+
 // Close is a stub on non-Windows platforms.
 func (m *Manager) Close() {}
@@ -17,10 +17,15 @@ import (
 const (
 	windowClassName = "TranslaterOverlayWindow"
 	windowPadding   = 14
+	lwaColorKey     = 0x01
 	lwaAlpha        = 0x02
 	vkEscape        = 0x1B
 	escHotkeyID     = 0x4F52
 	textFitPadding  = 4
+	blockPadding    = 4
+	blockMaxPoint   = 72
+	// wdaExcludeFromCapture hides the window from screen capture APIs (Windows 10 2004+).
+	wdaExcludeFromCapture = 0x11
 )
 
 var (
@@ -35,6 +40,9 @@ var (
 	procRegisterHotKey             = user32.NewProc("RegisterHotKey")
 	procUnregisterHotKey           = user32.NewProc("UnregisterHotKey")
 	procShowScrollBar              = user32.NewProc("ShowScrollBar")
+	procSetWindowDisplayAffinity   = user32.NewProc("SetWindowDisplayAffinity")
+	// blockColorKey is painted outside blocks and made fully transparent, so clicks pass through to the window below.
+	blockColorKey = win.RGB(255, 0, 255)
 )
 
 // Manager coordinates overlay window lifecycle on Windows.
@@ -71,6 +79,30 @@ func (m *Manager) Show(text string, rect Rect) error {
 	return nil
 }
 
+// ShowBlocks replaces the current overlay with a single window covering area that draws
+// each block over its own rect, leaving the space between blocks transparent.
+func (m *Manager) ShowBlocks(area Rect, blocks []Block) error {
+	if len(blocks) == 0 {
+		m.Close()
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current != nil {
+		m.current.Close()
+		m.current = nil
+	}
+
+	ow, err := newBlockOverlayWindow(area, blocks)
+	if err != nil {
+		return err
+	}
+	m.current = ow
+	return nil
+}
+
 // Close closes the current overlay window if any.
 func (m *Manager) Close() {
 	m.mu.Lock()
@@ -96,6 +128,20 @@ func (m *Manager) Update(text string) error {
 	return m.current.UpdateText(text)
 }
 
+// Append appends streamed text to the current overlay window.
+func (m *Manager) Append(text string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return fmt.Errorf("overlay window is not active")
+	}
+	if text == "" {
+		return nil
+	}
+	return m.current.AppendText(text)
+}
+
 type overlayWindow struct {
 	baseRect  Rect
 	rect      Rect
@@ -113,6 +159,15 @@ type overlayWindow struct {
 	scrollPos      int32
 	viewHeight     int32
 	contentHeight  int32
+
+	// blocks is set for block overlays, which draw each block instead of textUTF16.
+	blocks []overlayBlock
+}
+
+type overlayBlock struct {
+	rect win.RECT // relative to the window
+	text []uint16
+	font win.HFONT
 }
 
 func newOverlayWindow(text string, rect Rect) (*overlayWindow, error) {
@@ -123,15 +178,50 @@ func newOverlayWindow(text string, rect Rect) (*overlayWindow, error) {
 		ready:     make(chan error, 1),
 		closed:    make(chan struct{}),
 	}
+	if err := ow.start(); err != nil {
+		return nil, err
+	}
+	return ow, nil
+}
 
+func newBlockOverlayWindow(area Rect, blocks []Block) (*overlayWindow, error) {
+	ow := &overlayWindow{
+		baseRect: area,
+		rect:     area,
+		ready:    make(chan error, 1),
+		closed:   make(chan struct{}),
+	}
+	for _, block := range blocks {
+		if strings.TrimSpace(block.Text) == "" {
+			continue
+		}
+		ow.blocks = append(ow.blocks, overlayBlock{
+			rect: win.RECT{
+				Left:   int32(block.Rect.Left - area.Left),
+				Top:    int32(block.Rect.Top - area.Top),
+				Right:  int32(block.Rect.Left - area.Left + rectSafeDimension(block.Rect.Width)),
+				Bottom: int32(block.Rect.Top - area.Top + rectSafeDimension(block.Rect.Height)),
+			},
+			text: append(utf16.Encode([]rune(block.Text)), 0),
+		})
+	}
+	if len(ow.blocks) == 0 {
+		return nil, fmt.Errorf("overlay blocks are empty")
+	}
+	if err := ow.start(); err != nil {
+		return nil, err
+	}
+	return ow, nil
+}
+
+func (ow *overlayWindow) start() error {
 	go ow.loop()
 
 	if err := <-ow.ready; err != nil {
 		<-ow.closed
-		return nil, err
+		return err
 	}
-
-	return ow, nil
+	return nil
 }
 
 func (ow *overlayWindow) UpdateText(text string) error {
@@ -151,6 +241,27 @@ func (ow *overlayWindow) UpdateText(text string) error {
 	return nil
 }
 
+// AppendText appends text without re-encoding the existing content.
+func (ow *overlayWindow) AppendText(text string) error {
+	if ow == nil {
+		return fmt.Errorf("overlay window is nil")
+	}
+	if ow.hwnd == 0 {
+		return fmt.Errorf("overlay window not initialized")
+	}
+
+	if n := len(ow.textUTF16); n > 0 && ow.textUTF16[n-1] == 0 {
+		ow.textUTF16 = ow.textUTF16[:n-1]
+	}
+	ow.textUTF16 = append(append(ow.textUTF16, utf16.Encode([]rune(text))...), 0)
+	ow.fontRectWidth = 0
+	ow.fontRectHeight = 0
+	ow.contentHeight = 0
+	ow.updateLayout()
+	ow.requestRepaint()
+	return nil
+}
+
 func (ow *overlayWindow) Close() {
 	if ow == nil {
 		return
@@ -200,11 +311,16 @@ func (ow *overlayWindow) createWindow() error {
 	width := int32(rectSafeDimension(ow.rect.Width))
 	height := int32(rectSafeDimension(ow.rect.Height))
 
+	style := uint32(win.WS_POPUP | win.WS_VSCROLL)
+	if len(ow.blocks) > 0 {
+		style = win.WS_POPUP
+	}
+
 	hwnd := win.CreateWindowEx(
 		win.WS_EX_TOPMOST|win.WS_EX_TOOLWINDOW|win.WS_EX_LAYERED|win.WS_EX_NOACTIVATE,
 		classNameUTF16,
 		nil,
-		win.WS_POPUP|win.WS_VSCROLL,
+		style,
 		int32(ow.rect.Left),
 		int32(ow.rect.Top),
 		width,
@@ -220,9 +336,16 @@ func (ow *overlayWindow) createWindow() error {
 
 	ow.hwnd = hwnd
 
-	if err := setLayeredWindowAttributes(hwnd, 0, 240, lwaAlpha); err != nil {
+	if len(ow.blocks) > 0 {
+		if err := setLayeredWindowAttributes(hwnd, uint32(blockColorKey), 240, lwaAlpha|lwaColorKey); err != nil {
+			return err
+		}
+	} else if err := setLayeredWindowAttributes(hwnd, 0, 240, lwaAlpha); err != nil {
 		return err
 	}
+	// Keep overlays out of our own captures, so a watched region never sees its translation.
+	// Older systems reject the flag; the overlay still works there, it just shows up in captures.
+	procSetWindowDisplayAffinity.Call(uintptr(hwnd), wdaExcludeFromCapture)
 	ow.registerEscapeHotkey()
 	ow.updateLayout()
 	win.ShowWindow(hwnd, win.SW_SHOWNOACTIVATE)
@@ -336,6 +459,14 @@ func (ow *overlayWindow) onPaint(hwnd win.HWND) {
 		}()
 	}
 
+	if len(ow.blocks) > 0 {
+		ow.paintBlocks(targetDC, &rect)
+		if useBuffer {
+			win.BitBlt(hdc, 0, 0, width, height, targetDC, 0, 0, win.SRCCOPY)
+		}
+		return
+	}
+
 	bgBrush, err := createSolidBrush(win.RGB(20, 24, 32))
 	if err != nil {
 		return
@@ -344,7 +475,7 @@ func (ow *overlayWindow) onPaint(hwnd win.HWND) {
 	fillRect(targetDC, &rect, bgBrush)
 
 	inner := rect
-	pad := int32(windowPadding)
+	pad := ow.scaled(windowPadding)
 	if inner.Right-inner.Left > pad*2 {
 		inner.Left += pad
 		inner.Right -= pad
@@ -394,8 +525,120 @@ func (ow *overlayWindow) onPaint(hwnd win.HWND) {
 	}
 }
 
+// paintBlocks fills the window with the color key and draws every block on an opaque background.
+func (ow *overlayWindow) paintBlocks(hdc win.HDC, client *win.RECT) {
+	keyBrush, err := createSolidBrush(blockColorKey)
+	if err != nil {
+		return
+	}
+	defer win.DeleteObject(win.HGDIOBJ(keyBrush))
+	fillRect(hdc, client, keyBrush)
+
+	bgBrush, err := createSolidBrush(win.RGB(20, 24, 32))
+	if err != nil {
+		return
+	}
+	defer win.DeleteObject(win.HGDIOBJ(bgBrush))
+
+	logPixelsY := ow.logPixelsY(hdc)
+
+	win.SetBkMode(hdc, win.TRANSPARENT)
+	win.SetTextColor(hdc, win.RGB(240, 247, 255))
+	for i := range ow.blocks {
+		block := &ow.blocks[i]
+		if block.font == 0 {
+			block.font = fitBlockFont(hdc, block, logPixelsY, client.Bottom)
+		}
+		fillRect(hdc, &block.rect, bgBrush)
+		if block.font == 0 {
+			continue
+		}
+		inner := insetRect(block.rect, blockPadding)
+		previous := win.SelectObject(hdc, win.HGDIOBJ(block.font))
+		win.DrawTextEx(hdc, &block.text[0], -1, &inner, win.DT_LEFT|win.DT_WORDBREAK|win.DT_NOPREFIX, nil)
+		win.SelectObject(hdc, previous)
+	}
+}
+
+// fitBlockFont picks the largest font that fits the block. When even the smallest font
+// overflows, the block grows downwards (up to maxBottom) so the translation stays readable.
+func fitBlockFont(hdc win.HDC, block *overlayBlock, logPixelsY int32, maxBottom int32) win.HFONT {
+	inner := insetRect(block.rect, blockPadding)
+	width := int(inner.Right - inner.Left)
+	height := int(inner.Bottom - inner.Top)
+	if width <= 0 || height <= 0 {
+		return 0
+	}
+
+	minPoint := 8
+	maxPoint := maxInt(minPoint, height*72/int(logPixelsY))
+	if maxPoint > blockMaxPoint {
+		maxPoint = blockMaxPoint
+	}
+
+	var best win.HFONT
+	for minPoint <= maxPoint {
+		mid := (minPoint + maxPoint) / 2
+		font := createFontForPoint(logPixelsY, mid)
+		if font == 0 {
+			break
+		}
+		if _, textHeight := measureBlockText(hdc, font, block.text, width); textHeight <= height {
+			if best != 0 {
+				win.DeleteObject(win.HGDIOBJ(best))
+			}
+			best = font
+			minPoint = mid + 1
+		} else {
+			win.DeleteObject(win.HGDIOBJ(font))
+			maxPoint = mid - 1
+		}
+	}
+	if best != 0 {
+		return best
+	}
+
+	best = createFontForPoint(logPixelsY, 8)
+	if _, textHeight := measureBlockText(hdc, best, block.text, width); textHeight > height {
+		bottom := block.rect.Top + int32(textHeight) + blockPadding*2
+		if bottom > maxBottom {
+			bottom = maxBottom
+		}
+		if bottom > block.rect.Bottom {
+			block.rect.Bottom = bottom
+		}
+	}
+	return best
+}
+
+func measureBlockText(hdc win.HDC, font win.HFONT, text []uint16, width int) (int, int) {
+	if font == 0 || width <= 0 || len(text) == 0 {
+		return 0, 0
+	}
+	prev := win.SelectObject(hdc, win.HGDIOBJ(font))
+	defer win.SelectObject(hdc, prev)
+
+	calcRect := win.RECT{Left: 0, Top: 0, Right: int32(width), Bottom: 0}
+	flags := uint32(win.DT_LEFT | win.DT_WORDBREAK | win.DT_NOPREFIX | win.DT_CALCRECT)
+	win.DrawTextEx(hdc, &text[0], -1, &calcRect, flags, nil)
+	return int(calcRect.Right - calcRect.Left), int(calcRect.Bottom - calcRect.Top)
+}
+
+func insetRect(rect win.RECT, pad int32) win.RECT {
+	if rect.Right-rect.Left > pad*2 {
+		rect.Left += pad
+		rect.Right -= pad
+	}
+	if rect.Bottom-rect.Top > pad*2 {
+		rect.Top += pad
+		rect.Bottom -= pad
+	}
+	return rect
+}
+
 func (ow *overlayWindow) updateLayout() {
-	if ow.hwnd == 0 {
+	// Block overlays keep the window at the captured area; each block is laid out when painted.
+	if ow.hwnd == 0 || len(ow.blocks) > 0 {
 		return
 	}
 	hdc := win.GetDC(ow.hwnd)
@@ -404,7 +647,7 @@ func (ow *overlayWindow) updateLayout() {
 	}
 	defer win.ReleaseDC(ow.hwnd, hdc)
 
-	pad := int32(windowPadding)
+	pad := ow.scaled(windowPadding)
 	maxOuterWidth := int32(rectSafeDimension(ow.baseRect.Width))
 	maxOuterHeight := int32(rectSafeDimension(ow.baseRect.Height))
 	innerMaxWidth := maxOuterWidth - pad*2
@@ -741,10 +984,7 @@ func (ow *overlayWindow) ensureFittingFont(hdc win.HDC, target *win.RECT) win.HF
 		ow.font = 0
 	}
 
-	logPixelsY := win.GetDeviceCaps(hdc, win.LOGPIXELSY)
-	if logPixelsY <= 0 {
-		logPixelsY = 96
-	}
+	logPixelsY := ow.logPixelsY(hdc)
 
 	minPoint := 8
 	maxPoint := maxInt(minPoint, int(availableHeight)*72/int(logPixelsY))
@@ -824,6 +1064,26 @@ func (ow *overlayWindow) textFits(hdc win.HDC, font win.HFONT, width, height int
 	return requiredWidth <= allowedWidth && requiredHeight <= allowedHeight
 }
 
+// logPixelsY returns the DPI fonts are sized for. GetDeviceCaps only knows the system DPI,
+// which is wrong for an overlay on a monitor scaled differently from the primary one.
+func (ow *overlayWindow) logPixelsY(hdc win.HDC) int32 {
+	if ow.rect.Scale > 0 {
+		return int32(96*ow.rect.Scale + 0.5)
+	}
+	if logPixelsY := win.GetDeviceCaps(hdc, win.LOGPIXELSY); logPixelsY > 0 {
+		return logPixelsY
+	}
+	return 96
+}
+
+// scaled converts a length given at 100% scale into pixels on the overlay's monitor.
+func (ow *overlayWindow) scaled(value int32) int32 {
+	if ow.rect.Scale <= 0 {
+		return value
+	}
+	return int32(float64(value)*ow.rect.Scale + 0.5)
+}
+
 func createFontForPoint(logPixelsY int32, pointSize int) win.HFONT {
 	if pointSize < 1 {
 		pointSize = 1
@@ -856,6 +1116,12 @@ func (ow *overlayWindow) cleanup() {
 	ow.fontRectWidth = 0
 	ow.fontRectHeight = 0
 	ow.fontPointSize = 0
+	for i := range ow.blocks {
+		if ow.blocks[i].font != 0 {
+			win.DeleteObject(win.HGDIOBJ(ow.blocks[i].font))
+			ow.blocks[i].font = 0
+		}
+	}
 	if ow.escHotkey {
 		procUnregisterHotKey.Call(uintptr(ow.hwnd), escHotkeyID)
 		ow.escHotkey = false
@@ -0,0 +1,132 @@
+package usage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 预算指标名称
+const (
+	MetricRequestsPerDay = "requestsPerDay"
+	MetricTokensPerDay   = "tokensPerDay"
+	MetricCostPerMonth   = "costPerMonth"
+)
+
+// ErrBudgetExceeded 表示已达到硬性预算上限，可通过 errors.Is 判断
+var ErrBudgetExceeded = errors.New("budget exceeded")
+
+// Limits 描述一组用量上限，0 表示不限制
+type Limits struct {
+	RequestsPerDay int     `json:"requestsPerDay"`
+	TokensPerDay   int     `json:"tokensPerDay"`
+	CostPerMonth   float64 `json:"costPerMonth"`
+}
+
+// Budget 包含软上限（仅提醒）与硬上限（阻止调用）
+type Budget struct {
+	Soft Limits `json:"soft"`
+	Hard Limits `json:"hard"`
+}
+
+// BudgetWarning 描述一次达到软上限的提醒
+type BudgetWarning struct {
+	Metric string  `json:"metric"`
+	Used   float64 `json:"used"`
+	Limit  float64 `json:"limit"`
+}
+
+// Message 返回面向用户的提醒文本
+func (w BudgetWarning) Message() string {
+	return fmt.Sprintf("%s已达 %s，超过提醒阈值 %s", metricLabel(w.Metric), formatMetric(w.Metric, w.Used), formatMetric(w.Metric, w.Limit))
+}
+
+// BudgetExceededError 表示某项指标达到硬上限
+type BudgetExceededError struct {
+	Metric string
+	Used   float64
+	Limit  float64
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("budget exceeded: %s used %v of %v", e.Metric, e.Used, e.Limit)
+}
+
+// Is 使 errors.Is(err, ErrBudgetExceeded) 成立
+func (e *BudgetExceededError) Is(target error) bool {
+	return target == ErrBudgetExceeded
+}
+
+// UserMessage 返回面向用户的中文提示
+func (e *BudgetExceededError) UserMessage() string {
+	return fmt.Sprintf("%s已达上限 %s（当前 %s），翻译已暂停，可在设置中调整预算", metricLabel(e.Metric), formatMetric(e.Metric, e.Limit), formatMetric(e.Metric, e.Used))
+}
+
+// IsZero 报告预算是否未设置任何上限
+func (b Budget) IsZero() bool {
+	return b.Soft == (Limits{}) && b.Hard == (Limits{})
+}
+
+// Check 根据当日与当月用量检查预算，硬上限返回 *BudgetExceededError，软上限返回提醒列表
+func (b Budget) Check(daily, monthly Summary) ([]BudgetWarning, error) {
+	used := map[string]float64{
+		MetricRequestsPerDay: float64(daily.Totals.Requests),
+		MetricTokensPerDay:   float64(daily.Totals.TotalTokens),
+		MetricCostPerMonth:   monthly.Totals.Cost,
+	}
+
+	for _, limit := range b.Hard.entries() {
+		if limit.value > 0 && used[limit.metric] >= limit.value {
+			return nil, &BudgetExceededError{Metric: limit.metric, Used: used[limit.metric], Limit: limit.value}
+		}
+	}
+
+	var warnings []BudgetWarning
+	for _, limit := range b.Soft.entries() {
+		if limit.value > 0 && used[limit.metric] >= limit.value {
+			warnings = append(warnings, BudgetWarning{Metric: limit.metric, Used: used[limit.metric], Limit: limit.value})
+		}
+	}
+	return warnings, nil
+}
+
+// CheckBudget 使用账本中 now 所在日、月的用量检查预算
+func (l *Ledger) CheckBudget(budget Budget, now time.Time) ([]BudgetWarning, error) {
+	if budget.IsZero() {
+		return nil, nil
+	}
+	return budget.Check(l.Daily(now), l.Monthly(now))
+}
+
+type limitEntry struct {
+	metric string
+	value  float64
+}
+
+func (l Limits) entries() []limitEntry {
+	return []limitEntry{
+		{metric: MetricRequestsPerDay, value: float64(l.RequestsPerDay)},
+		{metric: MetricTokensPerDay, value: float64(l.TokensPerDay)},
+		{metric: MetricCostPerMonth, value: l.CostPerMonth},
+	}
+}
+
+func metricLabel(metric string) string {
+	switch metric {
+	case MetricRequestsPerDay:
+		return "今日请求数"
+	case MetricTokensPerDay:
+		return "今日 token 用量"
+	case MetricCostPerMonth:
+		return "本月费用"
+	default:
+		return metric
+	}
+}
+
+func formatMetric(metric string, value float64) string {
+	if metric == MetricCostPerMonth {
+		return fmt.Sprintf("%.2f", value)
+	}
+	return fmt.Sprintf("%.0f", value)
+}
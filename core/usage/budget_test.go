@@ -0,0 +1,66 @@
+package usage
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBudgetCheck(t *testing.T) {
+	daily := Summary{Totals: Totals{Requests: 10, TotalTokens: 5000, Cost: 0.5}}
+	monthly := Summary{Totals: Totals{Requests: 200, TotalTokens: 90000, Cost: 12}}
+
+	cases := []struct {
+		name         string
+		budget       Budget
+		wantMetric   string
+		wantWarnings []string
+	}{
+		{name: "no limits", budget: Budget{}},
+		{name: "below hard limit", budget: Budget{Hard: Limits{RequestsPerDay: 11}}},
+		{name: "hard limit reached exactly", budget: Budget{Hard: Limits{RequestsPerDay: 10}}, wantMetric: MetricRequestsPerDay},
+		{name: "hard token limit exceeded", budget: Budget{Hard: Limits{TokensPerDay: 4000}}, wantMetric: MetricTokensPerDay},
+		{name: "cost uses monthly totals", budget: Budget{Hard: Limits{CostPerMonth: 10}}, wantMetric: MetricCostPerMonth},
+		{name: "cost below monthly limit", budget: Budget{Hard: Limits{CostPerMonth: 20}}},
+		{name: "soft limit only warns", budget: Budget{Soft: Limits{RequestsPerDay: 10}}, wantWarnings: []string{MetricRequestsPerDay}},
+		{
+			name:         "several soft limits",
+			budget:       Budget{Soft: Limits{RequestsPerDay: 5, TokensPerDay: 6000, CostPerMonth: 1}},
+			wantWarnings: []string{MetricRequestsPerDay, MetricCostPerMonth},
+		},
+		{
+			name:       "hard limit wins over soft",
+			budget:     Budget{Soft: Limits{RequestsPerDay: 1}, Hard: Limits{RequestsPerDay: 10}},
+			wantMetric: MetricRequestsPerDay,
+		},
+		{name: "zero limits are ignored", budget: Budget{Soft: Limits{TokensPerDay: 0}, Hard: Limits{RequestsPerDay: 0, CostPerMonth: 0}}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			warnings, err := tc.budget.Check(daily, monthly)
+			if tc.wantMetric != "" {
+				var exceeded *BudgetExceededError
+				if !errors.As(err, &exceeded) || exceeded.Metric != tc.wantMetric {
+					t.Fatalf("err = %v, want %s exceeded", err, tc.wantMetric)
+				}
+				if !errors.Is(err, ErrBudgetExceeded) {
+					t.Fatal("errors.Is(err, ErrBudgetExceeded) = false")
+				}
+				if warnings != nil {
+					t.Fatalf("warnings = %v, want none with hard limit", warnings)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if len(warnings) != len(tc.wantWarnings) {
+				t.Fatalf("warnings = %+v, want %v", warnings, tc.wantWarnings)
+			}
+			for i, warning := range warnings {
+				if warning.Metric != tc.wantWarnings[i] {
+					t.Fatalf("warning %d = %s, want %s", i, warning.Metric, tc.wantWarnings[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"Translater/core/ai"
+)
+
+// LedgerFileName 是用量账本在配置目录下的文件名
+const LedgerFileName = "usage.jsonl"
+
+// Price 描述某个模型每百万 token 的单价
+type Price struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// Record 表示一次模型调用的用量记录
+type Record struct {
+	Time             time.Time `json:"time"`
+	Endpoint         string    `json:"endpoint"`
+	Provider         string    `json:"provider,omitempty"`
+	Model            string    `json:"model"`
+	Stage            string    `json:"stage"`
+	PromptTokens     int       `json:"promptTokens"`
+	CompletionTokens int       `json:"completionTokens"`
+	TotalTokens      int       `json:"totalTokens"`
+	Cost             float64   `json:"cost"`
+	// UsageMissing 标记服务商未返回用量的调用，其 token 与费用记为 0
+	UsageMissing bool `json:"usageMissing,omitempty"`
+}
+
+// Totals 汇总一组记录的请求数、token 与费用
+type Totals struct {
+	Requests         int     `json:"requests"`
+	PromptTokens     int     `json:"promptTokens"`
+	CompletionTokens int     `json:"completionTokens"`
+	TotalTokens      int     `json:"totalTokens"`
+	Cost             float64 `json:"cost"`
+	// MissingUsage 为未返回用量的请求数，这些请求的 token 与费用未计入
+	MissingUsage int `json:"missingUsage"`
+}
+
+// ModelTotals 为按模型与接口拆分的汇总
+type ModelTotals struct {
+	Endpoint string `json:"endpoint"`
+	Model    string `json:"model"`
+	Totals
+}
+
+// Summary 表示某个时间区间内的用量汇总
+type Summary struct {
+	From   time.Time     `json:"from"`
+	To     time.Time     `json:"to"`
+	Totals Totals        `json:"totals"`
+	Models []ModelTotals `json:"models"`
+}
+
+// Ledger 记录模型调用用量并持久化到配置目录
+type Ledger struct {
+	mu      sync.RWMutex
+	path    string
+	prices  map[string]Price
+	records []Record
+}
+
+// NewLedger 创建账本并加载 dir 下已有的记录
+func NewLedger(dir string) (*Ledger, error) {
+	ledger := &Ledger{
+		path:   filepath.Join(dir, LedgerFileName),
+		prices: map[string]Price{},
+	}
+	if err := ledger.load(); err != nil {
+		return ledger, err
+	}
+	return ledger, nil
+}
+
+// Path 返回账本文件路径
+func (l *Ledger) Path() string {
+	return l.path
+}
+
+// SetPrices 更新模型价格表，键为模型名（不区分大小写），"*" 作为缺省价格
+func (l *Ledger) SetPrices(prices map[string]Price) {
+	normalized := make(map[string]Price, len(prices))
+	for model, price := range prices {
+		key := strings.ToLower(strings.TrimSpace(model))
+		if key == "" {
+			continue
+		}
+		normalized[key] = price
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.prices = normalized
+}
+
+// Observe 适配 ai.ClientConfig.OnUsage，将调用用量写入账本
+func (l *Ledger) Observe(event ai.UsageEvent) {
+	record := Record{
+		Time:             event.Time,
+		Endpoint:         event.Endpoint,
+		Provider:         event.Provider,
+		Model:            event.Model,
+		Stage:            event.Stage,
+		PromptTokens:     event.Usage.PromptTokens,
+		CompletionTokens: event.Usage.CompletionTokens,
+		TotalTokens:      event.Usage.TotalTokens,
+		UsageMissing:     event.UsageMissing,
+	}
+	if record.UsageMissing {
+		fmt.Printf("接口 %s（%s）未返回用量，本次调用按 0 token 记录\n", record.Endpoint, record.Model)
+	}
+	if err := l.Record(record); err != nil {
+		fmt.Printf("记录用量失败: %v\n", err)
+	}
+}
+
+// Record 计算费用后追加一条记录
+func (l *Ledger) Record(record Record) error {
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+	if record.TotalTokens == 0 {
+		record.TotalTokens = record.PromptTokens + record.CompletionTokens
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	record.Cost = l.costLocked(record)
+	l.records = append(l.records, record)
+	return l.appendLocked(record)
+}
+
+// Daily 返回 day 所在自然日（本地时区）的用量
+func (l *Ledger) Daily(day time.Time) Summary {
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	return l.Range(from, from.AddDate(0, 0, 1))
+}
+
+// Monthly 返回 month 所在自然月（本地时区）的用量
+func (l *Ledger) Monthly(month time.Time) Summary {
+	from := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	return l.Range(from, from.AddDate(0, 1, 0))
+}
+
+// DailyHistory 返回截至 until 的最近 days 天逐日用量，按日期升序
+func (l *Ledger) DailyHistory(until time.Time, days int) []Summary {
+	if days <= 0 {
+		return nil
+	}
+	last := time.Date(until.Year(), until.Month(), until.Day(), 0, 0, 0, 0, until.Location())
+	history := make([]Summary, 0, days)
+	for offset := days - 1; offset >= 0; offset-- {
+		history = append(history, l.Daily(last.AddDate(0, 0, -offset)))
+	}
+	return history
+}
+
+// Range 返回 [from, to) 区间内的用量
+func (l *Ledger) Range(from, to time.Time) Summary {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	summary := Summary{From: from, To: to}
+	byModel := map[string]*ModelTotals{}
+	for _, record := range l.records {
+		if record.Time.Before(from) || !record.Time.Before(to) {
+			continue
+		}
+		summary.Totals.add(record)
+
+		key := record.Endpoint + "\x00" + record.Model
+		entry, ok := byModel[key]
+		if !ok {
+			entry = &ModelTotals{Endpoint: record.Endpoint, Model: record.Model}
+			byModel[key] = entry
+		}
+		entry.Totals.add(record)
+	}
+
+	for _, entry := range byModel {
+		summary.Models = append(summary.Models, *entry)
+	}
+	sort.Slice(summary.Models, func(i, j int) bool {
+		if summary.Models[i].Endpoint != summary.Models[j].Endpoint {
+			return summary.Models[i].Endpoint < summary.Models[j].Endpoint
+		}
+		return summary.Models[i].Model < summary.Models[j].Model
+	})
+	return summary
+}
+
+func (t *Totals) add(record Record) {
+	t.Requests++
+	t.PromptTokens += record.PromptTokens
+	t.CompletionTokens += record.CompletionTokens
+	t.TotalTokens += record.TotalTokens
+	t.Cost += record.Cost
+	if record.UsageMissing {
+		t.MissingUsage++
+	}
+}
+
+func (l *Ledger) costLocked(record Record) float64 {
+	price, ok := l.prices[strings.ToLower(record.Model)]
+	if !ok {
+		price, ok = l.prices["*"]
+	}
+	if !ok {
+		return 0
+	}
+	return (float64(record.PromptTokens)*price.Input + float64(record.CompletionTokens)*price.Output) / 1_000_000
+}
+
+func (l *Ledger) load() error {
+	file, err := os.Open(l.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			// 跳过损坏的行，避免单条记录导致账本不可用
+			continue
+		}
+		l.records = append(l.records, record)
+	}
+	return scanner.Err()
+}
+
+func (l *Ledger) appendLocked(record Record) error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
@@ -2,17 +2,28 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"image"
+	"io"
+	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"Translater/core/ai"
+	"Translater/core/cache"
 	"Translater/core/config"
+	"Translater/core/display"
 	"Translater/core/hotkey"
+	"Translater/core/imageproc"
 	"Translater/core/screenshot"
+	"Translater/core/setup"
 	"Translater/core/translation"
 	"Translater/core/ui/overlay"
+	"Translater/core/usage"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
@@ -25,10 +36,20 @@ const (
 	eventTranslationError    = "translation:error"
 	eventTranslationIdle     = "translation:idle"
 	eventTranslationCopied   = "translation:copied"
+	eventBudgetWarning       = "budget:warning"
 	eventSettingsTheme       = "settings:theme"
 	eventSettingsUpdated     = "settings:updated"
 	eventConfigMissingKey    = "config:missing_api_key"
 	eventConfigReady         = "config:api_key_ready"
+	eventWatchStarted        = "watch:started"
+	eventWatchStopped        = "watch:stopped"
+)
+
+const (
+	// listModelsTimeout 限制获取模型列表的总耗时
+	listModelsTimeout = 15 * time.Second
+	// connectionProbeTimeout 限制单个接口连接测试的总耗时
+	connectionProbeTimeout = 45 * time.Second
 )
 
 // App 提供给 Wails 的后端逻辑
@@ -37,37 +58,48 @@ type App struct {
 
 	settingsManager *config.SettingsManager
 	settings        config.Settings
+	usageLedger     *usage.Ledger
+	resultCache     *cache.Cache
 
 	translationSvc        translation.Service
 	screenshotMgr         *screenshot.Manager
+	capturer              screenshot.Capturer
+	currentCaptureBackend string
 	currentAPIKey         string
 	currentBaseURL        string
 	currentTranslateModel string
 	currentVisionModel    string
 	currentVisionAPIKey   string
 	currentVisionBaseURL  string
+	currentProvider       string
+	currentVisionProvider string
+	currentFallbacks      [2][]ai.EndpointConfig
+	currentTimeouts       ai.Timeouts
+	currentBlockPolicy    [2]bool
 	streamMutex           sync.Mutex
 	streamActive          bool
 	streamSource          string
 	streamRect            overlay.Rect
 	streamHasRect         bool
 	streamOverlayVisible  bool
-	screenshotLocker      sync.Mutex
-	screenshotActive      bool
-	screenshotDone        chan struct{}
-	hotkeyMgr             *hotkey.Manager
-	hotkeyMutex           sync.Mutex
-	hotkeyLoopOnce        sync.Once
-	hotkeyRegistered      bool
-	hotkeyID              uintptr
-	currentHotkeyCombo    string
-	overlayMgr            *overlay.Manager
+	// streamReuseOverlay 为 true 时首段内容直接替换已有浮窗的文字，而不是重新创建窗口
+	streamReuseOverlay bool
+	// streamText 累计本次流式输出的文本，仅用于首次展示浮窗
+	streamText        strings.Builder
+	screenshotLocker  sync.Mutex
+	screenshotActive  bool
+	screenshotDone    chan struct{}
+	imageLocker       sync.Mutex
+	hotkeyMgr         *hotkey.Manager
+	hotkeyMutex       sync.Mutex
+	hotkeyLoopOnce    sync.Once
+	registeredHotkeys map[uintptr]hotkeyBinding
+	overlayMgr        *overlay.Manager
 }
 
 // NewApp creates a new App application struct
 func NewApp() *App {
 	return &App{
-		hotkeyID:   1,
 		overlayMgr: overlay.NewManager(),
 	}
 }
@@ -92,11 +124,185 @@ func (a *App) startup(ctx context.Context) {
 
 // StartScreenshotTranslation 触发一次截图翻译流程
 func (a *App) StartScreenshotTranslation() error {
+	return a.startCapture("请按下鼠标左键拖拽选择需要翻译的区域，按 Esc 取消", nil, false)
+}
+
+// CaptureMonitor 截取第 index 个显示器（从 0 开始）的完整画面并翻译
+func (a *App) CaptureMonitor(index int) error {
+	bounds, err := screenshot.MonitorBounds(index)
+	if err != nil {
+		return a.captureError(err)
+	}
+	return a.startCapture(fmt.Sprintf("正在截取显示器 %d，按 Esc 取消", index+1), &bounds, false)
+}
+
+// CaptureLastRegion 重新截取上一次使用的区域并翻译，无需拖拽
+func (a *App) CaptureLastRegion() error {
+	region, err := a.lastRegion()
+	if err != nil {
+		return a.captureError(err)
+	}
+	return a.startCapture("正在重新截取上一次的区域，按 Esc 取消", &region, false)
+}
+
+// CaptureRegion 截取设置中名为 name 的固定区域并翻译
+func (a *App) CaptureRegion(name string) error {
+	region, err := a.namedRegion(name)
+	if err != nil {
+		return a.captureError(err)
+	}
+	return a.startCapture(fmt.Sprintf("正在截取区域「%s」，按 Esc 取消", strings.TrimSpace(name)), &region, false)
+}
+
+// WatchLastRegion 持续监视上一次使用的区域，内容变化时自动翻译，直到调用 StopWatch 或按 Esc
+func (a *App) WatchLastRegion() error {
+	region, err := a.lastRegion()
+	if err != nil {
+		return a.captureError(err)
+	}
+	return a.startCapture("正在监视上一次的区域，按 Esc 停止", &region, true)
+}
+
+// WatchRegion 持续监视设置中名为 name 的固定区域，内容变化时自动翻译
+func (a *App) WatchRegion(name string) error {
+	region, err := a.namedRegion(name)
+	if err != nil {
+		return a.captureError(err)
+	}
+	return a.startCapture(fmt.Sprintf("正在监视区域「%s」，按 Esc 停止", strings.TrimSpace(name)), &region, true)
+}
+
+// StopWatch 停止当前的区域监视（或其他进行中的截图任务）
+func (a *App) StopWatch() {
+	if a.screenshotMgr != nil {
+		a.screenshotMgr.CancelActiveCapture()
+	}
+}
+
+// TranslateImage 翻译拖放或选择的图片文件，data 为 base64 编码的文件内容或 data URL，
+// 支持 PNG、JPEG、GIF、BMP 与 WebP，mimeType 为空时按内容识别格式
+func (a *App) TranslateImage(data string, mimeType string) error {
+	payload := strings.TrimSpace(data)
+	if strings.HasPrefix(payload, "data:") {
+		header, encoded, ok := strings.Cut(payload, ",")
+		if !ok {
+			return a.captureError(errors.New("图片数据格式无效"))
+		}
+		if strings.TrimSpace(mimeType) == "" {
+			mimeType = strings.TrimSuffix(strings.TrimPrefix(header, "data:"), ";base64")
+		}
+		payload = encoded
+	}
+	decoded, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return a.captureError(fmt.Errorf("图片数据解码失败: %w", err))
+	}
+	return a.translateImage(decoded, mimeType)
+}
+
+// TranslateClipboardImage 翻译剪贴板中的图片
+func (a *App) TranslateClipboardImage() error {
+	data, mimeType, err := readClipboardImage()
+	if err != nil {
+		return a.captureError(err)
+	}
+	return a.translateImage(data, mimeType)
+}
+
+// translateImage 翻译导入的图片并向前端发送结果。图片没有对应的屏幕位置，因此不显示浮窗
+func (a *App) translateImage(data []byte, mimeType string) error {
 	if err := a.ensureService(); err != nil {
-		a.emit(eventTranslationError, map[string]string{
-			"stage":   "init",
-			"message": err.Error(),
+		return a.captureError(err)
+	}
+	if _, err := a.translationSvc.CheckBudget(); err != nil {
+		a.emit(eventTranslationError, describeError("budget", err))
+		return err
+	}
+	if !a.imageLocker.TryLock() {
+		return a.captureError(errors.New("上一张图片仍在翻译中，请稍候"))
+	}
+	defer a.imageLocker.Unlock()
+	defer a.emit(eventTranslationIdle, nil)
+
+	ctx := a.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	streamEnabled := a.settings.EnableStreamOutput
+	a.emit(eventTranslationStarted, map[string]string{"source": "image"})
+	if streamEnabled {
+		a.beginStream("image", nil)
+	}
+	a.emit(eventTranslationProgress, map[string]string{
+		"stage":   "ocr",
+		"message": "正在识别图片中的文字…",
+	})
+
+	result, err := a.translationSvc.TranslateImage(ctx, data, mimeType)
+	a.presentScreenshotResult(result, err, nil, streamEnabled)
+	return err
+}
+
+func (a *App) lastRegion() (image.Rectangle, error) {
+	if err := a.ensureService(); err != nil {
+		return image.Rectangle{}, err
+	}
+	region, ok := a.screenshotMgr.LastRegion()
+	if !ok {
+		return image.Rectangle{}, screenshot.ErrNoLastRegion
+	}
+	return region, nil
+}
+
+func (a *App) namedRegion(name string) (image.Rectangle, error) {
+	for _, region := range a.settings.CaptureRegions {
+		if strings.EqualFold(region.Name, strings.TrimSpace(name)) {
+			return image.Rect(region.Left, region.Top, region.Left+region.Width, region.Top+region.Height), nil
+		}
+	}
+	return image.Rectangle{}, fmt.Errorf("未找到名为「%s」的截图区域", name)
+}
+
+// ListMonitors 返回当前活动显示器及其在虚拟桌面中的物理像素范围与缩放比例
+func (a *App) ListMonitors() []MonitorDTO {
+	topology, err := display.Current()
+	if err != nil {
+		a.logError(fmt.Sprintf("获取显示器布局失败: %v", err))
+		return []MonitorDTO{}
+	}
+	monitors := make([]MonitorDTO, 0, len(topology.Monitors))
+	for _, monitor := range topology.Monitors {
+		monitors = append(monitors, MonitorDTO{
+			Index:   monitor.Index,
+			Left:    monitor.Bounds.Min.X,
+			Top:     monitor.Bounds.Min.Y,
+			Width:   monitor.Bounds.Dx(),
+			Height:  monitor.Bounds.Dy(),
+			Scale:   monitor.Scale,
+			Primary: monitor.Primary,
 		})
+	}
+	return monitors
+}
+
+// captureError 通知前端截图无法开始并返回原错误
+func (a *App) captureError(err error) error {
+	a.emit(eventTranslationError, map[string]string{
+		"stage":   "init",
+		"message": err.Error(),
+	})
+	return err
+}
+
+// startCapture 启动一次截图翻译。region 为 nil 时由用户拖拽选区，否则直接截取给定区域；
+// watch 为 true 时持续监视该区域
+func (a *App) startCapture(prompt string, region *image.Rectangle, watch bool) error {
+	if err := a.ensureService(); err != nil {
+		return a.captureError(err)
+	}
+
+	if _, err := a.translationSvc.CheckBudget(); err != nil {
+		a.emit(eventTranslationError, describeError("budget", err))
 		return err
 	}
 
@@ -112,7 +318,7 @@ func (a *App) StartScreenshotTranslation() error {
 				a.overlayMgr.Close()
 			}
 
-			go a.runScreenshotCapture(done)
+			go a.runScreenshotCapture(done, prompt, region, watch)
 			return nil
 		}
 		a.screenshotLocker.Unlock()
@@ -124,7 +330,7 @@ func (a *App) StartScreenshotTranslation() error {
 	}
 }
 
-func (a *App) runScreenshotCapture(done chan struct{}) {
+func (a *App) runScreenshotCapture(done chan struct{}, prompt string, region *image.Rectangle, watch bool) {
 	defer func() {
 		a.screenshotLocker.Lock()
 		if a.screenshotDone == done {
@@ -139,10 +345,18 @@ func (a *App) runScreenshotCapture(done chan struct{}) {
 	a.emit(eventTranslationStarted, map[string]string{"source": "screenshot"})
 	a.emit(eventTranslationProgress, map[string]string{
 		"stage":   "prepare",
-		"message": "请按下鼠标左键拖拽选择需要翻译的区域，按 Esc 取消",
+		"message": prompt,
 	})
 
-	if a.screenshotMgr != nil {
+	if a.screenshotMgr == nil {
+		return
+	}
+	switch {
+	case region != nil && watch:
+		a.screenshotMgr.StartWatch(*region)
+	case region != nil:
+		a.screenshotMgr.StartRegion(*region)
+	default:
 		a.screenshotMgr.StartOnce()
 	}
 }
@@ -213,6 +427,38 @@ type UITranslationResult struct {
 	Timestamp      time.Time           `json:"timestamp"`
 	DurationMs     int64               `json:"durationMs"`
 	Bounds         *UIScreenshotBounds `json:"bounds,omitempty"`
+	CacheHit       bool                `json:"cacheHit,omitempty"`
+	ServedBy       []UIServedBy        `json:"servedBy,omitempty"`
+	// ExtractReasoning 与 Reasoning 为推理模型的思考过程，仅在开启 showReasoning 时返回
+	ExtractReasoning string `json:"extractReasoning,omitempty"`
+	Reasoning        string `json:"reasoning,omitempty"`
+	// Continuations 为自动续写次数，Truncated 表示译文仍被 max_tokens 截断
+	Continuations int  `json:"continuations,omitempty"`
+	Truncated     bool `json:"truncated,omitempty"`
+	// Background 为提取阶段给出的场景描述，RawExtractedText 为提取阶段的原始输出，便于排查识别问题
+	Background       string `json:"background,omitempty"`
+	RawExtractedText string `json:"rawExtractedText,omitempty"`
+	// Blocks 为版面分块模式下逐块的原文、译文与屏幕坐标
+	Blocks []UITranslationBlock `json:"blocks,omitempty"`
+}
+
+// UITranslationBlock 为一个文本块的原文与译文，Left/Top/Width/Height 为屏幕坐标
+type UITranslationBlock struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Left   int    `json:"left"`
+	Top    int    `json:"top"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// UIServedBy 描述某个阶段实际应答的接口，用于展示是否启用了备用模型
+type UIServedBy struct {
+	Stage    string `json:"stage"`
+	Provider string `json:"provider"`
+	BaseURL  string `json:"baseUrl"`
+	Model    string `json:"model"`
+	Fallback int    `json:"fallback"`
 }
 
 // UIScreenshotBounds 将截图范围暴露给前端用于定位浮窗
@@ -225,27 +471,303 @@ type UIScreenshotBounds struct {
 	Top    int `json:"top"`
 	Width  int `json:"width"`
 	Height int `json:"height"`
+	// Scale 为区域所在显示器的缩放比例，以上坐标均为物理像素
+	Scale float64 `json:"scale"`
 }
 
 // SettingsDTO 前端-后端交互的配置载体
 type SettingsDTO struct {
-	APIKeyOverride          string `json:"apiKeyOverride"`
-	AutoCopyResult          bool   `json:"autoCopyResult"`
-	KeepWindowOnTop         bool   `json:"keepWindowOnTop"`
-	Theme                   string `json:"theme"`
-	ShowToastOnComplete     bool   `json:"showToastOnComplete"`
-	EnableStreamOutput      bool   `json:"enableStreamOutput"`
-	HotkeyCombination       string `json:"hotkeyCombination"`
-	ExtractPrompt           string `json:"extractPrompt"`
-	TranslatePrompt         string `json:"translatePrompt"`
-	APIBaseURL              string `json:"apiBaseUrl"`
-	TranslateModel          string `json:"translateModel"`
-	VisionModel             string `json:"visionModel"`
-	VisionAPIBaseURL        string `json:"visionApiBaseUrl"`
-	VisionAPIKeyOverride    string `json:"visionApiKeyOverride"`
-	UseVisionForTranslation bool   `json:"useVisionForTranslation"`
-	SourceLanguage          string `json:"sourceLanguage"`
-	TargetLanguage          string `json:"targetLanguage"`
+	APIKeyOverride          string                   `json:"apiKeyOverride"`
+	AutoCopyResult          bool                     `json:"autoCopyResult"`
+	KeepWindowOnTop         bool                     `json:"keepWindowOnTop"`
+	Theme                   string                   `json:"theme"`
+	ShowToastOnComplete     bool                     `json:"showToastOnComplete"`
+	EnableStreamOutput      bool                     `json:"enableStreamOutput"`
+	PipelineTranslation     bool                     `json:"pipelineTranslation"`
+	ShowReasoning           bool                     `json:"showReasoning"`
+	AutoContinue            bool                     `json:"autoContinue"`
+	MaxContinuations        int                      `json:"maxContinuations"`
+	DetectRefusals          bool                     `json:"detectRefusals"`
+	FallbackOnBlocked       bool                     `json:"fallbackOnBlocked"`
+	HotkeyCombination       string                   `json:"hotkeyCombination"`
+	RepeatHotkeyCombination string                   `json:"repeatHotkeyCombination"`
+	CaptureRegions          []CaptureRegionDTO       `json:"captureRegions"`
+	Watch                   WatchDTO                 `json:"watch"`
+	ExtractPrompt           string                   `json:"extractPrompt"`
+	TranslatePrompt         string                   `json:"translatePrompt"`
+	APIBaseURL              string                   `json:"apiBaseUrl"`
+	TranslateModel          string                   `json:"translateModel"`
+	VisionModel             string                   `json:"visionModel"`
+	VisionAPIBaseURL        string                   `json:"visionApiBaseUrl"`
+	VisionAPIKeyOverride    string                   `json:"visionApiKeyOverride"`
+	TranslateProvider       string                   `json:"translateProvider"`
+	VisionProvider          string                   `json:"visionProvider"`
+	UseVisionForTranslation bool                     `json:"useVisionForTranslation"`
+	LayoutBlocks            bool                     `json:"layoutBlocks"`
+	SourceLanguage          string                   `json:"sourceLanguage"`
+	TargetLanguage          string                   `json:"targetLanguage"`
+	CaptureBackend          string                   `json:"captureBackend"`
+	ModelPrices             map[string]ModelPriceDTO `json:"modelPrices"`
+	Budget                  BudgetDTO                `json:"budget"`
+	Cache                   CacheDTO                 `json:"cache"`
+	TranslateFallbacks      []FallbackEndpointDTO    `json:"translateFallbacks"`
+	VisionFallbacks         []FallbackEndpointDTO    `json:"visionFallbacks"`
+	Sampling                SamplingDTO              `json:"sampling"`
+	Timeouts                TimeoutsDTO              `json:"timeouts"`
+	Image                   ImageDTO                 `json:"image"`
+}
+
+// SamplingDTO 描述提取、翻译与视觉直出三个阶段的采样参数
+type SamplingDTO struct {
+	Extract   StageSamplingDTO `json:"extract"`
+	Translate StageSamplingDTO `json:"translate"`
+	Direct    StageSamplingDTO `json:"direct"`
+}
+
+// StageSamplingDTO 描述单个阶段的采样参数，留空的字段沿用默认值
+type StageSamplingDTO struct {
+	Temperature      *float64               `json:"temperature,omitempty"`
+	TopP             *float64               `json:"topP,omitempty"`
+	MaxTokens        int                    `json:"maxTokens"`
+	PresencePenalty  *float64               `json:"presencePenalty,omitempty"`
+	FrequencyPenalty *float64               `json:"frequencyPenalty,omitempty"`
+	Seed             *int64                 `json:"seed,omitempty"`
+	Stop             []string               `json:"stop"`
+	ExtraBody        map[string]interface{} `json:"extraBody"`
+}
+
+// FallbackEndpointDTO 描述一个备用接口
+type FallbackEndpointDTO struct {
+	BaseURL  string `json:"baseUrl"`
+	APIKey   string `json:"apiKey"`
+	Model    string `json:"model"`
+	Provider string `json:"provider"`
+}
+
+// CaptureRegionDTO 描述一个命名的固定截图区域
+type CaptureRegionDTO struct {
+	Name   string `json:"name"`
+	Left   int    `json:"left"`
+	Top    int    `json:"top"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Hotkey string `json:"hotkey"`
+}
+
+// WatchDTO 描述区域监视模式的轮询间隔、变化阈值与稳定时长
+type WatchDTO struct {
+	IntervalMs int `json:"intervalMs"`
+	Threshold  int `json:"threshold"`
+	DebounceMs int `json:"debounceMs"`
+}
+
+// MonitorDTO 描述一个显示器在虚拟桌面中的范围
+type MonitorDTO struct {
+	Index   int     `json:"index"`
+	Left    int     `json:"left"`
+	Top     int     `json:"top"`
+	Width   int     `json:"width"`
+	Height  int     `json:"height"`
+	Scale   float64 `json:"scale"`
+	Primary bool    `json:"primary"`
+}
+
+// CacheDTO 描述翻译结果缓存配置
+type CacheDTO struct {
+	Enabled   bool `json:"enabled"`
+	TTLHours  int  `json:"ttlHours"`
+	MaxSizeMB int  `json:"maxSizeMb"`
+}
+
+// TimeoutsDTO 描述连接、首字、流式间隔与各阶段总时长的时限（秒）
+type TimeoutsDTO struct {
+	ConnectSeconds    int `json:"connectSeconds"`
+	FirstTokenSeconds int `json:"firstTokenSeconds"`
+	IdleSeconds       int `json:"idleSeconds"`
+	CaptureSeconds    int `json:"captureSeconds"`
+	OCRSeconds        int `json:"ocrSeconds"`
+	TranslateSeconds  int `json:"translateSeconds"`
+}
+
+// ImageDTO 描述截图上传前的预处理设置
+type ImageDTO struct {
+	MaxLongEdge int        `json:"maxLongEdge"`
+	Format      string     `json:"format"`
+	JPEGQuality int        `json:"jpegQuality"`
+	Grayscale   bool       `json:"grayscale"`
+	MaxKB       int        `json:"maxKb"`
+	Enhance     EnhanceDTO `json:"enhance"`
+}
+
+// EnhanceDTO 描述截图的文字增强步骤
+type EnhanceDTO struct {
+	TrimBorders  bool `json:"trimBorders"`
+	InvertDark   bool `json:"invertDark"`
+	AutoContrast bool `json:"autoContrast"`
+	Sharpen      bool `json:"sharpen"`
+	UpscaleBelow int  `json:"upscaleBelow"`
+	MaxUpscale   int  `json:"maxUpscale"`
+}
+
+// BudgetDTO 描述用量预算的软/硬上限
+type BudgetDTO struct {
+	Soft BudgetLimitsDTO `json:"soft"`
+	Hard BudgetLimitsDTO `json:"hard"`
+}
+
+// BudgetLimitsDTO 描述一组用量上限，0 表示不限制
+type BudgetLimitsDTO struct {
+	RequestsPerDay int     `json:"requestsPerDay"`
+	TokensPerDay   int     `json:"tokensPerDay"`
+	CostPerMonth   float64 `json:"costPerMonth"`
+}
+
+// ModelCatalogDTO 汇总翻译接口与视觉接口的可用模型
+type ModelCatalogDTO struct {
+	Translate ModelListDTO `json:"translate"`
+	Vision    ModelListDTO `json:"vision"`
+}
+
+// ModelListDTO 描述单个接口的模型列表，Supported 为 false 时前端回退为手动输入
+type ModelListDTO struct {
+	Endpoint  string           `json:"endpoint"`
+	Provider  string           `json:"provider"`
+	BaseURL   string           `json:"baseUrl"`
+	Models    []ModelOptionDTO `json:"models"`
+	Supported bool             `json:"supported"`
+	Error     string           `json:"error"`
+}
+
+// ModelOptionDTO 描述一个可选模型
+type ModelOptionDTO struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// ConnectionReportDTO 汇总连接测试结果，视觉直出模式下不测试翻译接口
+type ConnectionReportDTO struct {
+	Vision    EndpointProbeDTO  `json:"vision"`
+	Translate *EndpointProbeDTO `json:"translate,omitempty"`
+}
+
+// EndpointProbeDTO 描述单个接口的连接测试结果
+type EndpointProbeDTO struct {
+	Endpoint     string `json:"endpoint"`
+	Provider     string `json:"provider"`
+	BaseURL      string `json:"baseUrl"`
+	Model        string `json:"model"`
+	OK           bool   `json:"ok"`
+	Auth         string `json:"auth"`
+	LatencyMs    int64  `json:"latencyMs"`
+	Reply        string `json:"reply"`
+	Error        string `json:"error"`
+	Streaming    bool   `json:"streaming"`
+	FirstTokenMs int64  `json:"firstTokenMs"`
+	StreamError  string `json:"streamError"`
+	// Images 为 nil 表示未测试或无法判断
+	Images *bool `json:"images,omitempty"`
+}
+
+// ModelPriceDTO 描述模型每百万 token 的单价
+type ModelPriceDTO struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// UsageReportDTO 汇总当日与当月的模型用量
+type UsageReportDTO struct {
+	Today UsagePeriodDTO `json:"today"`
+	Month UsagePeriodDTO `json:"month"`
+}
+
+// UsagePeriodDTO 表示某个时间区间内的用量
+type UsagePeriodDTO struct {
+	From             time.Time       `json:"from"`
+	To               time.Time       `json:"to"`
+	Requests         int             `json:"requests"`
+	PromptTokens     int             `json:"promptTokens"`
+	CompletionTokens int             `json:"completionTokens"`
+	TotalTokens      int             `json:"totalTokens"`
+	Cost             float64         `json:"cost"`
+	MissingUsage     int             `json:"missingUsage"`
+	Models           []UsageModelDTO `json:"models"`
+}
+
+// UsageModelDTO 表示按接口与模型拆分的用量
+type UsageModelDTO struct {
+	Endpoint         string  `json:"endpoint"`
+	Model            string  `json:"model"`
+	Requests         int     `json:"requests"`
+	PromptTokens     int     `json:"promptTokens"`
+	CompletionTokens int     `json:"completionTokens"`
+	TotalTokens      int     `json:"totalTokens"`
+	Cost             float64 `json:"cost"`
+}
+
+// GetUsageReport 返回当日与当月的用量汇总
+func (a *App) GetUsageReport() (*UsageReportDTO, error) {
+	if a.usageLedger == nil {
+		if err := a.initSettings(); err != nil {
+			return nil, err
+		}
+		if a.usageLedger == nil {
+			return nil, fmt.Errorf("用量账本未初始化")
+		}
+	}
+
+	now := time.Now()
+	return &UsageReportDTO{
+		Today: fromUsageSummary(a.usageLedger.Daily(now)),
+		Month: fromUsageSummary(a.usageLedger.Monthly(now)),
+	}, nil
+}
+
+// GetDailyUsage 返回最近 days 天的逐日用量
+func (a *App) GetDailyUsage(days int) ([]UsagePeriodDTO, error) {
+	if a.usageLedger == nil {
+		if err := a.initSettings(); err != nil {
+			return nil, err
+		}
+		if a.usageLedger == nil {
+			return nil, fmt.Errorf("用量账本未初始化")
+		}
+	}
+	if days <= 0 {
+		days = 7
+	}
+
+	history := a.usageLedger.DailyHistory(time.Now(), days)
+	periods := make([]UsagePeriodDTO, 0, len(history))
+	for _, summary := range history {
+		periods = append(periods, fromUsageSummary(summary))
+	}
+	return periods, nil
+}
+
+func fromUsageSummary(summary usage.Summary) UsagePeriodDTO {
+	period := UsagePeriodDTO{
+		From:             summary.From,
+		To:               summary.To,
+		Requests:         summary.Totals.Requests,
+		PromptTokens:     summary.Totals.PromptTokens,
+		CompletionTokens: summary.Totals.CompletionTokens,
+		TotalTokens:      summary.Totals.TotalTokens,
+		Cost:             summary.Totals.Cost,
+		MissingUsage:     summary.Totals.MissingUsage,
+		Models:           []UsageModelDTO{},
+	}
+	for _, model := range summary.Models {
+		period.Models = append(period.Models, UsageModelDTO{
+			Endpoint:         model.Endpoint,
+			Model:            model.Model,
+			Requests:         model.Requests,
+			PromptTokens:     model.PromptTokens,
+			CompletionTokens: model.CompletionTokens,
+			TotalTokens:      model.TotalTokens,
+			Cost:             model.Cost,
+		})
+	}
+	return period
 }
 
 func (a *App) initSettings() error {
@@ -262,6 +784,18 @@ func (a *App) initSettings() error {
 		return err
 	}
 	a.settings = settings
+
+	ledger, err := usage.NewLedger(manager.Dir())
+	if err != nil {
+		a.logError(fmt.Sprintf("加载用量账本失败: %v", err))
+	}
+	a.usageLedger = ledger
+
+	resultCache, err := cache.New(filepath.Join(manager.Dir(), cache.DirName), settings.Cache.Options())
+	if err != nil {
+		a.logError(fmt.Sprintf("加载结果缓存失败: %v", err))
+	}
+	a.resultCache = resultCache
 	return nil
 }
 
@@ -270,73 +804,66 @@ func (a *App) ensureService() error {
 		return err
 	}
 
-	// 使用新的 API Key 解析逻辑
-	mainKey, translateKey, err := a.resolveAPIKeys()
+	clientConfig, err := setup.ClientConfig(a.settings)
 	if err != nil {
 		a.disableHotkey()
 		return err
 	}
 
-	baseURL := ai.NormalizeBaseURL(a.settings.APIBaseURL)
-	translateModel := strings.TrimSpace(a.settings.TranslateModel)
-	if translateModel == "" {
-		translateModel = ai.DefaultTranslateModel
-	}
-	visionModel := strings.TrimSpace(a.settings.VisionModel)
-	if visionModel == "" {
-		visionModel = ai.DefaultVisionModel
-	}
+	options := setup.TranslationOptions(a.settings)
 
-	// 视觉 API 配置（使用主 key）
-	visionAPIKey := mainKey
-	visionBaseURL := strings.TrimSpace(a.settings.VisionAPIBaseURL)
-	if visionBaseURL == "" {
-		visionBaseURL = baseURL
-	} else {
-		visionBaseURL = ai.NormalizeBaseURL(visionBaseURL)
+	fallbacks := [2][]ai.EndpointConfig{clientConfig.TranslateFallbacks, clientConfig.VisionFallbacks}
+	fallbacksChanged := !slices.Equal(fallbacks[0], a.currentFallbacks[0]) || !slices.Equal(fallbacks[1], a.currentFallbacks[1])
+	blockPolicy := [2]bool{clientConfig.DetectRefusals, clientConfig.FallbackOnBlocked}
+
+	if a.usageLedger != nil {
+		a.usageLedger.SetPrices(a.settings.ModelPrices)
+		clientConfig.OnUsage = a.usageLedger.Observe
 	}
 
-	options := translation.Options{
-		Stream:                  a.settings.EnableStreamOutput,
-		UseVisionForTranslation: a.settings.UseVisionForTranslation,
-		SourceLanguage:          a.settings.SourceLanguage,
-		TargetLanguage:          a.settings.TargetLanguage,
+	captureChanged := a.capturer == nil || a.settings.CaptureBackend != a.currentCaptureBackend
+	if captureChanged {
+		a.replaceCapturer(a.settings.CaptureBackend)
 	}
 
-	if a.translationSvc == nil || translateKey != a.currentAPIKey || baseURL != a.currentBaseURL || translateModel != a.currentTranslateModel || visionModel != a.currentVisionModel || visionAPIKey != a.currentVisionAPIKey || visionBaseURL != a.currentVisionBaseURL {
+	if a.translationSvc == nil || captureChanged || clientConfig.APIKey != a.currentAPIKey || clientConfig.BaseURL != a.currentBaseURL || clientConfig.TranslateModel != a.currentTranslateModel || clientConfig.VisionModel != a.currentVisionModel || clientConfig.VisionAPIKey != a.currentVisionAPIKey || clientConfig.VisionBaseURL != a.currentVisionBaseURL || clientConfig.Provider != a.currentProvider || clientConfig.VisionProvider != a.currentVisionProvider || fallbacksChanged || clientConfig.Timeouts != a.currentTimeouts || blockPolicy != a.currentBlockPolicy {
 		a.translationSvc = translation.NewService(
-			ai.NewClient(ai.ClientConfig{
-				APIKey:         translateKey,
-				BaseURL:        baseURL,
-				TranslateModel: translateModel,
-				VisionModel:    visionModel,
-				VisionAPIKey:   visionAPIKey,
-				VisionBaseURL:  visionBaseURL,
-			}),
+			ai.NewClient(clientConfig),
+			a.capturer,
 			a.settings.ExtractPrompt,
 			a.settings.TranslatePrompt,
 			options,
 		)
-		a.currentAPIKey = translateKey
-		a.currentBaseURL = baseURL
-		a.currentTranslateModel = translateModel
-		a.currentVisionModel = visionModel
-		a.currentVisionAPIKey = visionAPIKey
-		a.currentVisionBaseURL = visionBaseURL
+		a.currentAPIKey = clientConfig.APIKey
+		a.currentBaseURL = clientConfig.BaseURL
+		a.currentTranslateModel = clientConfig.TranslateModel
+		a.currentVisionModel = clientConfig.VisionModel
+		a.currentVisionAPIKey = clientConfig.VisionAPIKey
+		a.currentVisionBaseURL = clientConfig.VisionBaseURL
+		a.currentProvider = clientConfig.Provider
+		a.currentVisionProvider = clientConfig.VisionProvider
+		a.currentFallbacks = fallbacks
+		a.currentTimeouts = clientConfig.Timeouts
+		a.currentBlockPolicy = blockPolicy
 	}
 
 	if a.translationSvc != nil {
 		a.translationSvc.UpdatePrompts(a.settings.ExtractPrompt, a.settings.TranslatePrompt)
 		a.translationSvc.UpdateOptions(options)
 		a.translationSvc.SetStreamHandler(a.handleStreamDelta)
+		a.translationSvc.SetUsageLedger(a.usageLedger)
+		a.translationSvc.SetResultCache(a.activeResultCache())
+		a.translationSvc.SetBudgetWarningHandler(a.handleBudgetWarnings)
 	}
 
 	if a.screenshotMgr == nil {
 		a.screenshotMgr = screenshot.NewManager()
 		a.screenshotMgr.SetCaptureHandler(a.handleScreenshotCapture)
+		a.screenshotMgr.SetWatchHandler(a.handleRegionWatch)
 	} else {
 		// 确保 handler 持续引用最新的 service
 		a.screenshotMgr.SetCaptureHandler(a.handleScreenshotCapture)
+		a.screenshotMgr.SetWatchHandler(a.handleRegionWatch)
 	}
 
 	if err := a.ensureHotkeyListener(); err != nil {
@@ -346,6 +873,27 @@ func (a *App) ensureService() error {
 	return nil
 }
 
+// 全局热键 ID，第 i 个命名区域使用 regionHotkeyBase+i
+const (
+	mainHotkeyID     uintptr = 1
+	repeatHotkeyID   uintptr = 2
+	regionHotkeyBase uintptr = 100
+)
+
+// hotkeyBinding 为一个全局热键及其触发的截图动作
+type hotkeyBinding struct {
+	label     string
+	combo     string
+	modifiers uintptr
+	key       uintptr
+	action    func() error
+}
+
+// sameAs 判断两个绑定的名称与组合是否一致，一致时无需重新注册
+func (b hotkeyBinding) sameAs(other hotkeyBinding) bool {
+	return b.label == other.label && strings.EqualFold(b.combo, other.combo)
+}
+
 func (a *App) ensureHotkeyListener() error {
 	combo := strings.TrimSpace(a.settings.HotkeyCombination)
 	if combo == "" {
@@ -358,34 +906,86 @@ func (a *App) ensureHotkeyListener() error {
 		return err
 	}
 
-	canonical := hotkey.FormatCombination(modifiers, key)
+	bindings := map[uintptr]hotkeyBinding{
+		mainHotkeyID: {
+			label:     "截图翻译",
+			combo:     hotkey.FormatCombination(modifiers, key),
+			modifiers: modifiers,
+			key:       key,
+			action:    a.StartScreenshotTranslation,
+		},
+	}
+	owners := map[string]string{strings.ToLower(bindings[mainHotkeyID].combo): "截图翻译"}
+	// 附加热键解析失败或与已有热键冲突时仅记录日志，不影响主热键
+	addBinding := func(id uintptr, combo, label string, action func() error) {
+		if strings.TrimSpace(combo) == "" {
+			return
+		}
+		modifiers, key, err := hotkey.ParseCombination(combo)
+		if err != nil {
+			a.logError(fmt.Sprintf("热键「%s」无效: %v", label, err))
+			return
+		}
+		canonical := hotkey.FormatCombination(modifiers, key)
+		if owner, ok := owners[strings.ToLower(canonical)]; ok {
+			a.logError(fmt.Sprintf("热键 %s 已被「%s」占用，忽略「%s」", canonical, owner, label))
+			return
+		}
+		owners[strings.ToLower(canonical)] = label
+		bindings[id] = hotkeyBinding{label: label, combo: canonical, modifiers: modifiers, key: key, action: action}
+	}
+	addBinding(repeatHotkeyID, a.settings.RepeatHotkeyCombination, "重复上次区域", a.CaptureLastRegion)
+	for index, region := range a.settings.CaptureRegions {
+		name := region.Name
+		addBinding(regionHotkeyBase+uintptr(index), region.Hotkey, name, func() error {
+			return a.CaptureRegion(name)
+		})
+	}
 
 	a.hotkeyMutex.Lock()
 	defer a.hotkeyMutex.Unlock()
 
 	if a.hotkeyMgr == nil {
 		a.hotkeyMgr = hotkey.NewManager()
-		if a.hotkeyID == 0 {
-			a.hotkeyID = 1
-		}
+	}
+	if a.registeredHotkeys == nil {
+		a.registeredHotkeys = make(map[uintptr]hotkeyBinding)
 	}
 
-	if a.hotkeyRegistered {
-		if strings.EqualFold(a.currentHotkeyCombo, canonical) {
-			return nil
+	// 先注销已移除或发生变化的热键，避免新组合与旧注册冲突
+	for id, registered := range a.registeredHotkeys {
+		if binding, ok := bindings[id]; !ok || !binding.sameAs(registered) {
+			a.hotkeyMgr.Unregister(id)
+			delete(a.registeredHotkeys, id)
 		}
-		a.hotkeyMgr.Unregister(a.hotkeyID)
-		a.hotkeyRegistered = false
 	}
 
-	if err := a.hotkeyMgr.Register(a.hotkeyID, modifiers, key, func() {
-		go a.handleHotkeyTrigger()
-	}); err != nil {
-		return err
+	var mainErr error
+	for id, binding := range bindings {
+		if _, ok := a.registeredHotkeys[id]; ok {
+			continue
+		}
+		label, action := binding.label, binding.action
+		if err := a.hotkeyMgr.Register(id, binding.modifiers, binding.key, func() {
+			go func() {
+				if err := action(); err != nil {
+					a.logError(fmt.Sprintf("热键「%s」触发截图失败: %v", label, err))
+				}
+			}()
+		}); err != nil {
+			if id == mainHotkeyID {
+				mainErr = err
+			} else {
+				a.logError(fmt.Sprintf("注册热键「%s」失败: %v", label, err))
+			}
+			continue
+		}
+		a.registeredHotkeys[id] = binding
+	}
+	if mainErr != nil {
+		return mainErr
 	}
 
-	a.hotkeyRegistered = true
-	a.currentHotkeyCombo = canonical
 	a.hotkeyLoopOnce.Do(func() {
 		go a.hotkeyMgr.Start()
 	})
@@ -397,17 +997,12 @@ func (a *App) disableHotkey() {
 	a.hotkeyMutex.Lock()
 	defer a.hotkeyMutex.Unlock()
 
-	if a.hotkeyMgr != nil && a.hotkeyRegistered {
-		a.hotkeyMgr.Unregister(a.hotkeyID)
-		a.hotkeyRegistered = false
-	}
-	a.currentHotkeyCombo = ""
-}
-
-func (a *App) handleHotkeyTrigger() {
-	if err := a.StartScreenshotTranslation(); err != nil {
-		a.logError(fmt.Sprintf("热键触发截图失败: %v", err))
+	if a.hotkeyMgr != nil {
+		for id := range a.registeredHotkeys {
+			a.hotkeyMgr.Unregister(id)
+		}
 	}
+	a.registeredHotkeys = nil
 }
 
 func (a *App) computeOverlayRect(startX, startY, endX, endY int) overlay.Rect {
@@ -434,23 +1029,15 @@ func (a *App) computeOverlayRect(startX, startY, endX, endY int) overlay.Rect {
 		Top:    top,
 		Width:  width,
 		Height: height,
+		Scale:  display.ScaleFor(image.Rect(left, top, left+width, top+height)),
 	}
 }
 
 func (a *App) handleScreenshotCapture(ctx context.Context, startX, startY, endX, endY int) bool {
 	streamEnabled := a.settings.EnableStreamOutput
-	shouldCleanup := false
+	rect := a.computeOverlayRect(startX, startY, endX, endY)
 	if streamEnabled {
-		rect := a.computeOverlayRect(startX, startY, endX, endY)
 		a.beginStream("screenshot", &rect)
-		shouldCleanup = true
-		// 翻译完成后只清理流状态，不关闭overlay窗口
-		// overlay窗口由用户按ESC键手动关闭
-		defer func() {
-			if shouldCleanup {
-				a.endStream(false)
-			}
-		}()
 	}
 
 	a.emit(eventTranslationProgress, map[string]string{
@@ -459,29 +1046,98 @@ func (a *App) handleScreenshotCapture(ctx context.Context, startX, startY, endX,
 	})
 
 	result, err := a.translationSvc.ProcessScreenshotDetailedWithContext(ctx, startX, startY, endX, endY)
-	if err == context.Canceled {
-		if streamEnabled {
-			shouldCleanup = false
-		}
+	if errors.Is(err, context.Canceled) {
 		a.cancelActiveTranslation()
 		return false
 	}
+	return a.presentScreenshotResult(result, err, &rect, streamEnabled)
+}
+
+// handleRegionWatch 持续监视区域，内容变化时重新翻译并在同一浮窗中刷新译文，直到监视被取消
+func (a *App) handleRegionWatch(ctx context.Context, startX, startY, endX, endY int) bool {
+	streamEnabled := a.settings.EnableStreamOutput
+	rect := a.computeOverlayRect(startX, startY, endX, endY)
+	watching := map[string]string{
+		"stage":   "watch",
+		"message": "正在监视区域，内容变化时自动翻译，按 Esc 停止",
+	}
+	translated := false
+
+	a.emit(eventWatchStarted, map[string]int{
+		"left":   rect.Left,
+		"top":    rect.Top,
+		"width":  rect.Width,
+		"height": rect.Height,
+	})
+	defer a.emit(eventWatchStopped, nil)
+	a.emit(eventTranslationProgress, watching)
+
+	err := a.translationSvc.WatchRegion(ctx, startX, startY, endX, endY, watchOptions(a.settings.Watch), func(event translation.WatchEvent) {
+		if event.Changed {
+			if streamEnabled {
+				a.restartStream(rect)
+			}
+			a.emit(eventTranslationStarted, map[string]string{"source": "screenshot"})
+			a.emit(eventTranslationProgress, map[string]string{
+				"stage":   "ocr",
+				"message": "检测到内容变化，正在识别文字…",
+			})
+			return
+		}
+		if a.presentScreenshotResult(event.Result, event.Err, &rect, streamEnabled) {
+			translated = true
+		}
+		a.emit(eventTranslationProgress, watching)
+	})
+	if errors.Is(err, context.Canceled) {
+		a.cancelActiveTranslation()
+		return translated
+	}
 	if err != nil {
-		a.emit(eventTranslationError, map[string]string{
-			"stage":   "screenshot",
-			"message": err.Error(),
-		})
+		a.emit(eventTranslationError, describeError("screenshot", err))
+	}
+	return translated
+}
+
+// presentScreenshotResult 结束流式输出，向前端发送结果或错误并刷新浮窗，成功展示译文时返回 true。
+// 浮窗会一直保持显示，直到用户按 Esc 关闭；rect 为 nil 表示导入的图片，只向前端发送结果
+func (a *App) presentScreenshotResult(result *translation.ScreenshotTranslationResult, err error, rect *overlay.Rect, streamEnabled bool) bool {
+	source := "screenshot"
+	if rect == nil {
+		source = "image"
+	}
+	if streamEnabled {
+		a.endStream(false)
+	}
+	if err != nil {
+		payload := describeError(source, err)
+		// 被过滤或拒答时浮窗中的流式内容并非译文，替换为错误说明
+		if ai.IsBlocked(err) && rect != nil && a.overlayMgr != nil && a.isStreamOverlayActive() {
+			a.showStreamOverlay(payload["message"], *rect)
+		}
+		a.emit(eventTranslationError, payload)
 		// 不自动关闭overlay，让用户可以看到错误信息并手动关闭
 		return false
 	}
 
 	uiResult := &UITranslationResult{
-		OriginalText:   result.ExtractedText,
-		TranslatedText: result.TranslatedText,
-		Source:         "screenshot",
-		Timestamp:      time.Now(),
-		DurationMs:     result.ProcessingTime.Milliseconds(),
-		Bounds: &UIScreenshotBounds{
+		OriginalText:     result.ExtractedText,
+		TranslatedText:   result.TranslatedText,
+		Source:           source,
+		Timestamp:        time.Now(),
+		DurationMs:       result.ProcessingTime.Milliseconds(),
+		CacheHit:         result.CacheHit,
+		ServedBy:         servedByFromResult(result),
+		ExtractReasoning: result.ExtractReasoning,
+		Reasoning:        result.Reasoning,
+		Continuations:    result.Continuations,
+		Truncated:        result.Truncated,
+		Background:       result.Extract.Background,
+		RawExtractedText: result.RawExtractedText,
+		Blocks:           blocksFromResult(result.Blocks),
+	}
+	if rect != nil {
+		uiResult.Bounds = &UIScreenshotBounds{
 			StartX: result.Bounds.StartX,
 			StartY: result.Bounds.StartY,
 			EndX:   result.Bounds.EndX,
@@ -490,7 +1146,8 @@ func (a *App) handleScreenshotCapture(ctx context.Context, startX, startY, endX,
 			Top:    result.Bounds.Top,
 			Width:  result.Bounds.Width,
 			Height: result.Bounds.Height,
-		},
+			Scale:  result.Bounds.Scale,
+		}
 	}
 
 	// 检查是否有有效内容（ExtractedText 或 TranslatedText 至少有一个非空）
@@ -519,16 +1176,15 @@ func (a *App) handleScreenshotCapture(ctx context.Context, startX, startY, endX,
 		return false
 	}
 
+	completeMessage := "翻译完成"
+	if result.CacheHit {
+		completeMessage = "翻译完成（命中缓存）"
+	}
 	a.emit(eventTranslationProgress, map[string]string{
 		"stage":   "translate",
-		"message": "翻译完成",
+		"message": completeMessage,
 	})
 
-	if streamEnabled {
-		a.endStream(false)
-		shouldCleanup = false
-	}
-
 	a.emit(eventTranslationResult, uiResult)
 	a.postProcessTranslation(uiResult.TranslatedText)
 
@@ -539,8 +1195,17 @@ func (a *App) handleScreenshotCapture(ctx context.Context, startX, startY, endX,
 			Top:    uiResult.Bounds.Top,
 			Width:  uiResult.Bounds.Width,
 			Height: uiResult.Bounds.Height,
+			Scale:  uiResult.Bounds.Scale,
 		}
-		if streamEnabled && a.isStreamOverlayActive() {
+		if len(uiResult.Blocks) > 0 {
+			// 分块模式下逐块覆盖原文，替换整块区域的浮窗
+			if err := a.showBlockOverlay(rect, uiResult.Blocks); err != nil {
+				a.logError(fmt.Sprintf("展示分块翻译浮窗失败: %v", err))
+				if err := a.overlayMgr.Show(uiResult.TranslatedText, rect); err != nil {
+					a.logError(fmt.Sprintf("展示翻译浮窗失败: %v", err))
+				}
+			}
+		} else if streamEnabled && a.isStreamOverlayActive() {
 			// 流式模式下，更新已存在的overlay
 			if err := a.overlayMgr.Update(uiResult.TranslatedText); err != nil {
 				a.logError(fmt.Sprintf("更新流式翻译浮窗失败: %v", err))
@@ -560,6 +1225,136 @@ func (a *App) handleScreenshotCapture(ctx context.Context, startX, startY, endX,
 	return true
 }
 
+// activeResultCache 按配置返回可用的结果缓存，禁用时返回 nil
+func (a *App) activeResultCache() *cache.Cache {
+	if a.resultCache == nil || a.settings.Cache.Disabled {
+		return nil
+	}
+	a.resultCache.Configure(a.settings.Cache.Options())
+	return a.resultCache
+}
+
+// ClearResultCache 清空翻译结果缓存
+func (a *App) ClearResultCache() error {
+	if a.resultCache == nil {
+		return nil
+	}
+	return a.resultCache.Clear()
+}
+
+// TestConnection 使用设置面板中尚未保存的配置分别测试视觉与翻译接口，
+// 报告鉴权状态、延迟、流式输出与图像输入是否可用
+func (a *App) TestConnection(payload SettingsDTO) (*ConnectionReportDTO, error) {
+	settings := toConfigSettings(payload)
+	clientConfig, err := setup.ClientConfig(settings)
+	if err != nil {
+		return nil, err
+	}
+	client := ai.NewClient(clientConfig)
+
+	parent := a.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	probe := func(run func(context.Context) ai.ProbeResult) EndpointProbeDTO {
+		ctx, cancel := context.WithTimeout(parent, connectionProbeTimeout)
+		defer cancel()
+		return fromProbeResult(run(ctx))
+	}
+
+	report := &ConnectionReportDTO{Vision: probe(client.ProbeVision)}
+	if !settings.UseVisionForTranslation {
+		translate := probe(client.ProbeTranslate)
+		report.Translate = &translate
+	}
+	return report, nil
+}
+
+// ListModels 使用设置面板中尚未保存的接口配置获取可用模型，供模型名称下拉框使用
+func (a *App) ListModels(payload SettingsDTO) (*ModelCatalogDTO, error) {
+	clientConfig, err := setup.ClientConfig(toConfigSettings(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	parent := a.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, listModelsTimeout)
+	defer cancel()
+
+	catalog, err := ai.NewClient(clientConfig).ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &ModelCatalogDTO{
+		Translate: fromModelList(catalog.Translate),
+		Vision:    fromModelList(catalog.Vision),
+	}, nil
+}
+
+// handleBudgetWarnings 将达到软上限的预算提醒推送给前端
+func (a *App) handleBudgetWarnings(warnings []usage.BudgetWarning) {
+	for _, warning := range warnings {
+		a.emit(eventBudgetWarning, map[string]interface{}{
+			"metric":  warning.Metric,
+			"used":    warning.Used,
+			"limit":   warning.Limit,
+			"message": warning.Message(),
+		})
+	}
+}
+
+func servedByFromResult(result *translation.ScreenshotTranslationResult) []UIServedBy {
+	var served []UIServedBy
+	appendServed := func(stage string, info *ai.ServedBy) {
+		if info == nil {
+			return
+		}
+		served = append(served, UIServedBy{
+			Stage:    stage,
+			Provider: info.Provider,
+			BaseURL:  info.BaseURL,
+			Model:    info.Model,
+			Fallback: info.Fallback,
+		})
+	}
+	appendServed("vision", result.VisionServedBy)
+	appendServed("translate", result.TranslateServedBy)
+	return served
+}
+
+func blocksFromResult(blocks []translation.TranslatedBlock) []UITranslationBlock {
+	if len(blocks) == 0 {
+		return nil
+	}
+	converted := make([]UITranslationBlock, 0, len(blocks))
+	for _, block := range blocks {
+		converted = append(converted, UITranslationBlock{
+			Source: block.Source,
+			Target: block.Target,
+			Left:   block.Screen.Left,
+			Top:    block.Screen.Top,
+			Width:  block.Screen.Width,
+			Height: block.Screen.Height,
+		})
+	}
+	return converted
+}
+
+// showBlockOverlay 将每个文本块的译文覆盖在原文位置上
+func (a *App) showBlockOverlay(area overlay.Rect, blocks []UITranslationBlock) error {
+	items := make([]overlay.Block, 0, len(blocks))
+	for _, block := range blocks {
+		items = append(items, overlay.Block{
+			Text: block.Target,
+			Rect: overlay.Rect{Left: block.Left, Top: block.Top, Width: block.Width, Height: block.Height, Scale: area.Scale},
+		})
+	}
+	return a.overlayMgr.ShowBlocks(area, items)
+}
+
 func (a *App) postProcessTranslation(translated string) {
 	if !a.settings.AutoCopyResult || strings.TrimSpace(translated) == "" {
 		return
@@ -588,6 +1383,21 @@ func (a *App) beginStream(source string, rect *overlay.Rect) {
 		a.streamHasRect = false
 	}
 	a.streamOverlayVisible = false
+	a.streamReuseOverlay = false
+	a.streamText.Reset()
+}
+
+// restartStream 在监视模式下开始新一轮流式输出，已显示的浮窗会被复用，首段内容到达时整体替换
+func (a *App) restartStream(rect overlay.Rect) {
+	a.streamMutex.Lock()
+	reuse := a.streamOverlayVisible
+	a.streamMutex.Unlock()
+
+	a.beginStream("screenshot", &rect)
+
+	a.streamMutex.Lock()
+	a.streamReuseOverlay = reuse
+	a.streamMutex.Unlock()
 }
 
 func (a *App) endStream(closeOverlay bool) {
@@ -608,23 +1418,44 @@ func (a *App) endStream(closeOverlay bool) {
 	}
 }
 
-func (a *App) handleStreamDelta(stage string, content string) {
-	payload := map[string]string{
-		"stage":   stage,
-		"content": content,
+// handleStreamDelta 将增量推送给前端并追加到浮窗，前端负责拼接完整译文
+func (a *App) handleStreamDelta(stage string, event ai.StreamEvent) {
+	payload := map[string]interface{}{
+		"stage":  stage,
+		"delta":  event.Delta,
+		"length": event.Length,
+	}
+	if event.Reasoning != "" {
+		payload["reasoning"] = event.Reasoning
+	}
+	if event.FinishReason != "" {
+		payload["finishReason"] = event.FinishReason
+	}
+	if event.Usage != nil {
+		payload["usage"] = event.Usage
+	}
+	if event.Reset {
+		payload["reset"] = true
 	}
 
 	a.streamMutex.Lock()
+	if !a.streamActive {
+		a.streamMutex.Unlock()
+		return
+	}
 	source := a.streamSource
 	hasRect := a.streamHasRect
 	rect := a.streamRect
 	overlayVisible := a.streamOverlayVisible
-	active := a.streamActive
-	a.streamMutex.Unlock()
-
-	if !active {
-		return
+	if event.Reset {
+		a.streamText.Reset()
+	}
+	a.streamText.WriteString(event.Delta)
+	var snapshot string
+	if !overlayVisible || event.Reset {
+		snapshot = a.streamText.String()
 	}
+	a.streamMutex.Unlock()
 
 	if source != "" {
 		payload["source"] = source
@@ -634,32 +1465,52 @@ func (a *App) handleStreamDelta(stage string, content string) {
 	if source != "screenshot" || !hasRect || a.overlayMgr == nil {
 		return
 	}
-
-	if strings.TrimSpace(content) == "" {
+	if event.Reset && overlayVisible {
+		// 被拦截的内容已作废，用备用接口的输出替换浮窗内容
+		a.showStreamOverlay(snapshot, rect)
+		return
+	}
+	if event.Delta == "" {
 		return
 	}
 
 	if !overlayVisible {
-		if err := a.overlayMgr.Show(content, rect); err != nil {
-			a.logError(fmt.Sprintf("展示流式翻译浮窗失败: %v", err))
+		// 首段内容全为空白时暂不弹出浮窗
+		if strings.TrimSpace(snapshot) == "" {
 			return
 		}
-		a.streamMutex.Lock()
-		a.streamOverlayVisible = true
-		a.streamMutex.Unlock()
+		a.showStreamOverlay(snapshot, rect)
 		return
 	}
 
-	if err := a.overlayMgr.Update(content); err != nil {
+	if err := a.overlayMgr.Append(event.Delta); err != nil {
 		a.logError(fmt.Sprintf("更新流式翻译浮窗失败: %v", err))
-		if err := a.overlayMgr.Show(content, rect); err != nil {
-			a.logError(fmt.Sprintf("展示流式翻译浮窗失败: %v", err))
-			return
-		}
+		a.streamMutex.Lock()
+		snapshot = a.streamText.String()
+		a.streamMutex.Unlock()
+		a.showStreamOverlay(snapshot, rect)
+	}
+}
+
+func (a *App) showStreamOverlay(text string, rect overlay.Rect) {
+	a.streamMutex.Lock()
+	reuse := a.streamReuseOverlay
+	a.streamReuseOverlay = false
+	a.streamMutex.Unlock()
+
+	if reuse && a.overlayMgr.Update(text) == nil {
 		a.streamMutex.Lock()
 		a.streamOverlayVisible = true
 		a.streamMutex.Unlock()
+		return
+	}
+	if err := a.overlayMgr.Show(text, rect); err != nil {
+		a.logError(fmt.Sprintf("展示流式翻译浮窗失败: %v", err))
+		return
 	}
+	a.streamMutex.Lock()
+	a.streamOverlayVisible = true
+	a.streamMutex.Unlock()
 }
 
 func (a *App) isStreamOverlayActive() bool {
@@ -694,39 +1545,75 @@ func (a *App) shutdown(ctx context.Context) {
 	if a.overlayMgr != nil {
 		a.overlayMgr.Close()
 	}
+	a.replaceCapturer("")
 }
 
-// resolveAPIKeys 根据 useVisionForTranslation 设置解析主 API Key 和翻译 API Key
-// 主 API Key 优先从 visionApiKeyOverride 读取（向前兼容），翻译 API Key 根据模式决定
-func (a *App) resolveAPIKeys() (mainKey string, translateKey string, err error) {
-	// 1. 解析主 API Key（视觉 API Key 优先）
-	mainKey = strings.TrimSpace(a.settings.VisionAPIKeyOverride)
-	if mainKey == "" {
-		// 向后兼容：回退到 apiKeyOverride
-		mainKey = strings.TrimSpace(a.settings.APIKeyOverride)
+// replaceCapturer 关闭旧的截图后端并按名称创建新后端，创建失败时回退到默认后端；
+// backend 为空表示仅释放当前后端
+func (a *App) replaceCapturer(backend string) {
+	if closer, ok := a.capturer.(io.Closer); ok {
+		closer.Close()
 	}
-	if mainKey == "" {
-		// 最后尝试从文件读取
-		reader := config.NewFileAPIKeyReader([]string{".env", "env", "../.env", "../env"})
-		mainKey, err = reader.ReadAPIKey()
-		if err != nil || mainKey == "" {
-			return "", "", fmt.Errorf("需要配置视觉 API Key (visionApiKeyOverride) 或在 .env 文件中设置")
-		}
+	a.capturer = nil
+	a.currentCaptureBackend = backend
+	if backend == "" {
+		return
 	}
 
-	// 2. 解析翻译 API Key
-	if a.settings.UseVisionForTranslation {
-		// 视觉直出模式：翻译也用主 key
-		translateKey = mainKey
-	} else {
-		// 文本模型模式：翻译 key 可选，留空则回退到主 key
-		translateKey = strings.TrimSpace(a.settings.APIKeyOverride)
-		if translateKey == "" {
-			translateKey = mainKey
+	capturer, err := screenshot.NewCapturer(backend)
+	if err != nil {
+		a.logError(fmt.Sprintf("截图后端 %s 初始化失败，使用默认后端: %v", backend, err))
+		capturer = screenshot.DefaultCapturer
+	}
+	a.capturer = capturer
+}
+
+// watchOptions 将区域监视设置转换为翻译服务的监视参数
+func watchOptions(watch config.WatchSettings) translation.WatchOptions {
+	return translation.WatchOptions{
+		Interval:  time.Duration(watch.IntervalMs) * time.Millisecond,
+		Threshold: watch.Threshold,
+		Debounce:  time.Duration(watch.DebounceMs) * time.Millisecond,
+	}
+}
+
+// describeError 将错误转换为前端事件载荷，模型接口错误会替换为可读提示并附带分类
+func describeError(stage string, err error) map[string]string {
+	payload := map[string]string{
+		"stage":   stage,
+		"message": err.Error(),
+	}
+
+	var budgetErr *usage.BudgetExceededError
+	if errors.As(err, &budgetErr) {
+		payload["message"] = budgetErr.UserMessage()
+		payload["category"] = "budget"
+		payload["detail"] = err.Error()
+		return payload
+	}
+
+	// 超时错误保留已收到的部分译文，前端据此展示部分结果
+	var timeoutErr *ai.TimeoutError
+	if errors.As(err, &timeoutErr) {
+		payload["message"] = timeoutErr.UserMessage()
+		payload["category"] = "timeout"
+		payload["detail"] = err.Error()
+		if strings.TrimSpace(timeoutErr.Partial) != "" {
+			payload["partialText"] = timeoutErr.Partial
 		}
+		return payload
 	}
 
-	return mainKey, translateKey, nil
+	var requestErr *ai.RequestError
+	if errors.As(err, &requestErr) {
+		payload["message"] = requestErr.UserMessage()
+		payload["category"] = string(requestErr.Category)
+		payload["detail"] = err.Error()
+		if requestErr.RequestID != "" {
+			payload["requestId"] = requestErr.RequestID
+		}
+	}
+	return payload
 }
 
 func (a *App) emit(event string, payload interface{}) {
@@ -751,7 +1638,14 @@ func fromConfigSettings(settings config.Settings) SettingsDTO {
 		Theme:                   settings.Theme,
 		ShowToastOnComplete:     settings.ShowToastOnComplete,
 		EnableStreamOutput:      settings.EnableStreamOutput,
+		PipelineTranslation:     settings.PipelineTranslation,
+		ShowReasoning:           settings.ShowReasoning,
+		AutoContinue:            settings.AutoContinue,
+		MaxContinuations:        settings.MaxContinuations,
+		DetectRefusals:          settings.DetectRefusals,
+		FallbackOnBlocked:       settings.FallbackOnBlocked,
 		HotkeyCombination:       settings.HotkeyCombination,
+		RepeatHotkeyCombination: settings.RepeatHotkeyCombination,
 		ExtractPrompt:           settings.ExtractPrompt,
 		TranslatePrompt:         settings.TranslatePrompt,
 		APIBaseURL:              settings.APIBaseURL,
@@ -759,10 +1653,233 @@ func fromConfigSettings(settings config.Settings) SettingsDTO {
 		VisionModel:             settings.VisionModel,
 		VisionAPIBaseURL:        settings.VisionAPIBaseURL,
 		VisionAPIKeyOverride:    settings.VisionAPIKeyOverride,
+		TranslateProvider:       settings.TranslateProvider,
+		VisionProvider:          settings.VisionProvider,
 		UseVisionForTranslation: settings.UseVisionForTranslation,
+		LayoutBlocks:            settings.LayoutBlocks,
 		SourceLanguage:          settings.SourceLanguage,
 		TargetLanguage:          settings.TargetLanguage,
+		CaptureBackend:          settings.CaptureBackend,
+		ModelPrices:             fromModelPrices(settings.ModelPrices),
+		Budget: BudgetDTO{
+			Soft: fromBudgetLimits(settings.Budget.Soft),
+			Hard: fromBudgetLimits(settings.Budget.Hard),
+		},
+		CaptureRegions:     fromCaptureRegions(settings.CaptureRegions),
+		TranslateFallbacks: fromFallbacks(settings.TranslateFallbacks),
+		VisionFallbacks:    fromFallbacks(settings.VisionFallbacks),
+		Cache: CacheDTO{
+			Enabled:   !settings.Cache.Disabled,
+			TTLHours:  settings.Cache.TTLHours,
+			MaxSizeMB: settings.Cache.MaxSizeMB,
+		},
+		Sampling: SamplingDTO{
+			Extract:   fromSampling(settings.Sampling.Extract),
+			Translate: fromSampling(settings.Sampling.Translate),
+			Direct:    fromSampling(settings.Sampling.Direct),
+		},
+		Timeouts: TimeoutsDTO(settings.Timeouts),
+		Watch:    WatchDTO(settings.Watch),
+		Image:    fromImageSettings(settings.Image),
+	}
+}
+
+func fromImageSettings(image config.ImageSettings) ImageDTO {
+	return ImageDTO{
+		MaxLongEdge: image.MaxLongEdge,
+		Format:      image.Format,
+		JPEGQuality: image.JPEGQuality,
+		Grayscale:   image.Grayscale,
+		MaxKB:       image.MaxKB,
+		Enhance:     EnhanceDTO(image.Enhance),
+	}
+}
+
+func toImageSettings(dto ImageDTO) config.ImageSettings {
+	return config.ImageSettings{
+		MaxLongEdge: dto.MaxLongEdge,
+		Format:      dto.Format,
+		JPEGQuality: dto.JPEGQuality,
+		Grayscale:   dto.Grayscale,
+		MaxKB:       dto.MaxKB,
+		Enhance:     imageproc.Enhancement(dto.Enhance),
+	}
+}
+
+func fromSampling(sampling ai.Sampling) StageSamplingDTO {
+	stop := sampling.Stop
+	if stop == nil {
+		stop = []string{}
+	}
+	extra := sampling.ExtraBody
+	if extra == nil {
+		extra = map[string]interface{}{}
+	}
+	return StageSamplingDTO{
+		Temperature:      sampling.Temperature,
+		TopP:             sampling.TopP,
+		MaxTokens:        sampling.MaxTokens,
+		PresencePenalty:  sampling.PresencePenalty,
+		FrequencyPenalty: sampling.FrequencyPenalty,
+		Seed:             sampling.Seed,
+		Stop:             stop,
+		ExtraBody:        extra,
+	}
+}
+
+func toSampling(dto StageSamplingDTO) ai.Sampling {
+	return ai.Sampling{
+		Temperature:      dto.Temperature,
+		TopP:             dto.TopP,
+		MaxTokens:        dto.MaxTokens,
+		PresencePenalty:  dto.PresencePenalty,
+		FrequencyPenalty: dto.FrequencyPenalty,
+		Seed:             dto.Seed,
+		Stop:             dto.Stop,
+		ExtraBody:        dto.ExtraBody,
+	}
+}
+
+func fromProbeResult(result ai.ProbeResult) EndpointProbeDTO {
+	return EndpointProbeDTO{
+		Endpoint:     result.Endpoint,
+		Provider:     result.Provider,
+		BaseURL:      result.BaseURL,
+		Model:        result.Model,
+		OK:           result.Err == nil,
+		Auth:         string(result.Auth),
+		LatencyMs:    result.Latency.Milliseconds(),
+		Reply:        result.Reply,
+		Error:        probeErrorMessage(result.Err),
+		Streaming:    result.Streaming,
+		FirstTokenMs: result.FirstTokenLatency.Milliseconds(),
+		StreamError:  probeErrorMessage(result.StreamErr),
+		Images:       result.Images,
+	}
+}
+
+func probeErrorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	var requestErr *ai.RequestError
+	if errors.As(err, &requestErr) {
+		return requestErr.UserMessage()
+	}
+	return err.Error()
+}
+
+func fromModelList(list ai.ModelList) ModelListDTO {
+	dto := ModelListDTO{
+		Endpoint:  list.Endpoint,
+		Provider:  list.Provider,
+		BaseURL:   list.BaseURL,
+		Models:    make([]ModelOptionDTO, 0, len(list.Models)),
+		Supported: !errors.Is(list.Err, ai.ErrModelListUnsupported),
+	}
+	for _, model := range list.Models {
+		dto.Models = append(dto.Models, ModelOptionDTO{ID: model.ID, Name: model.Name})
+	}
+	if list.Err != nil {
+		dto.Error = list.Err.Error()
+		var requestErr *ai.RequestError
+		if dto.Supported && errors.As(list.Err, &requestErr) {
+			dto.Error = requestErr.UserMessage()
+		}
+	}
+	return dto
+}
+
+func fromFallbacks(fallbacks []ai.EndpointConfig) []FallbackEndpointDTO {
+	result := make([]FallbackEndpointDTO, 0, len(fallbacks))
+	for _, fallback := range fallbacks {
+		result = append(result, FallbackEndpointDTO{
+			BaseURL:  fallback.BaseURL,
+			APIKey:   fallback.APIKey,
+			Model:    fallback.Model,
+			Provider: fallback.Provider,
+		})
+	}
+	return result
+}
+
+func toFallbacks(fallbacks []FallbackEndpointDTO) []ai.EndpointConfig {
+	var result []ai.EndpointConfig
+	for _, fallback := range fallbacks {
+		result = append(result, ai.EndpointConfig{
+			BaseURL:  strings.TrimSpace(fallback.BaseURL),
+			APIKey:   strings.TrimSpace(fallback.APIKey),
+			Model:    strings.TrimSpace(fallback.Model),
+			Provider: strings.TrimSpace(fallback.Provider),
+		})
+	}
+	return result
+}
+
+func fromCaptureRegions(regions []config.CaptureRegion) []CaptureRegionDTO {
+	result := make([]CaptureRegionDTO, 0, len(regions))
+	for _, region := range regions {
+		result = append(result, CaptureRegionDTO(region))
+	}
+	return result
+}
+
+func toCaptureRegions(regions []CaptureRegionDTO) []config.CaptureRegion {
+	var result []config.CaptureRegion
+	for _, region := range regions {
+		region.Name = strings.TrimSpace(region.Name)
+		region.Hotkey = normalizeOptionalHotkey(region.Hotkey)
+		result = append(result, config.CaptureRegion(region))
+	}
+	return result
+}
+
+// normalizeOptionalHotkey 规范化可选热键，留空或无法解析时返回空字符串表示不绑定
+func normalizeOptionalHotkey(combo string) string {
+	normalized, err := hotkey.NormalizeCombination(strings.TrimSpace(combo))
+	if err != nil {
+		return ""
+	}
+	return normalized
+}
+
+func fromBudgetLimits(limits usage.Limits) BudgetLimitsDTO {
+	return BudgetLimitsDTO{
+		RequestsPerDay: limits.RequestsPerDay,
+		TokensPerDay:   limits.TokensPerDay,
+		CostPerMonth:   limits.CostPerMonth,
+	}
+}
+
+func toBudgetLimits(limits BudgetLimitsDTO) usage.Limits {
+	return usage.Limits{
+		RequestsPerDay: limits.RequestsPerDay,
+		TokensPerDay:   limits.TokensPerDay,
+		CostPerMonth:   limits.CostPerMonth,
+	}
+}
+
+func fromModelPrices(prices map[string]usage.Price) map[string]ModelPriceDTO {
+	result := make(map[string]ModelPriceDTO, len(prices))
+	for model, price := range prices {
+		result[model] = ModelPriceDTO{Input: price.Input, Output: price.Output}
+	}
+	return result
+}
+
+func toModelPrices(prices map[string]ModelPriceDTO) map[string]usage.Price {
+	if len(prices) == 0 {
+		return nil
+	}
+	result := make(map[string]usage.Price, len(prices))
+	for model, price := range prices {
+		model = strings.TrimSpace(model)
+		if model == "" {
+			continue
+		}
+		result[model] = usage.Price{Input: price.Input, Output: price.Output}
 	}
+	return result
 }
 
 func toConfigSettings(dto SettingsDTO) config.Settings {
@@ -775,6 +1892,12 @@ func toConfigSettings(dto SettingsDTO) config.Settings {
 	}
 	settings.ShowToastOnComplete = dto.ShowToastOnComplete
 	settings.EnableStreamOutput = dto.EnableStreamOutput
+	settings.ShowReasoning = dto.ShowReasoning
+	settings.AutoContinue = dto.AutoContinue
+	settings.MaxContinuations = dto.MaxContinuations
+	settings.DetectRefusals = dto.DetectRefusals
+	settings.PipelineTranslation = dto.PipelineTranslation
+	settings.FallbackOnBlocked = dto.FallbackOnBlocked
 	combo := strings.TrimSpace(dto.HotkeyCombination)
 	if combo == "" {
 		settings.HotkeyCombination = config.DefaultSettings().HotkeyCombination
@@ -792,8 +1915,36 @@ func toConfigSettings(dto SettingsDTO) config.Settings {
 	settings.VisionModel = strings.TrimSpace(dto.VisionModel)
 	settings.VisionAPIBaseURL = strings.TrimSpace(dto.VisionAPIBaseURL)
 	settings.VisionAPIKeyOverride = strings.TrimSpace(dto.VisionAPIKeyOverride)
+	settings.TranslateProvider = strings.TrimSpace(dto.TranslateProvider)
+	settings.VisionProvider = strings.TrimSpace(dto.VisionProvider)
 	settings.UseVisionForTranslation = dto.UseVisionForTranslation
+	settings.LayoutBlocks = dto.LayoutBlocks
 	settings.SourceLanguage = strings.TrimSpace(dto.SourceLanguage)
 	settings.TargetLanguage = strings.TrimSpace(dto.TargetLanguage)
+	if backend := strings.TrimSpace(dto.CaptureBackend); backend != "" {
+		settings.CaptureBackend = backend
+	}
+	settings.ModelPrices = toModelPrices(dto.ModelPrices)
+	settings.Budget = usage.Budget{
+		Soft: toBudgetLimits(dto.Budget.Soft),
+		Hard: toBudgetLimits(dto.Budget.Hard),
+	}
+	settings.RepeatHotkeyCombination = normalizeOptionalHotkey(dto.RepeatHotkeyCombination)
+	settings.CaptureRegions = toCaptureRegions(dto.CaptureRegions)
+	settings.TranslateFallbacks = toFallbacks(dto.TranslateFallbacks)
+	settings.VisionFallbacks = toFallbacks(dto.VisionFallbacks)
+	settings.Cache = config.CacheSettings{
+		Disabled:  !dto.Cache.Enabled,
+		TTLHours:  dto.Cache.TTLHours,
+		MaxSizeMB: dto.Cache.MaxSizeMB,
+	}
+	settings.Sampling = config.SamplingSettings{
+		Extract:   toSampling(dto.Sampling.Extract),
+		Translate: toSampling(dto.Sampling.Translate),
+		Direct:    toSampling(dto.Sampling.Direct),
+	}
+	settings.Timeouts = config.TimeoutSettings(dto.Timeouts)
+	settings.Watch = config.WatchSettings(dto.Watch)
+	settings.Image = toImageSettings(dto.Image)
 	return settings
 }
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package main
+
+import "errors"
+
+// readClipboardImage 目前仅在 Windows 上实现
+func readClipboardImage() ([]byte, string, error) {
+	return nil, "", errors.New("当前平台暂不支持读取剪贴板图片")
+}
@@ -0,0 +1,89 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"Translater/core/imageproc"
+
+	"github.com/lxn/win"
+)
+
+var (
+	kernel32                    = syscall.NewLazyDLL("kernel32.dll")
+	user32                      = syscall.NewLazyDLL("user32.dll")
+	procGlobalSize              = kernel32.NewProc("GlobalSize")
+	procRegisterClipboardFormat = user32.NewProc("RegisterClipboardFormatW")
+)
+
+// readClipboardImage 读取剪贴板中的图片，优先使用浏览器与截图工具写入的 PNG 格式，
+// 否则将 CF_DIB 位图转换为 BMP 文件，返回图片内容及其 MIME 类型
+func readClipboardImage() ([]byte, string, error) {
+	if err := openClipboard(); err != nil {
+		return nil, "", err
+	}
+	defer win.CloseClipboard()
+
+	if format := registerClipboardFormat("PNG"); format != 0 && win.IsClipboardFormatAvailable(format) {
+		data, err := clipboardBytes(format)
+		if err != nil {
+			return nil, "", err
+		}
+		return data, "image/png", nil
+	}
+	if win.IsClipboardFormatAvailable(win.CF_DIB) {
+		dib, err := clipboardBytes(win.CF_DIB)
+		if err != nil {
+			return nil, "", err
+		}
+		data, err := imageproc.DIBToBMP(dib)
+		if err != nil {
+			return nil, "", err
+		}
+		return data, "image/bmp", nil
+	}
+	return nil, "", errors.New("剪贴板中没有图片")
+}
+
+// openClipboard 打开剪贴板，其他程序占用时短暂重试
+func openClipboard() error {
+	for attempt := 0; attempt < 5; attempt++ {
+		if win.OpenClipboard(0) {
+			return nil
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return fmt.Errorf("打开剪贴板失败: %w", syscall.Errno(win.GetLastError()))
+}
+
+func registerClipboardFormat(name string) uint32 {
+	namePtr, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return 0
+	}
+	format, _, _ := procRegisterClipboardFormat.Call(uintptr(unsafe.Pointer(namePtr)))
+	return uint32(format)
+}
+
+// clipboardBytes 复制剪贴板中指定格式的数据，需在剪贴板打开期间调用
+func clipboardBytes(format uint32) ([]byte, error) {
+	handle := win.HGLOBAL(win.GetClipboardData(format))
+	if handle == 0 {
+		return nil, fmt.Errorf("读取剪贴板数据失败: %w", syscall.Errno(win.GetLastError()))
+	}
+	size, _, _ := procGlobalSize.Call(uintptr(handle))
+	if size == 0 {
+		return nil, errors.New("剪贴板数据为空")
+	}
+	ptr := win.GlobalLock(handle)
+	if ptr == nil {
+		return nil, fmt.Errorf("锁定剪贴板数据失败: %w", syscall.Errno(win.GetLastError()))
+	}
+	defer win.GlobalUnlock(handle)
+	return append([]byte(nil), unsafe.Slice((*byte)(ptr), size)...), nil
+}
@@ -1,6 +1,6 @@
 import {main} from '../wailsjs/go/models';
 
-export type TranslationSource = 'manual' | 'screenshot';
+export type TranslationSource = 'manual' | 'screenshot' | 'image';
 
 export interface ScreenshotBounds {
 	startX: number;
@@ -11,6 +11,8 @@ export interface ScreenshotBounds {
 	top: number;
 	width: number;
 	height: number;
+	// 所在显示器的缩放比例，以上坐标均为物理像素
+	scale: number;
 }
 
 export interface TranslationResult {
@@ -20,6 +22,39 @@ export interface TranslationResult {
 	timestamp: string;
 	durationMs: number;
 	bounds?: ScreenshotBounds;
+	cacheHit?: boolean;
+	servedBy?: ServedBy[];
+	// 推理模型的思考过程，仅在开启“显示思考过程”时存在
+	extractReasoning?: string;
+	reasoning?: string;
+	// 阶段超时后保留的部分译文
+	partial?: boolean;
+	// 自动续写次数与最终是否仍被截断
+	continuations?: number;
+	truncated?: boolean;
+	// 提取阶段的场景描述与原始输出，用于排查识别问题
+	background?: string;
+	rawExtractedText?: string;
+	// 版面分块模式下逐块的原文、译文与屏幕坐标
+	blocks?: TranslationBlock[];
+}
+
+export interface TranslationBlock {
+	source: string;
+	target: string;
+	left: number;
+	top: number;
+	width: number;
+	height: number;
+}
+
+// ServedBy 记录某个阶段实际应答的接口，fallback 为 0 表示主接口
+export interface ServedBy {
+	stage: string;
+	provider: string;
+	baseUrl: string;
+	model: string;
+	fallback: number;
 }
 
 export interface StatusMessage {
@@ -32,24 +67,228 @@ export interface SettingsState {
 	apiBaseUrl: string;
 	visionApiKeyOverride: string;
 	visionApiBaseUrl: string;
+	translateProvider: string;
+	visionProvider: string;
 	autoCopyResult: boolean;
 	keepWindowOnTop: boolean;
 	theme: string;
 	showToastOnComplete: boolean;
 	enableStreamOutput: boolean;
+	showReasoning: boolean;
+	autoContinue: boolean;
+	maxContinuations: number;
+	detectRefusals: boolean;
+	pipelineTranslation: boolean;
+	fallbackOnBlocked: boolean;
 	hotkeyCombination: string;
+	repeatHotkeyCombination: string;
+	captureRegions: CaptureRegion[];
+	watch: WatchSettings;
 	extractPrompt: string;
 	translatePrompt: string;
 	translateModel: string;
 	visionModel: string;
 	useVisionForTranslation: boolean;
+	layoutBlocks: boolean;
 	sourceLanguage: string;
 	targetLanguage: string;
+	captureBackend: string;
+	modelPrices: Record<string, ModelPrice>;
+	budget: BudgetSettings;
+	cache: CacheSettings;
+	translateFallbacks: FallbackEndpoint[];
+	visionFallbacks: FallbackEndpoint[];
+	sampling: SamplingSettings;
+	timeouts: TimeoutSettings;
+	image: ImageSettings;
+}
+
+export type SamplingStage = 'extract' | 'translate' | 'direct';
+
+// 单个阶段的采样参数，null 或空字符串表示沿用默认值
+export interface StageSampling {
+	temperature: number | null;
+	topP: number | null;
+	maxTokens: number;
+	presencePenalty: number | null;
+	frequencyPenalty: number | null;
+	seed: number | null;
+	// 每行一个停止词
+	stop: string;
+	// JSON 对象文本，合并到请求体顶层
+	extraBody: string;
+}
+
+export type SamplingSettings = Record<SamplingStage, StageSampling>;
+
+// 命名的固定截图区域，坐标为虚拟桌面中的屏幕坐标
+export interface CaptureRegion {
+	name: string;
+	left: number;
+	top: number;
+	width: number;
+	height: number;
+	hotkey: string;
+}
+
+// 显示器在虚拟桌面中的范围
+export interface MonitorInfo {
+	index: number;
+	left: number;
+	top: number;
+	width: number;
+	height: number;
+	scale: number;
+	primary: boolean;
+}
+
+// 一次截图翻译的取景方式，watch 为 true 时持续监视该区域；image 与 clipboard 翻译导入的图片
+export type CaptureTarget =
+	| {kind: 'select'}
+	| {kind: 'last'; watch?: boolean}
+	| {kind: 'monitor'; index: number}
+	| {kind: 'region'; name: string; watch?: boolean}
+	| {kind: 'image'; name: string; data: string; mimeType: string}
+	| {kind: 'clipboard'};
+
+// 可导入翻译的图片格式，用于文件选择框与拖放校验
+export const IMAGE_MIME_TYPES = ['image/png', 'image/jpeg', 'image/gif', 'image/bmp', 'image/webp'];
+
+// 读取图片文件为 data URL，交给后端 TranslateImage 解码
+export function readImageFile(file: File): Promise<CaptureTarget> {
+	return new Promise((resolve, reject) => {
+		if (file.type && !IMAGE_MIME_TYPES.includes(file.type)) {
+			reject(new Error(`不支持的图片格式：${file.type}`));
+			return;
+		}
+		const reader = new FileReader();
+		reader.onload = () => resolve({kind: 'image', name: file.name, data: String(reader.result ?? ''), mimeType: file.type});
+		reader.onerror = () => reject(reader.error ?? new Error('读取图片失败'));
+		reader.readAsDataURL(file);
+	});
+}
+
+// 备用接口，留空的字段沿用主接口配置
+export interface FallbackEndpoint {
+	baseUrl: string;
+	apiKey: string;
+	model: string;
+	provider: string;
+}
+
+// 模型单价，单位为每百万 token
+export interface ModelPrice {
+	input: number;
+	output: number;
+}
+
+// 用量上限，0 表示不限制
+export interface BudgetLimits {
+	requestsPerDay: number;
+	tokensPerDay: number;
+	costPerMonth: number;
+}
+
+// 软上限仅提醒，硬上限会阻止翻译
+export interface BudgetSettings {
+	soft: BudgetLimits;
+	hard: BudgetLimits;
+}
+
+// 翻译结果缓存配置
+export interface CacheSettings {
+	enabled: boolean;
+	ttlHours: number;
+	maxSizeMb: number;
+}
+
+// 各项时限，单位为秒
+export interface TimeoutSettings {
+	connectSeconds: number;
+	firstTokenSeconds: number;
+	idleSeconds: number;
+	captureSeconds: number;
+	ocrSeconds: number;
+	translateSeconds: number;
 }
 
+export const DEFAULT_TIMEOUTS: TimeoutSettings = {
+	connectSeconds: 10,
+	firstTokenSeconds: 60,
+	idleSeconds: 30,
+	captureSeconds: 10,
+	ocrSeconds: 90,
+	translateSeconds: 180,
+};
+
+// 区域监视：轮询间隔与稳定时长单位为毫秒，阈值为感知哈希的差异位数（0-64）
+export interface WatchSettings {
+	intervalMs: number;
+	threshold: number;
+	debounceMs: number;
+}
+
+export const DEFAULT_WATCH: WatchSettings = {
+	intervalMs: 500,
+	threshold: 4,
+	debounceMs: 400,
+};
+
+export type ImageFormat = 'png' | 'jpeg';
+
+// 截图上传前的预处理设置
+export interface ImageSettings {
+	maxLongEdge: number;
+	format: ImageFormat;
+	jpegQuality: number;
+	grayscale: boolean;
+	maxKb: number;
+	enhance: EnhanceSettings;
+}
+
+// 提升小字号、低对比度文字识别率的增强步骤
+export interface EnhanceSettings {
+	trimBorders: boolean;
+	invertDark: boolean;
+	autoContrast: boolean;
+	sharpen: boolean;
+	// 长边小于该像素数时整数倍放大，0 表示不放大
+	upscaleBelow: number;
+	maxUpscale: number;
+}
+
+export const DEFAULT_ENHANCE: EnhanceSettings = {
+	trimBorders: false,
+	invertDark: false,
+	autoContrast: false,
+	sharpen: false,
+	upscaleBelow: 0,
+	maxUpscale: 3,
+};
+
+export const DEFAULT_IMAGE: ImageSettings = {
+	maxLongEdge: 2048,
+	format: 'png',
+	jpegQuality: 85,
+	grayscale: false,
+	maxKb: 1024,
+	enhance: {...DEFAULT_ENHANCE},
+};
+
+export const DEFAULT_CACHE_TTL_HOURS = 168;
+export const DEFAULT_CACHE_MAX_SIZE_MB = 64;
+
 export const DEFAULT_API_BASE_URL = 'https://open.bigmodel.cn/api/paas/v4';
 export const DEFAULT_TRANSLATE_MODEL = 'glm-4.5-flash';
 export const DEFAULT_VISION_MODEL = 'glm-4v-flash';
+export const DEFAULT_PROVIDER = 'openai';
+
+export const PROVIDER_OPTIONS = [
+	{value: 'openai', label: 'OpenAI Chat Completions 兼容'},
+	{value: 'anthropic', label: 'Anthropic Messages'},
+	{value: 'gemini', label: 'Google Gemini'},
+	{value: 'ollama', label: 'Ollama /api/chat'},
+];
 
 export const DEFAULT_EXTRACT_PROMPT = `你是一个专业的视觉上下文分析专家，负责为高质量的翻译任务准备完整素材。请完成以下工作：
 
@@ -194,19 +433,51 @@ export function defaultSettingsState(): SettingsState {
 		apiBaseUrl: DEFAULT_API_BASE_URL,
 		visionApiKeyOverride: '',
 		visionApiBaseUrl: DEFAULT_API_BASE_URL,
+		translateProvider: DEFAULT_PROVIDER,
+		visionProvider: DEFAULT_PROVIDER,
 		autoCopyResult: true,
 		keepWindowOnTop: false,
 		theme: 'system',
 		showToastOnComplete: true,
 		enableStreamOutput: true,
+		showReasoning: false,
+		autoContinue: true,
+		maxContinuations: 2,
+		detectRefusals: false,
+		pipelineTranslation: true,
+		fallbackOnBlocked: true,
 		hotkeyCombination: 'Alt+T',
+		repeatHotkeyCombination: '',
+		captureRegions: [],
+		watch: {...DEFAULT_WATCH},
 		extractPrompt: DEFAULT_EXTRACT_PROMPT,
 		translatePrompt: DEFAULT_TRANSLATE_PROMPT,
 		translateModel: DEFAULT_TRANSLATE_MODEL,
 		visionModel: DEFAULT_VISION_MODEL,
 		useVisionForTranslation: true,
+		layoutBlocks: false,
 		sourceLanguage: 'auto',
 		targetLanguage: 'zh-CN',
+		captureBackend: 'auto',
+		modelPrices: {},
+		budget: {
+			soft: emptyBudgetLimits(),
+			hard: emptyBudgetLimits(),
+		},
+		cache: {
+			enabled: true,
+			ttlHours: DEFAULT_CACHE_TTL_HOURS,
+			maxSizeMb: DEFAULT_CACHE_MAX_SIZE_MB,
+		},
+		translateFallbacks: [],
+		visionFallbacks: [],
+		timeouts: {...DEFAULT_TIMEOUTS},
+		image: {...DEFAULT_IMAGE, enhance: {...DEFAULT_ENHANCE}},
+		sampling: {
+			extract: emptyStageSampling(),
+			translate: emptyStageSampling(),
+			direct: emptyStageSampling(),
+		},
 	};
 }
 
@@ -227,6 +498,7 @@ export function mapTranslationResult(data: any): TranslationResult {
 				top: Number(rawBounds.top) || 0,
 				width: Number(rawBounds.width) || 1,
 				height: Number(rawBounds.height) || 1,
+				scale: Number(rawBounds.scale) || 1,
 			}
 			: undefined;
 		console.log('📦 [mapTranslationResult] bounds 处理完成');
@@ -237,6 +509,15 @@ export function mapTranslationResult(data: any): TranslationResult {
 			timestamp: timestamp.toISOString(),
 			durationMs: Number.isFinite(data.durationMs) ? data.durationMs : 0,
 			bounds,
+			cacheHit: Boolean(data.cacheHit),
+			servedBy: Array.isArray(data.servedBy) ? (data.servedBy as ServedBy[]) : undefined,
+			extractReasoning: typeof data.extractReasoning === 'string' ? data.extractReasoning : undefined,
+			reasoning: typeof data.reasoning === 'string' ? data.reasoning : undefined,
+			continuations: Number(data.continuations) || 0,
+			truncated: Boolean(data.truncated),
+			background: typeof data.background === 'string' ? data.background : undefined,
+			rawExtractedText: typeof data.rawExtractedText === 'string' ? data.rawExtractedText : undefined,
+			blocks: Array.isArray(data.blocks) ? (data.blocks as TranslationBlock[]) : undefined,
 		};
 		console.log('📦 [mapTranslationResult] result 对象创建完成');
 		const preview = result.translatedText.length > 100 ? result.translatedText.substring(0, 100) : result.translatedText;
@@ -256,44 +537,262 @@ export function mapSettings(data: main.SettingsDTO | any): SettingsState {
 		apiBaseUrl: converted.apiBaseUrl || defaults.apiBaseUrl,
 		visionApiKeyOverride: converted.visionApiKeyOverride ?? '',
 		visionApiBaseUrl: converted.visionApiBaseUrl || converted.apiBaseUrl || defaults.visionApiBaseUrl,
+		translateProvider: converted.translateProvider || defaults.translateProvider,
+		visionProvider: converted.visionProvider || converted.translateProvider || defaults.visionProvider,
 		autoCopyResult: Boolean(converted.autoCopyResult),
 		keepWindowOnTop: Boolean(converted.keepWindowOnTop),
 		theme: converted.theme || defaults.theme,
 		showToastOnComplete: Boolean(converted.showToastOnComplete),
 		enableStreamOutput: Boolean((converted as any).enableStreamOutput ?? defaults.enableStreamOutput),
+		showReasoning: Boolean(converted.showReasoning),
+		autoContinue: Boolean(converted.autoContinue),
+		maxContinuations: Number(converted.maxContinuations) || defaults.maxContinuations,
+		detectRefusals: Boolean(converted.detectRefusals),
+		pipelineTranslation: Boolean(converted.pipelineTranslation ?? defaults.pipelineTranslation),
+		fallbackOnBlocked: Boolean(converted.fallbackOnBlocked),
 		hotkeyCombination: converted.hotkeyCombination || defaults.hotkeyCombination,
+		repeatHotkeyCombination: (converted as any).repeatHotkeyCombination ?? '',
+		captureRegions: mapCaptureRegions((converted as any).captureRegions),
+		watch: mapWatch((converted as any).watch),
 		extractPrompt: converted.extractPrompt || defaults.extractPrompt,
 		translatePrompt: converted.translatePrompt || defaults.translatePrompt,
 		translateModel: converted.translateModel || defaults.translateModel,
 		visionModel: converted.visionModel || defaults.visionModel,
 		useVisionForTranslation: Boolean((converted as any).useVisionForTranslation ?? defaults.useVisionForTranslation),
+		layoutBlocks: Boolean(converted.layoutBlocks),
 		sourceLanguage: (converted as any).sourceLanguage || defaults.sourceLanguage,
 		targetLanguage: (converted as any).targetLanguage || defaults.targetLanguage,
+		captureBackend: (converted as any).captureBackend || defaults.captureBackend,
+		modelPrices: mapModelPrices((converted as any).modelPrices),
+		budget: {
+			soft: mapBudgetLimits((converted as any).budget?.soft),
+			hard: mapBudgetLimits((converted as any).budget?.hard),
+		},
+		cache: {
+			enabled: Boolean((converted as any).cache?.enabled ?? defaults.cache.enabled),
+			ttlHours: Number((converted as any).cache?.ttlHours) || defaults.cache.ttlHours,
+			maxSizeMb: Number((converted as any).cache?.maxSizeMb) || defaults.cache.maxSizeMb,
+		},
+		translateFallbacks: mapFallbacks((converted as any).translateFallbacks),
+		visionFallbacks: mapFallbacks((converted as any).visionFallbacks),
+		sampling: {
+			extract: mapStageSampling((converted as any).sampling?.extract),
+			translate: mapStageSampling((converted as any).sampling?.translate),
+			direct: mapStageSampling((converted as any).sampling?.direct),
+		},
+		timeouts: mapTimeouts((converted as any).timeouts),
+		image: mapImage((converted as any).image),
 	};
 }
 
+function mapImage(data: any): ImageSettings {
+	const positive = (value: unknown, fallback: number) => {
+		const parsed = Number(value);
+		return Number.isFinite(parsed) && parsed > 0 ? Math.round(parsed) : fallback;
+	};
+	return {
+		maxLongEdge: positive(data?.maxLongEdge, DEFAULT_IMAGE.maxLongEdge),
+		format: data?.format === 'jpeg' ? 'jpeg' : 'png',
+		jpegQuality: Math.min(positive(data?.jpegQuality, DEFAULT_IMAGE.jpegQuality), 100),
+		grayscale: Boolean(data?.grayscale),
+		maxKb: positive(data?.maxKb, DEFAULT_IMAGE.maxKb),
+		enhance: {
+			trimBorders: Boolean(data?.enhance?.trimBorders),
+			invertDark: Boolean(data?.enhance?.invertDark),
+			autoContrast: Boolean(data?.enhance?.autoContrast),
+			sharpen: Boolean(data?.enhance?.sharpen),
+			upscaleBelow: positive(data?.enhance?.upscaleBelow, 0),
+			maxUpscale: positive(data?.enhance?.maxUpscale, DEFAULT_ENHANCE.maxUpscale),
+		},
+	};
+}
+
+function mapTimeouts(data: any): TimeoutSettings {
+	const timeouts = {...DEFAULT_TIMEOUTS};
+	for (const key of Object.keys(timeouts) as (keyof TimeoutSettings)[]) {
+		const value = Number(data?.[key]);
+		if (Number.isFinite(value) && value > 0) {
+			timeouts[key] = Math.round(value);
+		}
+	}
+	return timeouts;
+}
+
 export function toSettingsPayload(state: SettingsState): main.SettingsDTO {
 	return main.SettingsDTO.createFrom({
 		apiKeyOverride: state.apiKeyOverride,
 		apiBaseUrl: state.apiBaseUrl,
 		visionApiKeyOverride: state.visionApiKeyOverride,
 		visionApiBaseUrl: state.visionApiBaseUrl,
+		translateProvider: state.translateProvider,
+		visionProvider: state.visionProvider,
 		autoCopyResult: state.autoCopyResult,
 		keepWindowOnTop: state.keepWindowOnTop,
 		theme: state.theme,
 		showToastOnComplete: state.showToastOnComplete,
 		enableStreamOutput: state.enableStreamOutput,
+		showReasoning: state.showReasoning,
+		autoContinue: state.autoContinue,
+		maxContinuations: state.maxContinuations,
+		detectRefusals: state.detectRefusals,
+		pipelineTranslation: state.pipelineTranslation,
+		fallbackOnBlocked: state.fallbackOnBlocked,
 		hotkeyCombination: state.hotkeyCombination,
+		repeatHotkeyCombination: state.repeatHotkeyCombination,
 		extractPrompt: state.extractPrompt,
 		translatePrompt: state.translatePrompt,
 		translateModel: state.translateModel,
 		visionModel: state.visionModel,
 		useVisionForTranslation: state.useVisionForTranslation,
+		layoutBlocks: state.layoutBlocks,
 		sourceLanguage: state.sourceLanguage,
 		targetLanguage: state.targetLanguage,
+		captureBackend: state.captureBackend,
+		modelPrices: state.modelPrices,
+		budget: state.budget,
+		cache: state.cache,
+		captureRegions: state.captureRegions,
+		watch: state.watch,
+		translateFallbacks: state.translateFallbacks,
+		visionFallbacks: state.visionFallbacks,
+		sampling: {
+			extract: toStageSamplingPayload(state.sampling.extract),
+			translate: toStageSamplingPayload(state.sampling.translate),
+			direct: toStageSamplingPayload(state.sampling.direct),
+		},
+		timeouts: state.timeouts,
+		image: state.image,
 	});
 }
 
+// parseExtraBody 解析额外请求参数，空文本返回空对象，非法 JSON 或非对象时返回 null
+export function parseExtraBody(text: string): Record<string, any> | null {
+	if (!text.trim()) {
+		return {};
+	}
+	try {
+		const parsed = JSON.parse(text);
+		if (!parsed || typeof parsed !== 'object' || Array.isArray(parsed)) {
+			return null;
+		}
+		return parsed;
+	} catch {
+		return null;
+	}
+}
+
+function emptyStageSampling(): StageSampling {
+	return {
+		temperature: null,
+		topP: null,
+		maxTokens: 0,
+		presencePenalty: null,
+		frequencyPenalty: null,
+		seed: null,
+		stop: '',
+		extraBody: '',
+	};
+}
+
+function optionalNumber(value: any): number | null {
+	if (value === null || value === undefined || value === '') {
+		return null;
+	}
+	const parsed = Number(value);
+	return Number.isFinite(parsed) ? parsed : null;
+}
+
+function mapStageSampling(data: any): StageSampling {
+	const extraBody = data?.extraBody && typeof data.extraBody === 'object' ? data.extraBody : {};
+	return {
+		temperature: optionalNumber(data?.temperature),
+		topP: optionalNumber(data?.topP),
+		maxTokens: Math.max(0, Math.floor(Number(data?.maxTokens) || 0)),
+		presencePenalty: optionalNumber(data?.presencePenalty),
+		frequencyPenalty: optionalNumber(data?.frequencyPenalty),
+		seed: optionalNumber(data?.seed),
+		stop: Array.isArray(data?.stop) ? data.stop.join('\n') : '',
+		extraBody: Object.keys(extraBody).length ? JSON.stringify(extraBody, null, 2) : '',
+	};
+}
+
+function toStageSamplingPayload(state: StageSampling): main.StageSamplingDTO {
+	const seed = optionalNumber(state.seed);
+	return main.StageSamplingDTO.createFrom({
+		temperature: optionalNumber(state.temperature) ?? undefined,
+		topP: optionalNumber(state.topP) ?? undefined,
+		maxTokens: Math.max(0, Math.floor(Number(state.maxTokens) || 0)),
+		presencePenalty: optionalNumber(state.presencePenalty) ?? undefined,
+		frequencyPenalty: optionalNumber(state.frequencyPenalty) ?? undefined,
+		seed: seed === null ? undefined : Math.floor(seed),
+		stop: state.stop.split('\n').filter((value) => value.trim() !== ''),
+		extraBody: parseExtraBody(state.extraBody) ?? {},
+	});
+}
+
+function mapWatch(data: any): WatchSettings {
+	const watch = {...DEFAULT_WATCH};
+	for (const key of Object.keys(watch) as (keyof WatchSettings)[]) {
+		const value = Number(data?.[key]);
+		if (Number.isFinite(value) && value >= 0) {
+			watch[key] = Math.round(value);
+		}
+	}
+	return watch;
+}
+
+function mapCaptureRegions(data: any): CaptureRegion[] {
+	if (!Array.isArray(data)) {
+		return [];
+	}
+	return data.map((item: any) => ({
+		name: item?.name ?? '',
+		left: Math.floor(Number(item?.left) || 0),
+		top: Math.floor(Number(item?.top) || 0),
+		width: Math.max(0, Math.floor(Number(item?.width) || 0)),
+		height: Math.max(0, Math.floor(Number(item?.height) || 0)),
+		hotkey: item?.hotkey ?? '',
+	}));
+}
+
+function mapFallbacks(data: any): FallbackEndpoint[] {
+	if (!Array.isArray(data)) {
+		return [];
+	}
+	return data.map((item: any) => ({
+		baseUrl: item?.baseUrl ?? '',
+		apiKey: item?.apiKey ?? '',
+		model: item?.model ?? '',
+		provider: item?.provider ?? '',
+	}));
+}
+
+function emptyBudgetLimits(): BudgetLimits {
+	return {requestsPerDay: 0, tokensPerDay: 0, costPerMonth: 0};
+}
+
+function mapBudgetLimits(data: any): BudgetLimits {
+	return {
+		requestsPerDay: Math.max(0, Math.floor(Number(data?.requestsPerDay) || 0)),
+		tokensPerDay: Math.max(0, Math.floor(Number(data?.tokensPerDay) || 0)),
+		costPerMonth: Math.max(0, Number(data?.costPerMonth) || 0),
+	};
+}
+
+function mapModelPrices(data: any): Record<string, ModelPrice> {
+	const prices: Record<string, ModelPrice> = {};
+	if (!data || typeof data !== 'object') {
+		return prices;
+	}
+	for (const [model, price] of Object.entries<any>(data)) {
+		prices[model] = {
+			input: Number(price?.input) || 0,
+			output: Number(price?.output) || 0,
+		};
+	}
+	return prices;
+}
+
 export function formatTimestamp(iso: string): string {
 	const date = new Date(iso);
 	if (Number.isNaN(date.getTime())) {
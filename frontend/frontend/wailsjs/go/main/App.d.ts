@@ -2,8 +2,36 @@
 // This file is automatically generated. DO NOT EDIT
 import {main} from '../models';
 
+export function CaptureLastRegion():Promise<void>;
+
+export function CaptureMonitor(arg1:number):Promise<void>;
+
+export function CaptureRegion(arg1:string):Promise<void>;
+
+export function ClearResultCache():Promise<void>;
+
+export function GetDailyUsage(arg1:number):Promise<main.UsagePeriodDTO[]>;
+
 export function GetSettings():Promise<main.SettingsDTO>;
 
+export function GetUsageReport():Promise<main.UsageReportDTO>;
+
+export function ListModels(arg1:main.SettingsDTO):Promise<main.ModelCatalogDTO>;
+
+export function ListMonitors():Promise<main.MonitorDTO[]>;
+
 export function SaveSettings(arg1:main.SettingsDTO):Promise<main.SettingsDTO>;
 
 export function StartScreenshotTranslation():Promise<void>;
+
+export function StopWatch():Promise<void>;
+
+export function TestConnection(arg1:main.SettingsDTO):Promise<main.ConnectionReportDTO>;
+
+export function TranslateClipboardImage():Promise<void>;
+
+export function TranslateImage(arg1:string,arg2:string):Promise<void>;
+
+export function WatchLastRegion():Promise<void>;
+
+export function WatchRegion(arg1:string):Promise<void>;
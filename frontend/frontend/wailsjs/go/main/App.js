@@ -2,10 +2,42 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function CaptureLastRegion() {
+  return window['go']['main']['App']['CaptureLastRegion']();
+}
+
+export function CaptureMonitor(arg1) {
+  return window['go']['main']['App']['CaptureMonitor'](arg1);
+}
+
+export function CaptureRegion(arg1) {
+  return window['go']['main']['App']['CaptureRegion'](arg1);
+}
+
+export function ClearResultCache() {
+  return window['go']['main']['App']['ClearResultCache']();
+}
+
+export function GetDailyUsage(arg1) {
+  return window['go']['main']['App']['GetDailyUsage'](arg1);
+}
+
 export function GetSettings() {
   return window['go']['main']['App']['GetSettings']();
 }
 
+export function GetUsageReport() {
+  return window['go']['main']['App']['GetUsageReport']();
+}
+
+export function ListModels(arg1) {
+  return window['go']['main']['App']['ListModels'](arg1);
+}
+
+export function ListMonitors() {
+  return window['go']['main']['App']['ListMonitors']();
+}
+
 export function SaveSettings(arg1) {
   return window['go']['main']['App']['SaveSettings'](arg1);
 }
@@ -13,3 +45,27 @@ export function SaveSettings(arg1) {
 export function StartScreenshotTranslation() {
   return window['go']['main']['App']['StartScreenshotTranslation']();
 }
+
+export function StopWatch() {
+  return window['go']['main']['App']['StopWatch']();
+}
+
+export function TestConnection(arg1) {
+  return window['go']['main']['App']['TestConnection'](arg1);
+}
+
+export function TranslateClipboardImage() {
+  return window['go']['main']['App']['TranslateClipboardImage']();
+}
+
+export function TranslateImage(arg1, arg2) {
+  return window['go']['main']['App']['TranslateImage'](arg1, arg2);
+}
+
+export function WatchLastRegion() {
+  return window['go']['main']['App']['WatchLastRegion']();
+}
+
+export function WatchRegion(arg1) {
+  return window['go']['main']['App']['WatchRegion'](arg1);
+}
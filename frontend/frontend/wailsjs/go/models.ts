@@ -1,5 +1,461 @@
 export namespace main {
 	
+	export class BudgetLimitsDTO {
+	    requestsPerDay: number;
+	    tokensPerDay: number;
+	    costPerMonth: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BudgetLimitsDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.requestsPerDay = source["requestsPerDay"];
+	        this.tokensPerDay = source["tokensPerDay"];
+	        this.costPerMonth = source["costPerMonth"];
+	    }
+	}
+	export class BudgetDTO {
+	    soft: BudgetLimitsDTO;
+	    hard: BudgetLimitsDTO;
+	
+	    static createFrom(source: any = {}) {
+	        return new BudgetDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.soft = this.convertValues(source["soft"], BudgetLimitsDTO);
+	        this.hard = this.convertValues(source["hard"], BudgetLimitsDTO);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CacheDTO {
+	    enabled: boolean;
+	    ttlHours: number;
+	    maxSizeMb: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CacheDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.ttlHours = source["ttlHours"];
+	        this.maxSizeMb = source["maxSizeMb"];
+	    }
+	}
+	export class CaptureRegionDTO {
+	    name: string;
+	    left: number;
+	    top: number;
+	    width: number;
+	    height: number;
+	    hotkey: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CaptureRegionDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.left = source["left"];
+	        this.top = source["top"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.hotkey = source["hotkey"];
+	    }
+	}
+	export class EndpointProbeDTO {
+	    endpoint: string;
+	    provider: string;
+	    baseUrl: string;
+	    model: string;
+	    ok: boolean;
+	    auth: string;
+	    latencyMs: number;
+	    reply: string;
+	    error: string;
+	    streaming: boolean;
+	    firstTokenMs: number;
+	    streamError: string;
+	    images?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new EndpointProbeDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.endpoint = source["endpoint"];
+	        this.provider = source["provider"];
+	        this.baseUrl = source["baseUrl"];
+	        this.model = source["model"];
+	        this.ok = source["ok"];
+	        this.auth = source["auth"];
+	        this.latencyMs = source["latencyMs"];
+	        this.reply = source["reply"];
+	        this.error = source["error"];
+	        this.streaming = source["streaming"];
+	        this.firstTokenMs = source["firstTokenMs"];
+	        this.streamError = source["streamError"];
+	        this.images = source["images"];
+	    }
+	}
+	export class ConnectionReportDTO {
+	    vision: EndpointProbeDTO;
+	    translate?: EndpointProbeDTO;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConnectionReportDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.vision = this.convertValues(source["vision"], EndpointProbeDTO);
+	        this.translate = this.convertValues(source["translate"], EndpointProbeDTO);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EnhanceDTO {
+	    trimBorders: boolean;
+	    invertDark: boolean;
+	    autoContrast: boolean;
+	    sharpen: boolean;
+	    upscaleBelow: number;
+	    maxUpscale: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new EnhanceDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.trimBorders = source["trimBorders"];
+	        this.invertDark = source["invertDark"];
+	        this.autoContrast = source["autoContrast"];
+	        this.sharpen = source["sharpen"];
+	        this.upscaleBelow = source["upscaleBelow"];
+	        this.maxUpscale = source["maxUpscale"];
+	    }
+	}
+	export class FallbackEndpointDTO {
+	    baseUrl: string;
+	    apiKey: string;
+	    model: string;
+	    provider: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FallbackEndpointDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.baseUrl = source["baseUrl"];
+	        this.apiKey = source["apiKey"];
+	        this.model = source["model"];
+	        this.provider = source["provider"];
+	    }
+	}
+	export class ImageDTO {
+	    maxLongEdge: number;
+	    format: string;
+	    jpegQuality: number;
+	    grayscale: boolean;
+	    maxKb: number;
+	    enhance: EnhanceDTO;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImageDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.maxLongEdge = source["maxLongEdge"];
+	        this.format = source["format"];
+	        this.jpegQuality = source["jpegQuality"];
+	        this.grayscale = source["grayscale"];
+	        this.maxKb = source["maxKb"];
+	        this.enhance = this.convertValues(source["enhance"], EnhanceDTO);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ModelOptionDTO {
+	    id: string;
+	    name: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelOptionDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	    }
+	}
+	export class ModelListDTO {
+	    endpoint: string;
+	    provider: string;
+	    baseUrl: string;
+	    models: ModelOptionDTO[];
+	    supported: boolean;
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelListDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.endpoint = source["endpoint"];
+	        this.provider = source["provider"];
+	        this.baseUrl = source["baseUrl"];
+	        this.models = this.convertValues(source["models"], ModelOptionDTO);
+	        this.supported = source["supported"];
+	        this.error = source["error"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ModelCatalogDTO {
+	    translate: ModelListDTO;
+	    vision: ModelListDTO;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelCatalogDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.translate = this.convertValues(source["translate"], ModelListDTO);
+	        this.vision = this.convertValues(source["vision"], ModelListDTO);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ModelPriceDTO {
+	    input: number;
+	    output: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelPriceDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.input = source["input"];
+	        this.output = source["output"];
+	    }
+	}
+	export class MonitorDTO {
+	    index: number;
+	    left: number;
+	    top: number;
+	    width: number;
+	    height: number;
+	    scale: number;
+	    primary: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new MonitorDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.left = source["left"];
+	        this.top = source["top"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.scale = source["scale"];
+	        this.primary = source["primary"];
+	    }
+	}
+	export class StageSamplingDTO {
+	    temperature?: number;
+	    topP?: number;
+	    maxTokens: number;
+	    presencePenalty?: number;
+	    frequencyPenalty?: number;
+	    seed?: number;
+	    stop: string[];
+	    extraBody: {[key: string]: any};
+	
+	    static createFrom(source: any = {}) {
+	        return new StageSamplingDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.temperature = source["temperature"];
+	        this.topP = source["topP"];
+	        this.maxTokens = source["maxTokens"];
+	        this.presencePenalty = source["presencePenalty"];
+	        this.frequencyPenalty = source["frequencyPenalty"];
+	        this.seed = source["seed"];
+	        this.stop = source["stop"];
+	        this.extraBody = source["extraBody"];
+	    }
+	}
+	export class SamplingDTO {
+	    extract: StageSamplingDTO;
+	    translate: StageSamplingDTO;
+	    direct: StageSamplingDTO;
+	
+	    static createFrom(source: any = {}) {
+	        return new SamplingDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.extract = this.convertValues(source["extract"], StageSamplingDTO);
+	        this.translate = this.convertValues(source["translate"], StageSamplingDTO);
+	        this.direct = this.convertValues(source["direct"], StageSamplingDTO);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WatchDTO {
+	    intervalMs: number;
+	    threshold: number;
+	    debounceMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WatchDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.intervalMs = source["intervalMs"];
+	        this.threshold = source["threshold"];
+	        this.debounceMs = source["debounceMs"];
+	    }
+	}
+	export class TimeoutsDTO {
+	    connectSeconds: number;
+	    firstTokenSeconds: number;
+	    idleSeconds: number;
+	    captureSeconds: number;
+	    ocrSeconds: number;
+	    translateSeconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TimeoutsDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connectSeconds = source["connectSeconds"];
+	        this.firstTokenSeconds = source["firstTokenSeconds"];
+	        this.idleSeconds = source["idleSeconds"];
+	        this.captureSeconds = source["captureSeconds"];
+	        this.ocrSeconds = source["ocrSeconds"];
+	        this.translateSeconds = source["translateSeconds"];
+	    }
+	}
 	export class SettingsDTO {
 	    apiKeyOverride: string;
 	    autoCopyResult: boolean;
@@ -7,7 +463,16 @@ export namespace main {
 	    theme: string;
 	    showToastOnComplete: boolean;
 	    enableStreamOutput: boolean;
+	    pipelineTranslation: boolean;
+	    showReasoning: boolean;
+	    autoContinue: boolean;
+	    maxContinuations: number;
+	    detectRefusals: boolean;
+	    fallbackOnBlocked: boolean;
 	    hotkeyCombination: string;
+	    repeatHotkeyCombination: string;
+	    captureRegions: CaptureRegionDTO[];
+	    watch: WatchDTO;
 	    extractPrompt: string;
 	    translatePrompt: string;
 	    apiBaseUrl: string;
@@ -15,9 +480,21 @@ export namespace main {
 	    visionModel: string;
 	    visionApiBaseUrl: string;
 	    visionApiKeyOverride: string;
+	    translateProvider: string;
+	    visionProvider: string;
 	    useVisionForTranslation: boolean;
+	    layoutBlocks: boolean;
 	    sourceLanguage: string;
 	    targetLanguage: string;
+	    captureBackend: string;
+	    modelPrices: {[key: string]: ModelPriceDTO};
+	    budget: BudgetDTO;
+	    cache: CacheDTO;
+	    translateFallbacks: FallbackEndpointDTO[];
+	    visionFallbacks: FallbackEndpointDTO[];
+	    sampling: SamplingDTO;
+	    timeouts: TimeoutsDTO;
+	    image: ImageDTO;
 	
 	    static createFrom(source: any = {}) {
 	        return new SettingsDTO(source);
@@ -31,7 +508,16 @@ export namespace main {
 	        this.theme = source["theme"];
 	        this.showToastOnComplete = source["showToastOnComplete"];
 	        this.enableStreamOutput = source["enableStreamOutput"];
+	        this.pipelineTranslation = source["pipelineTranslation"];
+	        this.showReasoning = source["showReasoning"];
+	        this.autoContinue = source["autoContinue"];
+	        this.maxContinuations = source["maxContinuations"];
+	        this.detectRefusals = source["detectRefusals"];
+	        this.fallbackOnBlocked = source["fallbackOnBlocked"];
 	        this.hotkeyCombination = source["hotkeyCombination"];
+	        this.repeatHotkeyCombination = source["repeatHotkeyCombination"];
+	        this.captureRegions = this.convertValues(source["captureRegions"], CaptureRegionDTO);
+	        this.watch = this.convertValues(source["watch"], WatchDTO);
 	        this.extractPrompt = source["extractPrompt"];
 	        this.translatePrompt = source["translatePrompt"];
 	        this.apiBaseUrl = source["apiBaseUrl"];
@@ -39,11 +525,144 @@ export namespace main {
 	        this.visionModel = source["visionModel"];
 	        this.visionApiBaseUrl = source["visionApiBaseUrl"];
 	        this.visionApiKeyOverride = source["visionApiKeyOverride"];
+	        this.translateProvider = source["translateProvider"];
+	        this.visionProvider = source["visionProvider"];
 	        this.useVisionForTranslation = source["useVisionForTranslation"];
+	        this.layoutBlocks = source["layoutBlocks"];
 	        this.sourceLanguage = source["sourceLanguage"];
 	        this.targetLanguage = source["targetLanguage"];
+	        this.captureBackend = source["captureBackend"];
+	        this.modelPrices = this.convertValues(source["modelPrices"], ModelPriceDTO, true);
+	        this.budget = this.convertValues(source["budget"], BudgetDTO);
+	        this.cache = this.convertValues(source["cache"], CacheDTO);
+	        this.translateFallbacks = this.convertValues(source["translateFallbacks"], FallbackEndpointDTO);
+	        this.visionFallbacks = this.convertValues(source["visionFallbacks"], FallbackEndpointDTO);
+	        this.sampling = this.convertValues(source["sampling"], SamplingDTO);
+	        this.timeouts = this.convertValues(source["timeouts"], TimeoutsDTO);
+	        this.image = this.convertValues(source["image"], ImageDTO);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class UsageModelDTO {
+	    endpoint: string;
+	    model: string;
+	    requests: number;
+	    promptTokens: number;
+	    completionTokens: number;
+	    totalTokens: number;
+	    cost: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new UsageModelDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.endpoint = source["endpoint"];
+	        this.model = source["model"];
+	        this.requests = source["requests"];
+	        this.promptTokens = source["promptTokens"];
+	        this.completionTokens = source["completionTokens"];
+	        this.totalTokens = source["totalTokens"];
+	        this.cost = source["cost"];
 	    }
 	}
+	export class UsagePeriodDTO {
+	    // Go type: time
+	    from: any;
+	    // Go type: time
+	    to: any;
+	    requests: number;
+	    promptTokens: number;
+	    completionTokens: number;
+	    totalTokens: number;
+	    cost: number;
+	    missingUsage: number;
+	    models: UsageModelDTO[];
+	
+	    static createFrom(source: any = {}) {
+	        return new UsagePeriodDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.from = this.convertValues(source["from"], null);
+	        this.to = this.convertValues(source["to"], null);
+	        this.requests = source["requests"];
+	        this.promptTokens = source["promptTokens"];
+	        this.completionTokens = source["completionTokens"];
+	        this.totalTokens = source["totalTokens"];
+	        this.cost = source["cost"];
+	        this.missingUsage = source["missingUsage"];
+	        this.models = this.convertValues(source["models"], UsageModelDTO);
+	    }
 
-}
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class UsageReportDTO {
+	    today: UsagePeriodDTO;
+	    month: UsagePeriodDTO;
+	
+	    static createFrom(source: any = {}) {
+	        return new UsageReportDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.today = this.convertValues(source["today"], UsagePeriodDTO);
+	        this.month = this.convertValues(source["month"], UsagePeriodDTO);
+	    }
 
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"embed"
 
+	"Translater/core/display"
+
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
@@ -12,6 +14,8 @@ import (
 var assets embed.FS
 
 func main() {
+	// 在创建任何窗口之前声明按显示器感知 DPI，使鼠标钩子、截图与浮窗共用物理像素坐标
+	display.EnableDPIAwareness()
 	// Create an instance of the app structure
 	app := NewApp()
 	// Create application with options
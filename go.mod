@@ -3,15 +3,16 @@ module Translater
 go 1.24.0
 
 require (
+	github.com/jezek/xgb v1.1.1
 	github.com/kbinani/screenshot v0.0.0-20250624051815-089614a94018
 	github.com/lxn/win v0.0.0-20210218163916-a377121e959e
 	github.com/robotn/gohook v0.42.2
+	golang.org/x/image v0.24.0
 )
 
 require (
 	github.com/gen2brain/shm v0.1.1 // indirect
 	github.com/godbus/dbus/v5 v5.1.0 // indirect
-	github.com/jezek/xgb v1.1.1 // indirect
 	github.com/vcaesar/keycode v0.10.1 // indirect
 	golang.org/x/sys v0.36.0 // indirect
 )
@@ -4,73 +4,48 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"strings"
+	"path/filepath"
 
 	"Translater/core/ai"
+	"Translater/core/cache"
 	"Translater/core/config"
+	"Translater/core/display"
 	"Translater/core/hotkey"
 	"Translater/core/screenshot"
+	"Translater/core/setup"
 	"Translater/core/translation"
+	"Translater/core/usage"
 )
 
 func main() {
-	// 创建API密钥读取器
-	envFiles := []string{".env", "env"}
-	apiKeyReader := config.NewFileAPIKeyReader(envFiles)
+	// 统一使用物理像素坐标，避免混合缩放的多显示器下截图区域偏移
+	display.EnableDPIAwareness()
 
 	settings := config.DefaultSettings()
+	var ledger *usage.Ledger
+	var resultCache *cache.Cache
 	if manager, err := config.NewSettingsManager("Translater"); err != nil {
 		log.Printf("failed to resolve settings path: %v", err)
-	} else if loaded, err := manager.Load(); err != nil {
-		log.Printf("failed to load settings, using defaults: %v", err)
 	} else {
-		settings = loaded
-	}
-
-	// 解析主 API Key（视觉 API Key 优先）
-	mainKey := strings.TrimSpace(settings.VisionAPIKeyOverride)
-	if mainKey == "" {
-		// 向后兼容：回退到 apiKeyOverride
-		mainKey = strings.TrimSpace(settings.APIKeyOverride)
-	}
-	if mainKey == "" {
-		// 最后尝试从文件读取
-		var err error
-		mainKey, err = apiKeyReader.ReadAPIKey()
-		if err != nil || mainKey == "" {
-			log.Fatal("需要配置视觉 API Key (visionApiKeyOverride) 或在 .env 文件中设置")
-		}
-		fmt.Println("Successfully read API key from file")
-	} else {
-		if strings.TrimSpace(settings.VisionAPIKeyOverride) != "" {
-			fmt.Println("Using vision API key from settings")
+		if loaded, err := manager.Load(); err != nil {
+			log.Printf("failed to load settings, using defaults: %v", err)
 		} else {
-			fmt.Println("Using API key from settings override")
+			settings = loaded
 		}
-	}
-
-	// 解析翻译 API Key
-	var translateKey string
-	if settings.UseVisionForTranslation {
-		// 视觉直出模式：翻译也用主 key
-		translateKey = mainKey
-		fmt.Println("Vision-only mode: using vision API key for translation")
-	} else {
-		// 文本模型模式：翻译 key 可选，留空则回退到主 key
-		translateKey = strings.TrimSpace(settings.APIKeyOverride)
-		if translateKey == "" {
-			translateKey = mainKey
-			fmt.Println("Text model mode: translation API key falls back to vision API key")
-		} else {
-			fmt.Println("Text model mode: using separate translation API key")
+		if ledger, err = usage.NewLedger(manager.Dir()); err != nil {
+			log.Printf("failed to load usage ledger: %v", err)
+		}
+		if !settings.Cache.Disabled {
+			if resultCache, err = cache.New(filepath.Join(manager.Dir(), cache.DirName), settings.Cache.Options()); err != nil {
+				log.Printf("failed to open result cache: %v", err)
+			}
 		}
 	}
 
-	// 视觉 API 配置
-	visionAPIKey := mainKey
-	visionBaseURL := strings.TrimSpace(settings.VisionAPIBaseURL)
-	if visionBaseURL == "" {
-		visionBaseURL = settings.APIBaseURL
+	// 与桌面应用共用同一套装配逻辑，避免两个入口的配置不一致
+	clientConfig, err := setup.ClientConfig(settings)
+	if err != nil {
+		log.Fatal(err)
 	}
 
 	// 创建热键管理器
@@ -79,28 +54,36 @@ func main() {
 	// 创建截图管理器（只创建一次）
 	screenshotManager := screenshot.NewManager()
 
+	if ledger != nil {
+		ledger.SetPrices(settings.ModelPrices)
+		clientConfig.OnUsage = ledger.Observe
+	}
+
 	// 创建AI客户端
-	aiClient := ai.NewClient(ai.ClientConfig{
-		APIKey:         translateKey,
-		BaseURL:        settings.APIBaseURL,
-		TranslateModel: settings.TranslateModel,
-		VisionModel:    settings.VisionModel,
-		VisionAPIKey:   visionAPIKey,
-		VisionBaseURL:  visionBaseURL,
-	})
+	aiClient := ai.NewClient(clientConfig)
+
+	// 创建截图后端
+	capturer, err := screenshot.NewCapturer(settings.CaptureBackend)
+	if err != nil {
+		log.Printf("截图后端 %s 初始化失败，使用默认后端: %v", settings.CaptureBackend, err)
+		capturer = screenshot.DefaultCapturer
+	}
 
 	// 创建翻译服务
 	translationService := translation.NewService(
 		aiClient,
+		capturer,
 		settings.ExtractPrompt,
 		settings.TranslatePrompt,
-		translation.Options{
-			Stream:                  settings.EnableStreamOutput,
-			UseVisionForTranslation: settings.UseVisionForTranslation,
-			SourceLanguage:          settings.SourceLanguage,
-			TargetLanguage:          settings.TargetLanguage,
-		},
+		setup.TranslationOptions(settings),
 	)
+	translationService.SetUsageLedger(ledger)
+	translationService.SetResultCache(resultCache)
+	translationService.SetBudgetWarningHandler(func(warnings []usage.BudgetWarning) {
+		for _, warning := range warnings {
+			fmt.Printf("用量提醒: %s\n", warning.Message())
+		}
+	})
 
 	// 设置截图处理函数
 	screenshotManager.SetCaptureHandler(func(ctx context.Context, startX, startY, endX, endY int) bool {